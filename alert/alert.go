@@ -0,0 +1,149 @@
+package oopsalert
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// Alert is the payload routed to sinks.
+type Alert struct {
+	Message     string             `json:"message"`
+	Public      string             `json:"public,omitempty"`
+	Code        string             `json:"code,omitempty"`
+	Hint        string             `json:"hint,omitempty"`
+	Trace       string             `json:"trace,omitempty"`
+	Domain      string             `json:"domain,omitempty"`
+	Owner       string             `json:"owner,omitempty"`
+	Severity    oops.SeverityLevel `json:"severity,omitempty"`
+	Fingerprint string             `json:"fingerprint"`
+	Time        time.Time          `json:"time"`
+}
+
+// Sink delivers alerts (pager, chat, webhook...).
+type Sink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// SinkFunc is an adapter to use a function as a Sink.
+type SinkFunc func(ctx context.Context, alert Alert) error
+
+// Send calls f(ctx, alert).
+func (f SinkFunc) Send(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+var mutex sync.RWMutex
+var ownerSinks = map[string][]Sink{}
+var domainSinks = map[string][]Sink{}
+
+// RegisterOwner routes the errors of an owner (see `oops.Owner()`) to sinks.
+//
+//	oopsalert.RegisterOwner("billing-team@acme.org", &oopsalert.PagerDuty{RoutingKey: "..."})
+func RegisterOwner(owner string, sinks ...Sink) {
+	mutex.Lock()
+	ownerSinks[owner] = append(ownerSinks[owner], sinks...)
+	mutex.Unlock()
+}
+
+// RegisterDomain routes the errors of a domain (see `oops.In()`) to sinks.
+//
+//	oopsalert.RegisterDomain("billing", oopsalert.Slack("https://hooks.slack.com/services/..."))
+func RegisterDomain(domain string, sinks ...Sink) {
+	mutex.Lock()
+	domainSinks[domain] = append(domainSinks[domain], sinks...)
+	mutex.Unlock()
+}
+
+// Reset unregisters all sinks.
+func Reset() {
+	mutex.Lock()
+	ownerSinks = map[string][]Sink{}
+	domainSinks = map[string][]Sink{}
+	mutex.Unlock()
+}
+
+// Notify sends err to the sinks registered for its owner and its domain.
+// See NotifyContext.
+func Notify(err error) error {
+	return NotifyContext(context.Background(), err)
+}
+
+// NotifyContext sends err to the sinks registered for its owner and its domain.
+// A sink registered for both is notified once (unless it is a SinkFunc). Errors that are not `oops.OopsError`,
+// or without matching sink, are ignored. Sink failures are joined.
+func NotifyContext(ctx context.Context, err error) error {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return nil
+	}
+
+	sinks := lookup(oopsError.Owner(), oopsError.Domain())
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	alert := ToAlert(oopsError)
+
+	errs := []error{}
+	for _, sink := range sinks {
+		if e := sink.Send(ctx, alert); e != nil {
+			errs = append(errs, e)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ToAlert returns the alert of an error.
+func ToAlert(err oops.OopsError) Alert {
+	return Alert{
+		Message:     err.Error(),
+		Public:      err.Public(),
+		Code:        err.Code(),
+		Hint:        err.Hint(),
+		Trace:       err.Trace(),
+		Domain:      err.Domain(),
+		Owner:       err.Owner(),
+		Severity:    err.Severity(),
+		Fingerprint: err.Fingerprint(),
+		Time:        err.Time(),
+	}
+}
+
+func lookup(owner string, domain string) []Sink {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	sinks := []Sink{}
+	seen := map[Sink]struct{}{}
+
+	add := func(candidates []Sink) {
+		for _, sink := range candidates {
+			// funcs cannot be used as map keys
+			if !reflect.TypeOf(sink).Comparable() {
+				sinks = append(sinks, sink)
+				continue
+			}
+
+			if _, ok := seen[sink]; ok {
+				continue
+			}
+			seen[sink] = struct{}{}
+			sinks = append(sinks, sink)
+		}
+	}
+
+	if owner != "" {
+		add(ownerSinks[owner])
+	}
+	if domain != "" {
+		add(domainSinks[domain])
+	}
+
+	return sinks
+}
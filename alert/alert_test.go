@@ -0,0 +1,127 @@
+package oopsalert
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotify(t *testing.T) {
+	is := assert.New(t)
+	defer Reset()
+
+	received := map[string][]Alert{}
+	sink := func(name string) Sink {
+		return SinkFunc(func(ctx context.Context, alert Alert) error {
+			received[name] = append(received[name], alert)
+			return nil
+		})
+	}
+
+	billing := &Webhook{URL: "unused", Body: func(a Alert) any { return a }}
+	RegisterOwner("billing-team@acme.org", sink("owner"))
+	RegisterDomain("payment", sink("domain"))
+
+	err := oops.
+		Owner("billing-team@acme.org").
+		In("payment").
+		Code("card_declined").
+		Hint("retry with another card").
+		Public("Your card was declined.").
+		Trace("1234").
+		Errorf("stripe: card declined")
+
+	is.NoError(Notify(err))
+	is.Len(received["owner"], 1)
+	is.Len(received["domain"], 1)
+
+	alert := received["owner"][0]
+	is.Equal("stripe: card declined", alert.Message)
+	is.Equal("Your card was declined.", alert.Public)
+	is.Equal("card_declined", alert.Code)
+	is.Equal("retry with another card", alert.Hint)
+	is.Equal("1234", alert.Trace)
+	is.Equal("payment", alert.Domain)
+	is.Equal("billing-team@acme.org", alert.Owner)
+	is.NotEmpty(alert.Fingerprint)
+
+	// no matching sink
+	is.NoError(Notify(oops.Owner("someone-else").Errorf("boom")))
+	is.NoError(Notify(errors.New("boom")))
+	is.Len(received["owner"], 1)
+
+	// sinks registered twice are notified once
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls++ }))
+	defer server.Close()
+
+	billing.URL = server.URL
+	RegisterOwner("billing-team@acme.org", billing)
+	RegisterDomain("payment", billing)
+	is.NoError(Notify(err))
+	is.Equal(1, calls)
+
+	// failures are returned
+	RegisterDomain("payment", SinkFunc(func(ctx context.Context, alert Alert) error { return errors.New("unavailable") }))
+	is.EqualError(Notify(err), "unavailable")
+}
+
+func TestPagerDuty(t *testing.T) {
+	is := assert.New(t)
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		is.NoError(json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := &PagerDuty{RoutingKey: "key", Endpoint: server.URL}
+
+	err := oops.
+		In("payment").
+		Code("card_declined").
+		Hint("retry with another card").
+		Severity(oops.SeverityFatal).
+		Trace("1234").
+		Errorf("stripe: card declined")
+
+	is.NoError(sink.Send(context.Background(), ToAlert(err.(oops.OopsError))))
+	is.Equal("key", received["routing_key"])
+	is.Equal("trigger", received["event_action"])
+	is.Equal(err.(oops.OopsError).Fingerprint(), received["dedup_key"])
+
+	payload := received["payload"].(map[string]any)
+	is.Equal("[payment] stripe: card declined", payload["summary"])
+	is.Equal("critical", payload["severity"])
+	is.Equal("oops", payload["source"])
+	is.Equal("card_declined", payload["class"])
+	is.Equal(map[string]any{"message": "stripe: card declined", "code": "card_declined", "hint": "retry with another card", "trace": "1234"}, payload["custom_details"])
+}
+
+func TestSlack(t *testing.T) {
+	is := assert.New(t)
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		is.NoError(json.Unmarshal(body, &received))
+	}))
+	defer server.Close()
+
+	err := oops.In("payment").Code("card_declined").Trace("1234").Errorf("stripe: card declined")
+
+	is.NoError(Slack(server.URL).Send(context.Background(), ToAlert(err.(oops.OopsError))))
+	is.Equal(map[string]any{"text": "*[payment] stripe: card declined*\nCode: `card_declined`\nTrace: `1234`"}, received)
+
+	server.Close()
+	is.Error(Slack(server.URL).Send(context.Background(), ToAlert(err.(oops.OopsError))))
+}
@@ -0,0 +1,173 @@
+package oopsalert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// PagerDutyEndpoint is the PagerDuty Events API v2.
+const PagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty triggers incidents with the PagerDuty Events API v2. Alerts with
+// the same fingerprint are deduplicated into the same incident.
+type PagerDuty struct {
+	// RoutingKey is the integration key of the PagerDuty service.
+	RoutingKey string
+	// Source of the events (eg: hostname). Defaults to "oops".
+	Source string
+	// Endpoint defaults to PagerDutyEndpoint.
+	Endpoint string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Sink = (*PagerDuty)(nil)
+
+// Send triggers a PagerDuty event.
+func (p *PagerDuty) Send(ctx context.Context, alert Alert) error {
+	source := p.Source
+	if source == "" {
+		source = "oops"
+	}
+
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = PagerDutyEndpoint
+	}
+
+	details := map[string]any{"message": alert.Message}
+	for k, v := range map[string]string{"public": alert.Public, "code": alert.Code, "hint": alert.Hint, "trace": alert.Trace, "owner": alert.Owner} {
+		if v != "" {
+			details[k] = v
+		}
+	}
+
+	event := map[string]any{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    alert.Fingerprint,
+		"payload": map[string]any{
+			"summary":        summary(alert),
+			"source":         source,
+			"severity":       pagerDutySeverity(alert.Severity),
+			"timestamp":      alert.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+			"group":          alert.Domain,
+			"class":          alert.Code,
+			"custom_details": details,
+		},
+	}
+
+	return post(ctx, p.HTTPClient, endpoint, nil, event)
+}
+
+// Webhook posts alerts as JSON to an url.
+type Webhook struct {
+	URL string
+	// Header is added to the requests (eg: authorization).
+	Header http.Header
+	// Body returns the JSON body of the request. Defaults to the alert.
+	Body func(Alert) any
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+var _ Sink = (*Webhook)(nil)
+
+// Send posts the alert.
+func (w *Webhook) Send(ctx context.Context, alert Alert) error {
+	var body any = alert
+	if w.Body != nil {
+		body = w.Body(alert)
+	}
+
+	return post(ctx, w.HTTPClient, w.URL, w.Header, body)
+}
+
+// Slack returns a webhook posting alerts to a Slack incoming webhook.
+func Slack(url string) *Webhook {
+	return &Webhook{
+		URL:  url,
+		Body: SlackMessage,
+	}
+}
+
+// SlackMessage formats an alert as a Slack message.
+func SlackMessage(alert Alert) any {
+	lines := []string{"*" + summary(alert) + "*"}
+	for _, field := range []struct{ name, value string }{
+		{"Code", alert.Code},
+		{"Public", alert.Public},
+		{"Hint", alert.Hint},
+		{"Owner", alert.Owner},
+		{"Trace", alert.Trace},
+	} {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("%s: `%s`", field.name, field.value))
+		}
+	}
+
+	return map[string]any{"text": strings.Join(lines, "\n")}
+}
+
+func summary(alert Alert) string {
+	if alert.Domain != "" {
+		return "[" + alert.Domain + "] " + alert.Message
+	}
+
+	return alert.Message
+}
+
+func pagerDutySeverity(severity oops.SeverityLevel) string {
+	switch severity {
+	case oops.SeverityDebug, oops.SeverityInfo:
+		return "info"
+	case oops.SeverityWarning:
+		return "warning"
+	case oops.SeverityFatal:
+		return "critical"
+	default:
+		return "error"
+	}
+}
+
+func post(ctx context.Context, httpClient *http.Client, url string, header http.Header, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("oopsalert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("oopsalert: %w", err)
+	}
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("oopsalert: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("oopsalert: unexpected status %d: %s", res.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
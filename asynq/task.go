@@ -0,0 +1,131 @@
+package oopsasynq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/hibiken/asynq"
+	"github.com/samber/oops"
+)
+
+// PayloadRedactedKeys lists the keys (case insensitive) of JSON payloads whose
+// values are replaced by `[REDACTED]`. Global keys of `oops.RedactedKeys` apply too.
+var PayloadRedactedKeys = []string{"password", "secret", "token", "api_key"}
+
+// Wrap returns a handler turning the errors and the panics of handler into oops
+// errors carrying the task type, id, queue, attempt and payload (see FromTask).
+// Errors that are not retryable (see `oops.OopsError.Retryable()`) also match
+// `asynq.SkipRetry`, so that asynq archives the task immediately.
+//
+// Wrap can be used as a middleware:
+//
+//	mux := asynq.NewServeMux()
+//	mux.Use(oopsasynq.Wrap)
+func Wrap(handler asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		builder := FromTask(ctx, task)
+
+		var err error
+		recovered := builder.Recoverf(func() {
+			err = handler.ProcessTask(ctx, task)
+		}, "asynq: panic recovered")
+
+		switch {
+		case recovered != nil:
+			err = recovered
+		case err != nil:
+			err = builder.Wrap(err)
+		default:
+			return nil
+		}
+
+		if oopsErr, ok := oops.AsOops(err); ok && !oopsErr.Retryable() {
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+
+		return err
+	})
+}
+
+// ErrorHandlerOptions configures the error handler.
+type ErrorHandlerOptions struct {
+	// Logger is called with the errors of tasks. Defaults to `slog.Default()`.
+	Logger func(ctx context.Context, task *asynq.Task, err error)
+}
+
+// ErrorHandler returns an `asynq.ErrorHandler` logging the errors of tasks with
+// `slog.Default()`, as oops errors carrying the task attributes (see FromTask).
+//
+//	srv := asynq.NewServer(redis, asynq.Config{
+//		ErrorHandler: oopsasynq.ErrorHandler(),
+//	})
+func ErrorHandler() asynq.ErrorHandler {
+	return ErrorHandlerWith(ErrorHandlerOptions{})
+}
+
+// ErrorHandlerWith returns an `asynq.ErrorHandler` with custom options.
+//
+//	oopsasynq.ErrorHandlerWith(oopsasynq.ErrorHandlerOptions{
+//		Logger: func(ctx context.Context, task *asynq.Task, err error) {
+//			logger.Error(err.Error(), zap.Error(err))
+//		},
+//	})
+func ErrorHandlerWith(opts ErrorHandlerOptions) asynq.ErrorHandler {
+	logger := opts.Logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	return asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
+		// errors of Wrap already carry the task attributes
+		if _, ok := oops.AsOops(err); !ok {
+			err = FromTask(ctx, task).Wrap(err)
+		}
+
+		logger(ctx, task, err)
+	})
+}
+
+// FromTask returns a builder with the type (`task_type`) and the payload
+// (`task_payload`) of a task, and with its id (`task_id`), queue (`task_queue`),
+// attempt (`task_attempt`, starting at 1) and max retry (`task_max_retry`) when
+// ctx is the context of a task being processed. JSON object payloads are decoded,
+// and values of PayloadRedactedKeys are redacted. Other payloads are reported
+// by size (`task_payload_size`).
+func FromTask(ctx context.Context, task *asynq.Task) oops.OopsErrorBuilder {
+	builder := oops.
+		FromContext(ctx).
+		RedactKeys(PayloadRedactedKeys...).
+		With("task_type", task.Type())
+
+	if id, ok := asynq.GetTaskID(ctx); ok {
+		builder = builder.With("task_id", id)
+	}
+
+	if queue, ok := asynq.GetQueueName(ctx); ok {
+		builder = builder.With("task_queue", queue)
+	}
+
+	if retried, ok := asynq.GetRetryCount(ctx); ok {
+		builder = builder.With("task_attempt", retried+1)
+	}
+
+	if maxRetry, ok := asynq.GetMaxRetry(ctx); ok {
+		builder = builder.With("task_max_retry", maxRetry)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(task.Payload(), &payload); err == nil {
+		builder = builder.With("task_payload", payload)
+	} else if len(task.Payload()) > 0 {
+		builder = builder.With("task_payload_size", len(task.Payload()))
+	}
+
+	return builder
+}
+
+func defaultLogger(ctx context.Context, task *asynq.Task, err error) {
+	slog.Default().ErrorContext(ctx, err.Error(), slog.Any("error", err))
+}
@@ -0,0 +1,76 @@
+package oopsasynq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromTask(t *testing.T) {
+	is := assert.New(t)
+
+	task := asynq.NewTask("email:send", []byte(`{"to":"john@example.com","token":"secret"}`))
+	err := FromTask(context.Background(), task).Errorf("smtp unavailable").(oops.OopsError)
+	is.Equal("email:send", err.Context()["task_type"])
+	is.Equal(map[string]any{"to": "john@example.com", "token": "secret"}, err.Context()["task_payload"])
+	is.NotContains(err.Context(), "task_attempt")
+
+	payload := err.ToMap()["context"].(map[string]any)["task_payload"]
+	is.Equal(map[string]any{"to": "john@example.com", "token": oops.RedactedValue}, payload)
+
+	task = asynq.NewTask("email:send", []byte("binary"))
+	err = FromTask(context.Background(), task).Errorf("smtp unavailable").(oops.OopsError)
+	is.Equal(6, err.Context()["task_payload_size"])
+	is.NotContains(err.Context(), "task_payload")
+}
+
+func TestWrap(t *testing.T) {
+	is := assert.New(t)
+
+	task := asynq.NewTask("email:send", nil)
+
+	err := Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		return errors.New("smtp unavailable")
+	})).ProcessTask(context.Background(), task)
+	is.EqualError(err, "smtp unavailable")
+	is.False(errors.Is(err, asynq.SkipRetry))
+	oopsErr, ok := oops.AsOops(err)
+	is.True(ok)
+	is.Equal("email:send", oopsErr.Context()["task_type"])
+
+	err = Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		return oops.Retryable(false).Errorf("invalid address")
+	})).ProcessTask(context.Background(), task)
+	is.True(errors.Is(err, asynq.SkipRetry))
+	_, ok = oops.AsOops(err)
+	is.True(ok)
+
+	err = Wrap(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		panic("oh no")
+	})).ProcessTask(context.Background(), task)
+	is.ErrorContains(err, "oh no")
+	oopsErr, ok = oops.AsOops(err)
+	is.True(ok)
+	is.Equal("email:send", oopsErr.Context()["task_type"])
+}
+
+func TestErrorHandler(t *testing.T) {
+	is := assert.New(t)
+
+	var logged error
+	handler := ErrorHandlerWith(ErrorHandlerOptions{
+		Logger: func(ctx context.Context, task *asynq.Task, err error) {
+			logged = err
+		},
+	})
+
+	handler.HandleError(context.Background(), asynq.NewTask("email:send", nil), errors.New("smtp unavailable"))
+	is.EqualError(logged, "smtp unavailable")
+	oopsErr, ok := oops.AsOops(logged)
+	is.True(ok)
+	is.Equal("email:send", oopsErr.Context()["task_type"])
+}
@@ -0,0 +1,155 @@
+package oops
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Errorf("could not fetch user")
+	}
+}
+
+func BenchmarkErrorfWithoutStacktrace(b *testing.B) {
+	builder := WithoutStacktrace()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = builder.Errorf("could not fetch user")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	err := errors.New("connection refused")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Wrap(err)
+	}
+}
+
+func BenchmarkWrapWithContext(b *testing.B) {
+	err := errors.New("connection refused")
+	ctx := context.WithValue(context.Background(), "request_id", "req-123") //nolint:staticcheck
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = WithContext(ctx, "request_id").
+			With("user_id", 1234, "query", "SELECT 1").
+			User("user-123", "email", "foo@bar.com").
+			Wrapf(err, "could not fetch user")
+	}
+}
+
+func benchmarkError() OopsError {
+	return In("repository").
+		Code("db_unavailable").
+		Trace("trace-id").
+		With("query", "SELECT 1", "duration_ms", 42).
+		User("user-123", "email", "foo@bar.com").
+		Wrapf(errors.New("connection refused"), "could not fetch user").(OopsError)
+}
+
+func BenchmarkToMap(b *testing.B) {
+	err := benchmarkError()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.ToMap()
+	}
+}
+
+func BenchmarkLogValuer(b *testing.B) {
+	err := benchmarkError()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.LogValuer()
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	err := benchmarkError()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = err.MarshalJSON()
+	}
+}
+
+func BenchmarkStacktrace(b *testing.B) {
+	err := benchmarkError()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		// bypass the cache of err.Stacktrace()
+		_ = err.stacktraceString()
+	}
+}
+
+// allocationBudgets are the max allocations per call of the core paths. They
+// leave some headroom: a budget is raised only with a good reason.
+var allocationBudgets = []struct {
+	name   string
+	budget float64
+	run    func() func()
+}{
+	{"Errorf", 16, func() func() {
+		return func() { _ = Errorf("could not fetch user") }
+	}},
+	{"ErrorfWithoutStacktrace", 8, func() func() {
+		builder := WithoutStacktrace()
+		return func() { _ = builder.Errorf("could not fetch user") }
+	}},
+	{"WrapWithContext", 24, func() func() {
+		err := errors.New("connection refused")
+		ctx := context.WithValue(context.Background(), "request_id", "req-123") //nolint:staticcheck
+		return func() {
+			_ = WithContext(ctx, "request_id").With("user_id", 1234).User("user-123", "email", "foo@bar.com").Wrap(err)
+		}
+	}},
+	{"BuilderChain", 24, func() func() {
+		err := errors.New("connection refused")
+		return func() {
+			_ = In("repository").Tags("database").Code("db_unavailable").Trace("trace-id").Hint("hint").Owner("owner").
+				With("query", "SELECT 1").User("user-123", "email", "foo@bar.com").Tenant("tenant-123").WithoutStacktrace().Wrap(err)
+		}
+	}},
+	{"ToMap", 36, func() func() {
+		err := benchmarkError()
+		return func() { _ = err.ToMap() }
+	}},
+	{"LogValuer", 36, func() func() {
+		err := benchmarkError()
+		return func() { _ = err.LogValuer() }
+	}},
+	{"Context", 4, func() func() {
+		err := benchmarkError()
+		return func() { _ = err.Context() }
+	}},
+	{"Error", 0, func() func() {
+		err := benchmarkError()
+		return func() { _ = err.Error() }
+	}},
+}
+
+// TestAllocationBudgets depends on the Go version and on the dependencies: it
+// runs only when OOPS_ALLOCATION_BUDGETS is set (see `make test-allocs`).
+func TestAllocationBudgets(t *testing.T) {
+	if os.Getenv("OOPS_ALLOCATION_BUDGETS") == "" {
+		t.Skip("set OOPS_ALLOCATION_BUDGETS=1 to check allocation budgets")
+	}
+
+	is := assert.New(t)
+
+	for _, tc := range allocationBudgets {
+		allocs := testing.AllocsPerRun(100, tc.run())
+		is.LessOrEqual(allocs, tc.budget, tc.name)
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime"
 	"time"
 
-	"github.com/oklog/ulid/v2"
 	"github.com/samber/lo"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -40,7 +40,7 @@ func new() OopsErrorBuilder {
 		err:      nil,
 		msg:      "",
 		code:     "",
-		time:     time.Now(),
+		time:     now(),
 		duration: 0,
 
 		// context
@@ -51,9 +51,10 @@ func new() OopsErrorBuilder {
 		trace: "",
 		span:  "",
 
-		hint:   "",
-		public: "",
-		owner:  "",
+		hint:    "",
+		public:  "",
+		owner:   "",
+		actions: []RemediationAction{},
 
 		// user
 		userID:     "",
@@ -70,35 +71,244 @@ func new() OopsErrorBuilder {
 	}
 }
 
+// copy duplicates the builder. Error, message and stacktrace are kept, so that an
+// `oops.OopsError` converted to a builder (eg: in a hook) can be altered.
+// Attribute maps and slices are shared: builder methods never mutate them, and
+// replace them with a copy instead (see cloneAttributes).
 func (o OopsErrorBuilder) copy() OopsErrorBuilder {
 	return OopsErrorBuilder{
-		// err:      err,
-		// msg:      o.msg,
+		err:      o.err,
+		msg:      o.msg,
+		format:   o.format,
 		code:     o.code,
+		severity: o.severity,
 		time:     o.time,
 		duration: o.duration,
 
-		domain:  o.domain,
-		tags:    o.tags,
-		context: lo.Assign(map[string]any{}, o.context),
+		domain:         o.domain,
+		tags:           o.tags,
+		context:        o.context,
+		contextParents: o.contextParents,
 
 		trace: o.trace,
 		span:  o.span,
 
-		hint:   o.hint,
-		public: o.public,
-		owner:  o.owner,
+		hint:    o.hint,
+		public:  o.public,
+		owner:   o.owner,
+		actions: o.actions,
+
+		httpStatus: o.httpStatus,
+		retryable:  o.retryable,
+
+		fingerprint: o.fingerprint,
+		redactKeys:  o.redactKeys,
 
 		userID:     o.userID,
-		userData:   lo.Assign(map[string]any{}, o.userData),
+		userData:   o.userData,
 		tenantID:   o.tenantID,
-		tenantData: lo.Assign(map[string]any{}, o.tenantData),
+		tenantData: o.tenantData,
+
+		req:     o.req,
+		reqDump: o.reqDump,
+		res:     o.res,
+
+		stacktrace:         o.stacktrace,
+		stacktraceDepth:    o.stacktraceDepth,
+		stacktraceSkip:     o.stacktraceSkip,
+		stacktraceDisabled: o.stacktraceDisabled,
+		foreign:            o.foreign,
+		linked:             o.linked,
+
+		goroutines:        o.goroutines,
+		goroutinesEnabled: o.goroutinesEnabled,
+		goroutineID:       o.goroutineID,
+		pprofLabels:       o.pprofLabels,
+
+		translated: o.translated,
+		resolution: o.resolution,
+		panicKind:  o.panicKind,
+
+		extractors: o.extractors,
+		deadline:   o.deadline,
+		replayed:   o.replayed,
+	}
+}
+
+// cloneAttributes copies a map of attributes before a write, with room for
+// `extra` new attributes.
+func cloneAttributes(attributes map[string]any, extra int) map[string]any {
+	clone := make(map[string]any, len(attributes)+extra)
+	for k, v := range attributes {
+		clone[k] = v
+	}
+	return clone
+}
+
+// merge returns a builder holding the attributes of both builders. Attributes
+// set by other win, maps are merged recursively, and tags, actions, redacted
+// keys and foreign stacktraces are appended. Disabling the stacktrace, or
+// enabling goroutines capture, in either builder applies. The time of o is
+// kept: it is the earliest builder.
+func (o OopsErrorBuilder) merge(other OopsErrorBuilder) OopsErrorBuilder {
+	o2 := o.copy()
+
+	for _, attr := range []struct {
+		dst *string
+		src string
+	}{
+		{&o2.msg, other.msg},
+		{&o2.format, other.format},
+		{&o2.code, other.code},
+		{&o2.domain, other.domain},
+		{&o2.trace, other.trace},
+		{&o2.span, other.span},
+		{&o2.hint, other.hint},
+		{&o2.public, other.public},
+		{&o2.owner, other.owner},
+		{&o2.fingerprint, other.fingerprint},
+		{&o2.userID, other.userID},
+		{&o2.tenantID, other.tenantID},
+	} {
+		if attr.src != "" {
+			*attr.dst = attr.src
+		}
+	}
+
+	if other.err != nil {
+		o2.err = other.err
+	}
+
+	if other.severity != "" {
+		o2.severity = other.severity
+	}
+
+	if other.duration != 0 {
+		o2.duration = other.duration
+	}
+
+	if other.httpStatus != 0 {
+		o2.httpStatus = other.httpStatus
+	}
+
+	if other.retryable != nil {
+		o2.retryable = other.retryable
+	}
 
-		req: o.req,
-		res: o.res,
+	if other.resolution != 0 {
+		o2.resolution = other.resolution
+	}
+
+	if other.req != nil {
+		o2.req = other.req
+		o2.reqDump = other.reqDump
+	}
+
+	if other.res != nil {
+		o2.res = other.res
+	}
+
+	if other.stacktraceDepth != 0 {
+		o2.stacktraceDepth = other.stacktraceDepth
+	}
+
+	if other.stacktraceSkip != 0 {
+		o2.stacktraceSkip = other.stacktraceSkip
+	}
+
+	if other.linked != nil {
+		o2.linked = other.linked
+	}
 
-		// stacktrace: o.stacktrace,
+	if other.panicKind != "" {
+		o2.panicKind = other.panicKind
 	}
+
+	o2.stacktraceDisabled = o.stacktraceDisabled || other.stacktraceDisabled
+	o2.goroutinesEnabled = o.goroutinesEnabled || other.goroutinesEnabled
+	o2.foreign = append(o.foreign[:len(o.foreign):len(o.foreign)], other.foreign...)
+	o2.pprofLabels = lo.Assign(o.pprofLabels, other.pprofLabels)
+
+	o2.tags = lo.Uniq(append(o.tags[:len(o.tags):len(o.tags)], other.tags...))
+	o2.actions = append(o.actions[:len(o.actions):len(o.actions)], other.actions...)
+	o2.redactKeys = append(o.redactKeys[:len(o.redactKeys):len(o.redactKeys)], other.redactKeys...)
+
+	// parent contexts are flattened
+	o2.context = mergeAttributes(OopsError(o).ownContext(), OopsError(other).ownContext())
+	o2.contextParents = nil
+	o2.userData = mergeAttributes(o.userData, other.userData)
+	o2.tenantData = mergeAttributes(o.tenantData, other.tenantData)
+
+	return o2
+}
+
+// mergeAttributes returns the attributes of both maps. Nested maps (eg: groups
+// set with WithGroup) are merged recursively, other values of src win.
+func mergeAttributes(dst map[string]any, src map[string]any) map[string]any {
+	output := lo.Assign(map[string]any{}, dst)
+
+	for k, v := range src {
+		if nested, ok := v.(map[string]any); ok {
+			if previous, ok := output[k].(map[string]any); ok {
+				output[k] = mergeAttributes(previous, nested)
+				continue
+			}
+		}
+
+		output[k] = v
+	}
+
+	return output
+}
+
+func (o OopsErrorBuilder) captureStacktrace() *oopsStacktrace {
+	if o.stacktraceDisabled {
+		return nil
+	}
+
+	sampled := StackTraceSampling > 1
+
+	if !shouldSampleStacktrace() {
+		// keep an empty stacktrace: its identity is used by errors.Is in strict mode
+		return &oopsStacktrace{span: o.span, frames: []oopsStacktraceFrame{}, sampled: true}
+	}
+
+	depth := StackTraceMaxDepth
+	if o.stacktraceDepth > 0 {
+		depth = o.stacktraceDepth
+	}
+
+	st := newStacktraceWithSkip(o.span, depth, o.stacktraceSkip)
+	st.sampled = sampled
+
+	return st
+}
+
+// captureStacks records the stack trace, and the goroutines when enabled.
+func (o *OopsErrorBuilder) captureStacks() {
+	o.stacktrace = o.captureStacktrace()
+
+	if o.goroutinesEnabled {
+		o.goroutines = captureGoroutines(GoroutinesMaxSize)
+	}
+
+	if CaptureGoroutineInfo {
+		o.goroutineID = currentGoroutineID()
+	}
+}
+
+// build runs the hooks on the error. Errors rebuilt by hooks get a new cache,
+// since builders do not copy it.
+func (o OopsErrorBuilder) build() error {
+	err := OopsError(o)
+	err.cache = &errorCache{}
+
+	err = runHooks(err)
+	if err.cache == nil {
+		err.cache = &errorCache{}
+	}
+
+	return err
 }
 
 // Wrap wraps an error into an `oops.OopsError` object that satisfies `error`
@@ -109,11 +319,11 @@ func (o OopsErrorBuilder) Wrap(err error) error {
 
 	o2 := o.copy()
 	o2.err = err
-	if o2.span == "" {
-		o2.span = ulid.Make().String()
+	if o2.span == "" && !LazySpanID {
+		o2.span = newID()
 	}
-	o2.stacktrace = newStacktrace(o2.span)
-	return OopsError(o2)
+	o2.captureStacks()
+	return o2.build()
 }
 
 // Wrapf wraps an error into an `oops.OopsError` object that satisfies `error` and formats an error message.
@@ -125,22 +335,50 @@ func (o OopsErrorBuilder) Wrapf(err error, format string, args ...any) error {
 	o2 := o.copy()
 	o2.err = err
 	o2.msg = fmt.Errorf(format, args...).Error()
-	if o2.span == "" {
-		o2.span = ulid.Make().String()
+	o2.format = format
+	if o2.span == "" && !LazySpanID {
+		o2.span = newID()
 	}
-	o2.stacktrace = newStacktrace(o2.span)
-	return OopsError(o2)
+	o2.captureStacks()
+	return o2.build()
 }
 
 // Errorf formats an error and returns `oops.OopsError` object that satisfies `error`.
 func (o OopsErrorBuilder) Errorf(format string, args ...any) error {
 	o2 := o.copy()
 	o2.err = fmt.Errorf(format, args...)
-	if o2.span == "" {
-		o2.span = ulid.Make().String()
+	o2.format = format
+	if o2.span == "" && !LazySpanID {
+		o2.span = newID()
 	}
-	o2.stacktrace = newStacktrace(o2.span)
-	return OopsError(o2)
+	o2.captureStacks()
+	return o2.build()
+}
+
+// WrapContext wraps an error into an `oops.OopsError` object that satisfies `error`.
+// Trace and span are extracted from the OpenTelemetry span found in the context.
+func (o OopsErrorBuilder) WrapContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return o.WithContext(ctx).Wrap(err)
+}
+
+// WrapfContext wraps an error into an `oops.OopsError` object that satisfies `error` and formats an error message.
+// Trace and span are extracted from the OpenTelemetry span found in the context.
+func (o OopsErrorBuilder) WrapfContext(ctx context.Context, err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	return o.WithContext(ctx).Wrapf(err, format, args...)
+}
+
+// ErrorfContext formats an error and returns `oops.OopsError` object that satisfies `error`.
+// Trace and span are extracted from the OpenTelemetry span found in the context.
+func (o OopsErrorBuilder) ErrorfContext(ctx context.Context, format string, args ...any) error {
+	return o.WithContext(ctx).Errorf(format, args...)
 }
 
 func (o OopsErrorBuilder) Join(e ...error) error {
@@ -151,11 +389,25 @@ func (o OopsErrorBuilder) Join(e ...error) error {
 func (o OopsErrorBuilder) Recover(cb func()) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(error); ok {
-				err = o.Wrap(e)
-			} else {
-				err = o.Wrap(fmt.Errorf("%v", r))
+			var panicNil *runtime.PanicNilError
+
+			o2 := o.copy()
+			payload, ok := r.(error)
+
+			switch {
+			case ok && errors.As(payload, &panicNil):
+				o2.panicKind = "nil"
+				if NilPanicAsFatal {
+					o2.severity = SeverityFatal
+				}
+			case ok:
+				o2.panicKind = "error"
+			default:
+				o2.panicKind = "value"
+				payload = fmt.Errorf("%v", r)
 			}
+
+			err = o2.Wrap(payload)
 		}
 	}()
 
@@ -197,8 +449,15 @@ func (o OopsErrorBuilder) Code(code string) OopsErrorBuilder {
 	return o2
 }
 
+// Severity set how critical the error is.
+func (o OopsErrorBuilder) Severity(severity SeverityLevel) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.severity = severity
+	return o2
+}
+
 // Time set the error time.
-// Default: `time.Now()`
+// Default: `time.Now()`, see SetClock
 func (o OopsErrorBuilder) Time(time time.Time) OopsErrorBuilder {
 	o2 := o.copy()
 	o2.time = time
@@ -208,7 +467,7 @@ func (o OopsErrorBuilder) Time(time time.Time) OopsErrorBuilder {
 // Since set the error duration.
 func (o OopsErrorBuilder) Since(t time.Time) OopsErrorBuilder {
 	o2 := o.copy()
-	o2.duration = time.Since(t)
+	o2.duration = now().Sub(t)
 	return o2
 }
 
@@ -229,13 +488,65 @@ func (o OopsErrorBuilder) In(domain string) OopsErrorBuilder {
 // Tags adds multiple tags, describing the feature returning an error.
 func (o OopsErrorBuilder) Tags(tags ...string) OopsErrorBuilder {
 	o2 := o.copy()
-	o2.tags = append(o2.tags, tags...)
+	o2.tags = append(o.tags[:len(o.tags):len(o.tags)], tags...)
 	return o2
 }
 
 // With supplies a list of attributes declared by pair of key+value.
 func (o OopsErrorBuilder) With(kv ...any) OopsErrorBuilder {
 	o2 := o.copy()
+	o2.context = cloneAttributes(o.context, len(kv)/2)
+
+	for i := 0; i < len(kv)-1; i += 2 {
+		k := kv[i]
+		v := kv[i+1]
+
+		if key, ok := k.(string); ok {
+			o2.context[key] = v
+		}
+	}
+
+	return o2
+}
+
+// WithGroup supplies a list of attributes declared by pair of key+value, nested
+// under a group. Groups are rendered as nested objects in JSON and as nested slog groups.
+//
+//	oops.WithGroup("db", "query", query, "duration_ms", 42)
+func (o OopsErrorBuilder) WithGroup(name string, kv ...any) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.context = cloneAttributes(o.context, 1)
+
+	// the existing group might be shared with another builder
+	group := map[string]any{}
+	if existing, ok := o2.context[name].(map[string]any); ok {
+		group = lo.Assign(group, existing)
+	}
+
+	for i := 0; i < len(kv)-1; i += 2 {
+		k := kv[i]
+		v := kv[i+1]
+
+		if key, ok := k.(string); ok {
+			group[key] = v
+		}
+	}
+
+	o2.context[name] = group
+
+	return o2
+}
+
+// Child returns a builder sharing the attributes of the current builder by reference,
+// with extra attributes declared by pair of key+value. It is cheaper than With()
+// when deriving many builders from a common parent (eg: per-item errors in a batch).
+func (o OopsErrorBuilder) Child(kv ...any) OopsErrorBuilder {
+	o2 := o // no copy: parent maps are never mutated by builder methods
+
+	parents := len(o.contextParents)
+	o2.contextParents = append(o.contextParents[:parents:parents], o.context)
+	o2.context = make(map[string]any, len(kv)/2)
+
 	for i := 0; i < len(kv)-1; i += 2 {
 		k := kv[i]
 		v := kv[i+1]
@@ -249,8 +560,10 @@ func (o OopsErrorBuilder) With(kv ...any) OopsErrorBuilder {
 }
 
 // WithContext supplies a list of values declared in context.
+// Registered context extractors are applied.
 func (o OopsErrorBuilder) WithContext(ctx context.Context, keys ...any) OopsErrorBuilder {
 	o2 := o.copy()
+	o2.context = cloneAttributes(o.context, len(keys))
 
 	for i := 0; i < len(keys); i++ {
 		switch k := keys[i].(type) {
@@ -273,7 +586,11 @@ func (o OopsErrorBuilder) WithContext(ctx context.Context, keys ...any) OopsErro
 		o2.span = spanCtx.SpanID().String()
 	}
 
-	return o2
+	if CaptureGoroutineInfo {
+		o2.pprofLabels = pprofLabels(ctx)
+	}
+
+	return applyContextExtractors(ctx, o2)
 }
 
 // Trace set a transaction id, trace id or correlation id...
@@ -312,10 +629,49 @@ func (o OopsErrorBuilder) Owner(owner string) OopsErrorBuilder {
 	return o2
 }
 
+// HTTPStatus sets the http status code to respond with (eg: 404).
+func (o OopsErrorBuilder) HTTPStatus(status int) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.httpStatus = status
+	return o2
+}
+
+// Retryable marks the error as retryable or not, for job queues and workflow
+// engines. Errors are retryable by default.
+func (o OopsErrorBuilder) Retryable(retryable bool) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.retryable = &retryable
+	return o2
+}
+
+// Fingerprint overrides the key used for grouping similar errors.
+func (o OopsErrorBuilder) Fingerprint(fingerprint string) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.fingerprint = fingerprint
+	return o2
+}
+
+// RedactKeys hides the values of the given context, user and tenant keys (case insensitive)
+// in every output. Global keys are declared in `oops.RedactedKeys`.
+func (o OopsErrorBuilder) RedactKeys(keys ...string) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.redactKeys = append(append([]string{}, o.redactKeys...), keys...)
+	return o2
+}
+
+// Action adds a machine-readable remediation action (eg: "rotate_credentials"),
+// for automated remediation systems.
+func (o OopsErrorBuilder) Action(name string, params map[string]any) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.actions = append(append([]RemediationAction{}, o.actions...), RemediationAction{Name: name, Params: params})
+	return o2
+}
+
 // User supplies user id and a chain of key/value.
 func (o OopsErrorBuilder) User(userID string, userData ...any) OopsErrorBuilder {
 	o2 := o.copy()
 	o2.userID = userID
+	o2.userData = cloneAttributes(o.userData, len(userData)/2)
 
 	for i := 0; i < len(userData)-1; i += 2 {
 		k := userData[i]
@@ -333,6 +689,7 @@ func (o OopsErrorBuilder) User(userID string, userData ...any) OopsErrorBuilder
 func (o OopsErrorBuilder) Tenant(tenantID string, tenantData ...any) OopsErrorBuilder {
 	o2 := o.copy()
 	o2.tenantID = tenantID
+	o2.tenantData = cloneAttributes(o.tenantData, len(tenantData)/2)
 
 	for i := 0; i < len(tenantData)-1; i += 2 {
 		k := tenantData[i]
@@ -346,16 +703,96 @@ func (o OopsErrorBuilder) Tenant(tenantID string, tenantData ...any) OopsErrorBu
 	return o2
 }
 
-// Request supplies a http.Request.
-func (o OopsErrorBuilder) Request(req *http.Request, withBody bool) OopsErrorBuilder {
+// Request supplies a http.Request. An optional max body size (in bytes) overrides
+// `oops.MaxBodySize` for this request.
+func (o OopsErrorBuilder) Request(req *http.Request, withBody bool, maxBodySize ...int) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.req = lo.ToPtr(lo.T3(req, withBody, coalesceOrEmpty(maxBodySize...)))
+	o2.reqDump = &requestDump{}
+	return o2
+}
+
+// Response supplies a http.Response. An optional max body size (in bytes) overrides
+// `oops.MaxBodySize` for this response. The body is buffered and restored, so that
+// it can still be read by the caller.
+func (o OopsErrorBuilder) Response(res *http.Response, withBody bool, maxBodySize ...int) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.res = captureResponse(res, withBody, coalesceOrEmpty(coalesceOrEmpty(maxBodySize...), MaxBodySize))
+	return o2
+}
+
+// Resolution sets the strategy used to resolve attributes declared by many errors
+// of a chain. Default: `oops.AttributeResolution`.
+func (o OopsErrorBuilder) Resolution(strategy ResolutionStrategy) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.resolution = strategy
+	return o2
+}
+
+// WithStackDepth sets the max depth of the stack trace captured for this error.
+// Default: `oops.StackTraceMaxDepth`.
+func (o OopsErrorBuilder) WithStackDepth(depth int) OopsErrorBuilder {
 	o2 := o.copy()
-	o2.req = lo.ToPtr(lo.T2(req, withBody))
+	o2.stacktraceDepth = depth
+	return o2
+}
+
+// Caller captures only the immediate call site (file, line and function) instead
+// of a full stack trace, for high-volume errors. The stack walk stops at the first
+// frame outside of this package. It is a shortcut for `WithStackDepth(1)`.
+func (o OopsErrorBuilder) Caller() OopsErrorBuilder {
+	return o.WithStackDepth(1)
+}
+
+// SkipFrames hides the n innermost frames of the captured stack trace, so that
+// helpers built on top of oops do not appear in it (similar to `logr.Logger.AddCallDepth`).
+// Successive calls add up.
+func (o OopsErrorBuilder) SkipFrames(n int) OopsErrorBuilder {
+	o2 := o.copy()
+	o2.stacktraceSkip += n
+	return o2
+}
+
+// WithoutStacktrace disables stack trace capture, for hot paths.
+func (o OopsErrorBuilder) WithoutStacktrace() OopsErrorBuilder {
+	o2 := o.copy()
+	o2.stacktraceDisabled = true
+	return o2
+}
+
+// WithAllGoroutines snapshots the stacks of all goroutines (up to `oops.GoroutinesMaxSize`
+// bytes) in addition to the current stack trace, for deadlock or timeout errors.
+func (o OopsErrorBuilder) WithAllGoroutines() OopsErrorBuilder {
+	o2 := o.copy()
+	o2.goroutinesEnabled = true
+	return o2
+}
+
+// LinkStack captures the current stack on the builder. Errors built later from
+// this builder, possibly in another goroutine (eg: in a worker pool), print these
+// frames beneath their own stack trace, similar to span links in OpenTelemetry.
+//
+//	builder := oops.In("mailer").LinkStack()
+//	pool.Submit(func() {
+//		err := builder.Errorf("could not send email")
+//	})
+func (o OopsErrorBuilder) LinkStack() OopsErrorBuilder {
+	o2 := o.copy()
+
+	depth := StackTraceMaxDepth
+	if o.stacktraceDepth > 0 {
+		depth = o.stacktraceDepth
+	}
+
+	o2.linked = newStacktraceWithSkip(o.span, depth, 0)
 	return o2
 }
 
-// Response supplies a http.Response.
-func (o OopsErrorBuilder) Response(res *http.Response, withBody bool) OopsErrorBuilder {
+// ForeignFrames appends frames captured outside of the Go runtime (cgo, FFI,
+// remote service...) beneath the Go frames of the stack trace. The origin is
+// printed next to each frame (eg: "sqlite", "payment-service").
+func (o OopsErrorBuilder) ForeignFrames(origin string, frames ...ForeignFrame) OopsErrorBuilder {
 	o2 := o.copy()
-	o2.res = lo.ToPtr(lo.T2(res, withBody))
+	o2.foreign = append(append([]foreignStacktrace{}, o.foreign...), foreignStacktrace{origin: origin, frames: frames})
 	return o2
 }
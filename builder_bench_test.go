@@ -0,0 +1,40 @@
+package oops
+
+import (
+	"errors"
+	"testing"
+)
+
+func BenchmarkBuilderChain(b *testing.B) {
+	err := errors.New("connection refused")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = In("repository").
+			Tags("database", "sql").
+			Code("db_unavailable").
+			Trace("trace-id").
+			Hint("check the database status").
+			Owner("platform@acme.org").
+			With("query", "SELECT 1", "duration_ms", 42).
+			User("user-123", "email", "foo@bar.com").
+			Tenant("tenant-123", "plan", "enterprise").
+			With("retry", 3).
+			WithoutStacktrace().
+			Wrapf(err, "could not fetch user")
+	}
+}
+
+func BenchmarkBuilderReuse(b *testing.B) {
+	err := errors.New("connection refused")
+	builder := In("repository").
+		Tags("database", "sql").
+		With("query", "SELECT 1", "duration_ms", 42).
+		User("user-123", "email", "foo@bar.com").
+		WithoutStacktrace()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = builder.Code("db_unavailable").Wrapf(err, "could not fetch user")
+	}
+}
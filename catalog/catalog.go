@@ -0,0 +1,112 @@
+package oopscatalog
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/samber/oops"
+)
+
+// Entry describes the default metadata of an error code.
+type Entry struct {
+	Domain     string
+	HTTPStatus int
+	Severity   oops.SeverityLevel
+	Owner      string
+	Hint       string
+	Public     string
+}
+
+var mutex sync.RWMutex
+var entries = map[string]Entry{}
+
+func init() {
+	oops.RegisterCodeResolver(resolve)
+}
+
+// Register declares an error code. Errors built with `oops.FromCode(code)` are
+// pre-filled with the metadata of the entry. It panics if the code is already registered.
+//
+//	oopscatalog.Register("billing.invoice_not_found", oopscatalog.Entry{
+//		Domain:     "billing",
+//		HTTPStatus: 404,
+//		Severity:   oops.SeverityWarning,
+//		Owner:      "billing-team@acme.org",
+//		Public:     "Invoice not found.",
+//	})
+func Register(code string, entry Entry) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if _, ok := entries[code]; ok {
+		panic(fmt.Sprintf("oopscatalog: error code %q registered twice", code))
+	}
+
+	entries[code] = entry
+}
+
+// Lookup returns the entry of a code.
+func Lookup(code string) (Entry, bool) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	entry, ok := entries[code]
+	return entry, ok
+}
+
+// Codes returns the registered codes.
+func Codes() []string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	codes := make([]string, 0, len(entries))
+	for code := range entries {
+		codes = append(codes, code)
+	}
+
+	return codes
+}
+
+// HTTPStatus returns the http status of the error code, or the default status
+// when the error has no registered code.
+func HTTPStatus(err error, defaultStatus int) int {
+	entry, ok := Lookup(oops.GetCode(err))
+	if !ok || entry.HTTPStatus == 0 {
+		return defaultStatus
+	}
+
+	return entry.HTTPStatus
+}
+
+func resolve(code string, builder oops.OopsErrorBuilder) oops.OopsErrorBuilder {
+	entry, ok := Lookup(code)
+	if !ok {
+		return builder
+	}
+
+	if entry.Domain != "" {
+		builder = builder.In(entry.Domain)
+	}
+
+	if entry.HTTPStatus != 0 {
+		builder = builder.HTTPStatus(entry.HTTPStatus)
+	}
+
+	if entry.Severity != oops.SeverityUnknown {
+		builder = builder.Severity(entry.Severity)
+	}
+
+	if entry.Owner != "" {
+		builder = builder.Owner(entry.Owner)
+	}
+
+	if entry.Hint != "" {
+		builder = builder.Hint(entry.Hint)
+	}
+
+	if entry.Public != "" {
+		builder = builder.Public(entry.Public)
+	}
+
+	return builder
+}
@@ -0,0 +1,47 @@
+package oopscatalog
+
+import (
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	is := assert.New(t)
+
+	Register("billing.invoice_not_found", Entry{
+		Domain:     "billing",
+		HTTPStatus: 404,
+		Severity:   oops.SeverityWarning,
+		Owner:      "billing-team@acme.org",
+		Hint:       "Check the invoice id",
+		Public:     "Invoice not found.",
+	})
+
+	is.Contains(Codes(), "billing.invoice_not_found")
+	is.PanicsWithValue(`oopscatalog: error code "billing.invoice_not_found" registered twice`, func() {
+		Register("billing.invoice_not_found", Entry{})
+	})
+
+	err := oops.FromCode("billing.invoice_not_found").Wrap(assert.AnError).(oops.OopsError)
+	is.Equal("billing.invoice_not_found", err.Code())
+	is.Equal("billing", err.Domain())
+	is.Equal(oops.SeverityWarning, err.Severity())
+	is.Equal("billing-team@acme.org", err.Owner())
+	is.Equal("Check the invoice id", err.Hint())
+	is.Equal("Invoice not found.", err.Public())
+	is.Equal(404, err.HTTPStatus())
+	is.Equal(404, HTTPStatus(err, 500))
+
+	// builder values override the catalog
+	err = oops.FromCode("billing.invoice_not_found").In("payments").Wrap(assert.AnError).(oops.OopsError)
+	is.Equal("payments", err.Domain())
+
+	// unknown code
+	err = oops.FromCode("billing.unknown").Wrap(assert.AnError).(oops.OopsError)
+	is.Equal("billing.unknown", err.Code())
+	is.Empty(err.Domain())
+	is.Equal(500, HTTPStatus(err, 500))
+	is.Equal(500, HTTPStatus(assert.AnError, 500))
+}
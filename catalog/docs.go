@@ -0,0 +1,104 @@
+package oopscatalog
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// Document is the machine-readable description of a registered error code.
+type Document struct {
+	Code       string `json:"code"`
+	Domain     string `json:"domain,omitempty"`
+	HTTPStatus int    `json:"http_status,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Hint       string `json:"hint,omitempty"`
+	Public     string `json:"public,omitempty"`
+}
+
+// Documents returns the description of every registered code, sorted by code.
+func Documents() []Document {
+	codes := Codes()
+	sort.Strings(codes)
+
+	docs := make([]Document, 0, len(codes))
+	for _, code := range codes {
+		entry, ok := Lookup(code)
+		if !ok {
+			continue
+		}
+
+		docs = append(docs, Document{
+			Code:       code,
+			Domain:     entry.Domain,
+			HTTPStatus: entry.HTTPStatus,
+			Severity:   string(entry.Severity),
+			Owner:      entry.Owner,
+			Hint:       entry.Hint,
+			Public:     entry.Public,
+		})
+	}
+
+	return docs
+}
+
+// WriteJSON writes the description of every registered code, as a JSON array.
+func WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Documents())
+}
+
+// OpenAPIComponents returns an OpenAPI 3 `components` object, with an `Error`
+// schema and one response per registered code. Responses can be referenced
+// from operations (eg: `#/components/responses/billing.invoice_not_found`).
+func OpenAPIComponents() map[string]any {
+	responses := map[string]any{}
+
+	for _, doc := range Documents() {
+		description := doc.Public
+		if description == "" {
+			description = doc.Code
+		}
+
+		example := map[string]any{"code": doc.Code}
+		if doc.Domain != "" {
+			example["domain"] = doc.Domain
+		}
+		if doc.Public != "" {
+			example["public"] = doc.Public
+		}
+
+		response := map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema":  map[string]any{"$ref": "#/components/schemas/Error"},
+					"example": example,
+				},
+			},
+			"x-oops-code": doc.Code,
+		}
+		if doc.HTTPStatus != 0 {
+			response["x-http-status"] = doc.HTTPStatus
+		}
+
+		responses[doc.Code] = response
+	}
+
+	return map[string]any{
+		"schemas": map[string]any{
+			"Error": map[string]any{
+				"type":     "object",
+				"required": []string{"code"},
+				"properties": map[string]any{
+					"code":   map[string]any{"type": "string"},
+					"domain": map[string]any{"type": "string"},
+					"public": map[string]any{"type": "string"},
+				},
+			},
+		},
+		"responses": responses,
+	}
+}
@@ -0,0 +1,61 @@
+package oopscatalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocuments(t *testing.T) {
+	is := assert.New(t)
+
+	Register("docs.b", Entry{Domain: "docs", HTTPStatus: 409, Severity: oops.SeverityInfo, Public: "Conflict."})
+	Register("docs.a", Entry{Domain: "docs"})
+
+	docs := Documents()
+	idxA := indexOf(docs, "docs.a")
+	idxB := indexOf(docs, "docs.b")
+	is.NotEqual(-1, idxA)
+	is.Less(idxA, idxB)
+	is.Equal(Document{Code: "docs.b", Domain: "docs", HTTPStatus: 409, Severity: "info", Public: "Conflict."}, docs[idxB])
+
+	var buf bytes.Buffer
+	is.NoError(WriteJSON(&buf))
+
+	var decoded []Document
+	is.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	is.Equal(docs, decoded)
+
+	components := OpenAPIComponents()
+	is.Contains(components["schemas"], "Error")
+
+	responses := components["responses"].(map[string]any)
+	is.Equal(
+		map[string]any{
+			"description": "Conflict.",
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema":  map[string]any{"$ref": "#/components/schemas/Error"},
+					"example": map[string]any{"code": "docs.b", "domain": "docs", "public": "Conflict."},
+				},
+			},
+			"x-oops-code":   "docs.b",
+			"x-http-status": 409,
+		},
+		responses["docs.b"],
+	)
+	is.Equal("docs.a", responses["docs.a"].(map[string]any)["description"])
+}
+
+func indexOf(docs []Document, code string) int {
+	for i, doc := range docs {
+		if doc.Code == code {
+			return i
+		}
+	}
+
+	return -1
+}
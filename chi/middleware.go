@@ -0,0 +1,96 @@
+package oopschi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/samber/oops"
+)
+
+type contextKey string
+
+const contextKeyOptions = contextKey("oopschi")
+
+// Options configures the middleware.
+type Options struct {
+	// TraceHeader is the request header of the trace id. Defaults to `X-Request-ID`.
+	// When missing, the request id of the chi `middleware.RequestID` is used.
+	TraceHeader string
+	// Domain returns the domain of errors from the route pattern (eg: "/invoices/{id}").
+	// Defaults to the route pattern.
+	Domain func(pattern string) string
+}
+
+func init() {
+	oops.RegisterContextExtractor(extract)
+}
+
+// Middleware stores a builder in the context of each request (see `oops.WithBuilder`),
+// with the trace id of the request and the request attached, so that handlers
+// just call `oops.FromContext(ctx)`. Errors are set in the domain of the route
+// pattern, once routed.
+//
+//	router := chi.NewRouter()
+//	router.Use(oopschi.Middleware)
+//	router.Get("/invoices/{id}", func(w http.ResponseWriter, r *http.Request) {
+//		err := oops.FromContext(r.Context()).Errorf("invoice not found")
+//	})
+func Middleware(next http.Handler) http.Handler {
+	return MiddlewareWith(Options{})(next)
+}
+
+// MiddlewareWith returns a middleware with custom options.
+func MiddlewareWith(opts Options) func(http.Handler) http.Handler {
+	if opts.TraceHeader == "" {
+		opts.TraceHeader = "X-Request-ID"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			builder := oops.FromContext(ctx)
+
+			trace := r.Header.Get(opts.TraceHeader)
+			if trace == "" {
+				trace = middleware.GetReqID(ctx)
+			}
+			if trace != "" {
+				builder = builder.Trace(trace)
+			}
+
+			// the request is dumped when the error is serialized
+			r = r.WithContext(context.WithValue(ctx, contextKeyOptions, &opts))
+			builder = builder.Request(r, false)
+
+			next.ServeHTTP(w, r.WithContext(oops.WithBuilder(r.Context(), builder)))
+		})
+	}
+}
+
+// extract sets the domain from the route pattern, which is known only after
+// routing, when the handler calls `oops.FromContext`.
+func extract(ctx context.Context, builder oops.OopsErrorBuilder) oops.OopsErrorBuilder {
+	opts, ok := ctx.Value(contextKeyOptions).(*Options)
+	if !ok {
+		return builder
+	}
+
+	routeCtx := chi.RouteContext(ctx)
+	if routeCtx == nil {
+		return builder
+	}
+
+	pattern := routeCtx.RoutePattern()
+	if pattern == "" {
+		return builder
+	}
+
+	if opts.Domain != nil {
+		return builder.In(opts.Domain(pattern))
+	}
+
+	return builder.In(pattern)
+}
@@ -0,0 +1,62 @@
+package oopschi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	is := assert.New(t)
+
+	var err error
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		err = oops.FromContext(r.Context()).Errorf("invoice not found")
+	}
+
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/invoices/{id}", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/invoices/42", nil)
+	req.Header.Set("X-Request-ID", "1234")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	oopsError, ok := oops.AsOops(err)
+	is.True(ok)
+	is.Equal("1234", oopsError.Trace())
+	is.Equal("/invoices/{id}", oopsError.Domain())
+	is.Equal("/invoices/42", oopsError.Request().URL.Path)
+
+	// custom options
+	router = chi.NewRouter()
+	router.Use(MiddlewareWith(Options{
+		TraceHeader: "X-Correlation-ID",
+		Domain: func(pattern string) string {
+			return strings.Split(strings.Trim(pattern, "/"), "/")[0]
+		},
+	}))
+	router.Get("/invoices/{id}", handler)
+
+	req = httptest.NewRequest(http.MethodGet, "/invoices/42", nil)
+	req.Header.Set("X-Correlation-ID", "5678")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	oopsError, _ = oops.AsOops(err)
+	is.Equal("5678", oopsError.Trace())
+	is.Equal("invoices", oopsError.Domain())
+
+	// without the middleware
+	router = chi.NewRouter()
+	router.Get("/invoices/{id}", handler)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/invoices/42", nil))
+
+	oopsError, _ = oops.AsOops(err)
+	is.Equal("", oopsError.Domain())
+	is.Nil(oopsError.Request())
+}
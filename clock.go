@@ -0,0 +1,32 @@
+package oops
+
+import (
+	"sync"
+	"time"
+)
+
+var clockMutex sync.RWMutex
+var clock = time.Now
+
+// SetClock overrides the clock used for the time of errors and for `Since()`
+// (eg: a fixed clock for deterministic tests). A nil clock restores `time.Now`.
+//
+//	oops.SetClock(func() time.Time { return time.Date(2023, 5, 2, 5, 26, 48, 0, time.UTC) })
+//	defer oops.SetClock(nil)
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+
+	clockMutex.Lock()
+	clock = now
+	clockMutex.Unlock()
+}
+
+func now() time.Time {
+	clockMutex.RLock()
+	now := clock
+	clockMutex.RUnlock()
+
+	return now()
+}
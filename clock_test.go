@@ -0,0 +1,55 @@
+package oops
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClock(t *testing.T) {
+	is := assert.New(t)
+
+	defer SetClock(nil)
+
+	now := time.Date(2023, 5, 2, 5, 26, 48, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+
+	err := new().Errorf("permission denied").(OopsError)
+	is.Equal(now, err.Time())
+
+	err = new().Since(now.Add(-time.Second)).Errorf("permission denied").(OopsError)
+	is.Equal(time.Second, err.Duration())
+
+	SetClock(nil)
+
+	err = new().Errorf("permission denied").(OopsError)
+	is.WithinDuration(time.Now(), err.Time(), time.Second)
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	is := assert.New(t)
+
+	defer SetClock(nil)
+	defer SetIDGenerator(nil)
+
+	SetClock(func() time.Time { return time.Date(2023, 5, 2, 5, 26, 48, 0, time.UTC) })
+
+	output := func() string {
+		id := 0
+		SetIDGenerator(func() string {
+			id++
+			return fmt.Sprintf("id-%d", id)
+		})
+
+		err := new().Code("iam_missing_permission").WithoutStacktrace().Errorf("permission denied")
+		b, _ := json.Marshal(err)
+		return string(b)
+	}
+
+	// the span is "id-1", the trace is generated on output
+	is.Contains(output(), `"time":"2023-05-02T05:26:48Z","trace":"id-2"`)
+	is.Equal(output(), output())
+}
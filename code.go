@@ -0,0 +1,82 @@
+package oops
+
+import (
+	"errors"
+	"sync"
+)
+
+// CodeResolver pre-fills a builder with the default metadata of an error code
+// (eg: domain, severity, owner...). See the `catalog` sub-package.
+type CodeResolver func(code string, builder OopsErrorBuilder) OopsErrorBuilder
+
+var codeResolversMutex sync.RWMutex
+var codeResolvers = []CodeResolver{}
+
+// RegisterCodeResolver registers a global resolver, applied by `FromCode`.
+func RegisterCodeResolver(resolver CodeResolver) {
+	codeResolversMutex.Lock()
+	codeResolvers = append(codeResolvers, resolver)
+	codeResolversMutex.Unlock()
+}
+
+func applyCodeResolvers(code string, builder OopsErrorBuilder) OopsErrorBuilder {
+	codeResolversMutex.RLock()
+	resolvers := codeResolvers
+	codeResolversMutex.RUnlock()
+
+	for _, resolver := range resolvers {
+		builder = resolver(code, builder)
+	}
+
+	return builder
+}
+
+// Sentinel is an error code usable as a target of errors.Is: it matches the oops
+// errors declaring the code, at any level of the chain. Sentinels are comparable
+// and can be declared as constants.
+//
+//	const ErrInvoiceNotFound = oops.Sentinel("billing.invoice_not_found")
+//
+//	err := ErrInvoiceNotFound.Builder().With("invoice_id", id).Errorf("invoice not found")
+//	errors.Is(err, ErrInvoiceNotFound) // true
+type Sentinel string
+
+var _ error = Sentinel("")
+
+// Error implements error.
+func (s Sentinel) Error() string {
+	return string(s)
+}
+
+// Code returns the error code of the sentinel.
+func (s Sentinel) Code() string {
+	return string(s)
+}
+
+// Builder returns a builder with the code of the sentinel, pre-filled by the
+// registered code resolvers (see FromCode).
+func (s Sentinel) Builder() OopsErrorBuilder {
+	return FromCode(string(s))
+}
+
+// IsCode reports whether an oops error of the chain declares the code. Errors
+// joined with errors.Join are traversed.
+//
+//	if oops.IsCode(err, "billing.invoice_not_found") {
+//		w.WriteHeader(http.StatusNotFound)
+//	}
+func IsCode(err error, code string) bool {
+	return code != "" && errors.Is(err, Sentinel(code))
+}
+
+// HasCode reports whether an oops error of the chain declares a code.
+func HasCode(err error) bool {
+	found := false
+
+	Walk(err, func(layer OopsError) bool {
+		found = layer.code != ""
+		return !found
+	})
+
+	return found
+}
@@ -0,0 +1,66 @@
+package oops
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinel(t *testing.T) {
+	is := assert.New(t)
+
+	const errInvoiceNotFound = Sentinel("billing.invoice_not_found")
+	const errPaymentDeclined = Sentinel("billing.payment_declined")
+
+	err := errInvoiceNotFound.Builder().With("invoice_id", 42).Errorf("invoice not found")
+	is.Equal("billing.invoice_not_found", err.(OopsError).Code())
+	is.True(errors.Is(err, errInvoiceNotFound))
+	is.False(errors.Is(err, errPaymentDeclined))
+
+	// at any level of the chain
+	wrapped := fmt.Errorf("could not send reminder: %w", new().Code("billing.reminder_failed").Wrap(err))
+	is.True(errors.Is(wrapped, errInvoiceNotFound))
+	is.True(errors.Is(wrapped, Sentinel("billing.reminder_failed")))
+	is.True(errors.Is(errors.Join(errors.New("timeout"), err), errInvoiceNotFound))
+
+	// bare sentinels
+	is.True(errors.Is(errInvoiceNotFound, errInvoiceNotFound))
+	is.EqualError(errInvoiceNotFound, "billing.invoice_not_found")
+	is.Equal("billing.invoice_not_found", errInvoiceNotFound.Code())
+
+	// code resolvers
+	resolvers := codeResolvers
+	defer func() { codeResolvers = resolvers }()
+	RegisterCodeResolver(func(code string, builder OopsErrorBuilder) OopsErrorBuilder {
+		return builder.In("billing")
+	})
+	is.Equal("billing", errInvoiceNotFound.Builder().Errorf("invoice not found").(OopsError).Domain())
+
+	// uncoded errors do not match the empty sentinel
+	is.False(errors.Is(new().Errorf("permission denied"), Sentinel("")))
+}
+
+func TestIsCode(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().Code("billing.invoice_not_found").Errorf("invoice not found")
+	wrapped := new().Code("billing.reminder_failed").Wrap(err)
+
+	is.True(IsCode(err, "billing.invoice_not_found"))
+	is.True(IsCode(wrapped, "billing.invoice_not_found"))
+	is.True(IsCode(wrapped, "billing.reminder_failed"))
+	is.True(IsCode(new().Join(errors.New("timeout"), err), "billing.invoice_not_found"))
+	is.False(IsCode(err, "billing.payment_declined"))
+	is.False(IsCode(err, ""))
+	is.False(IsCode(errors.New("billing.invoice_not_found"), "billing.invoice_not_found"))
+	is.False(IsCode(nil, "billing.invoice_not_found"))
+
+	is.True(HasCode(err))
+	is.True(HasCode(fmt.Errorf("wrapped: %w", wrapped)))
+	is.True(HasCode(errors.Join(errors.New("timeout"), err)))
+	is.False(HasCode(new().Errorf("invoice not found")))
+	is.False(HasCode(errors.New("invoice not found")))
+	is.False(HasCode(nil))
+}
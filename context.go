@@ -1,17 +1,228 @@
 package oops
 
-import "context"
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"go.opentelemetry.io/otel/trace"
+)
 
 type contextKey string
 
 const contextKeyOops = contextKey("oops")
 
+// ContextExtractor enriches a builder with values found in a Go context
+// (eg: user id, tenant id or request id set by an auth middleware).
+// Extractors must not call `WithContext`.
+type ContextExtractor func(ctx context.Context, builder OopsErrorBuilder) OopsErrorBuilder
+
+// EmitContextDeadline adds the deadline of the Go context (`deadline`, `deadline_remaining`)
+// and its cancellation cause (`context_err`) to the context of errors built with
+// `oops.FromContext(ctx)`. Attributes already set with `With` are kept.
+var EmitContextDeadline = false
+
+// contextExtractor is a registered extractor. Entries are compared by address,
+// to unregister them and to apply them once per builder.
+type contextExtractor struct {
+	extract ContextExtractor
+}
+
+var contextExtractorsMutex sync.RWMutex
+var contextExtractors = []*contextExtractor{}
+
+// RegisterContextExtractor registers a global extractor, applied by `FromContext` and `WithContext`.
+// Each extractor is applied once per builder, so that `FromContext(ctx).WithContext(ctx)`
+// does not run it twice. The returned function unregisters the extractor.
+//
+//	unregister := oops.RegisterContextExtractor(func(ctx context.Context, builder oops.OopsErrorBuilder) oops.OopsErrorBuilder {
+//		if userID, ok := ctx.Value(userIDKey).(string); ok {
+//			builder = builder.User(userID)
+//		}
+//		return builder
+//	})
+//	defer unregister()
+func RegisterContextExtractor(extractor ContextExtractor) func() {
+	entry := &contextExtractor{extract: extractor}
+
+	contextExtractorsMutex.Lock()
+	contextExtractors = append(contextExtractors[:len(contextExtractors):len(contextExtractors)], entry)
+	contextExtractorsMutex.Unlock()
+
+	return func() {
+		contextExtractorsMutex.Lock()
+		defer contextExtractorsMutex.Unlock()
+
+		// copy on write: applyContextExtractors iterates over a snapshot
+		extractors := make([]*contextExtractor, 0, len(contextExtractors))
+		for _, e := range contextExtractors {
+			if e != entry {
+				extractors = append(extractors, e)
+			}
+		}
+
+		contextExtractors = extractors
+	}
+}
+
+func applyContextExtractors(ctx context.Context, builder OopsErrorBuilder) OopsErrorBuilder {
+	contextExtractorsMutex.RLock()
+	extractors := contextExtractors
+	contextExtractorsMutex.RUnlock()
+
+	for _, extractor := range extractors {
+		if lo.Contains(builder.extractors, extractor) {
+			continue
+		}
+
+		builder = extractor.extract(ctx, builder)
+
+		applied := make([]*contextExtractor, len(builder.extractors), len(builder.extractors)+1)
+		copy(applied, builder.extractors)
+		builder.extractors = append(applied, extractor)
+	}
+
+	return builder
+}
+
+// enrichFromContext fills the attributes carried by a Go context that the builder
+// misses: OpenTelemetry trace and span ids, pprof labels and deadline.
+func enrichFromContext(ctx context.Context, builder OopsErrorBuilder) OopsErrorBuilder {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	fillTrace := builder.trace == "" && spanCtx.HasTraceID()
+	fillSpan := builder.span == "" && spanCtx.HasSpanID()
+	fillLabels := CaptureGoroutineInfo && builder.pprofLabels == nil
+
+	deadline, hasDeadline := ctx.Deadline()
+	hasDeadline = hasDeadline && EmitContextDeadline
+	ctxErr := ctx.Err()
+	hasErr := ctxErr != nil && EmitContextDeadline
+
+	if !fillTrace && !fillSpan && !fillLabels && !hasDeadline && !hasErr {
+		return builder
+	}
+
+	o2 := builder.copy()
+
+	if fillTrace {
+		o2.trace = spanCtx.TraceID().String()
+	}
+
+	if fillSpan {
+		o2.span = spanCtx.SpanID().String()
+	}
+
+	if fillLabels {
+		o2.pprofLabels = pprofLabels(ctx)
+	}
+
+	if hasDeadline || hasErr {
+		// computed at each call, instead of being stored in the builder context
+		o2.deadline = &contextDeadline{}
+
+		if hasDeadline {
+			o2.deadline.deadline = deadline.In(Local)
+			o2.deadline.remaining = time.Until(deadline)
+		}
+
+		if hasErr {
+			o2.deadline.err = ctxErr.Error()
+		}
+	}
+
+	return o2
+}
+
+// contextDeadline holds the deadline attributes of a Go context, at the time
+// of the FromContext call.
+type contextDeadline struct {
+	deadline  time.Time
+	remaining time.Duration
+	err       string
+}
+
+// fill returns a copy of context with the deadline attributes. Attributes set
+// by the user are not overridden.
+func (d *contextDeadline) fill(context map[string]any) map[string]any {
+	output := cloneAttributes(context, 3)
+
+	set := func(key string, value any) {
+		if _, ok := output[key]; !ok {
+			output[key] = value
+		}
+	}
+
+	if !d.deadline.IsZero() {
+		set("deadline", d.deadline)
+		set("deadline_remaining", d.remaining)
+	}
+
+	if d.err != "" {
+		set("context_err", d.err)
+	}
+
+	return output
+}
+
 func getBuilderFromContext(ctx context.Context) (OopsErrorBuilder, bool) {
 	b, ok := ctx.Value(contextKeyOops).(OopsErrorBuilder)
 	return b, ok
 }
 
 // WithBuilder set the error builder in the context, to be retrieved later with FromContext.
+// A builder already stored in the context is replaced: see AppendToContext to merge them.
+// Context extractors and deadline attributes are applied again by each FromContext,
+// with the context of the call.
 func WithBuilder(ctx context.Context, builder OopsErrorBuilder) context.Context {
+	if builder.extractors != nil || builder.deadline != nil {
+		builder = builder.copy()
+		builder.extractors = nil
+		builder.deadline = nil
+	}
+
 	return context.WithValue(ctx, contextKeyOops, builder)
 }
+
+// AppendToContext merges builder into the builder stored in the context, so that
+// several middlewares can each contribute attributes. Attributes set by builder
+// win. Without a stored builder, it behaves as WithBuilder.
+//
+//	ctx = oops.AppendToContext(ctx, oops.Trace(requestID))   // correlation middleware
+//	ctx = oops.AppendToContext(ctx, oops.User(userID, nil))  // auth middleware
+func AppendToContext(ctx context.Context, builder OopsErrorBuilder) context.Context {
+	if previous, ok := getBuilderFromContext(ctx); ok {
+		builder = previous.merge(builder)
+	}
+
+	return WithBuilder(ctx, builder)
+}
+
+// ContextAttrs returns the trace, span, domain and tenant attributes of the builder
+// stored in the context, so that every log line can be correlated with errors.
+// Context extractors are applied. Empty attributes are omitted.
+//
+//	logger.LogAttrs(ctx, slog.LevelInfo, "payment accepted", oops.ContextAttrs(ctx)...)
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	builder := FromContext(ctx)
+	attrs := []slog.Attr{}
+
+	if builder.trace != "" {
+		attrs = append(attrs, slog.String("trace", builder.trace))
+	}
+
+	if builder.span != "" {
+		attrs = append(attrs, slog.String("span", builder.span))
+	}
+
+	if builder.domain != "" {
+		attrs = append(attrs, slog.String("domain", builder.domain))
+	}
+
+	if builder.tenantID != "" {
+		attrs = append(attrs, slog.Group("tenant", slog.String("id", builder.tenantID)))
+	}
+
+	return attrs
+}
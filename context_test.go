@@ -0,0 +1,230 @@
+package oops
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRegisterContextExtractor(t *testing.T) {
+	is := assert.New(t)
+
+	type key string
+
+	unregister := RegisterContextExtractor(func(ctx context.Context, builder OopsErrorBuilder) OopsErrorBuilder {
+		if userID, ok := ctx.Value(key("user_id")).(string); ok {
+			builder = builder.User(userID)
+		}
+		if requestID, ok := ctx.Value(key("request_id")).(string); ok {
+			builder = builder.With("request_id", requestID)
+		}
+		return builder
+	})
+
+	ctx := context.WithValue(context.Background(), key("user_id"), "user-123")
+	ctx = context.WithValue(ctx, key("request_id"), "req-456")
+
+	err := FromContext(ctx).Errorf("permission denied")
+	is.Equal("user-123", err.(OopsError).userID)
+	is.Equal(map[string]any{"request_id": "req-456"}, err.(OopsError).context)
+
+	err = new().In("authz").WithContext(ctx).Errorf("permission denied")
+	is.Equal("authz", err.(OopsError).domain)
+	is.Equal("user-123", err.(OopsError).userID)
+	is.Equal(map[string]any{"request_id": "req-456"}, err.(OopsError).context)
+
+	// the builder transported in context is enriched
+	ctx = WithBuilder(ctx, new().In("billing"))
+	err = FromContext(ctx).Errorf("permission denied")
+	is.Equal("billing", err.(OopsError).domain)
+	is.Equal("user-123", err.(OopsError).userID)
+
+	// values missing from context
+	err = FromContext(context.Background()).Errorf("permission denied")
+	is.Empty(err.(OopsError).userID)
+	is.Empty(err.(OopsError).context)
+
+	unregister()
+	err = FromContext(ctx).Errorf("permission denied")
+	is.Empty(err.(OopsError).userID)
+	is.Empty(contextExtractors)
+}
+
+func TestRegisterContextExtractorOnce(t *testing.T) {
+	is := assert.New(t)
+
+	calls := 0
+	unregister := RegisterContextExtractor(func(ctx context.Context, builder OopsErrorBuilder) OopsErrorBuilder {
+		calls++
+		return builder.Tags("extracted")
+	})
+	defer unregister()
+
+	ctx := context.Background()
+
+	err := FromContext(ctx).WithContext(ctx).Errorf("permission denied")
+	is.Equal(1, calls)
+	is.Equal([]string{"extracted"}, err.(OopsError).tags)
+
+	// builders transported in context are extracted again by FromContext
+	ctx = WithBuilder(ctx, FromContext(ctx))
+	FromContext(ctx).Errorf("permission denied")
+	is.Equal(3, calls)
+
+	// the builder is not altered
+	builder := new()
+	builder.WithContext(ctx)
+	builder.WithContext(ctx)
+	is.Equal(5, calls)
+	is.Empty(builder.extractors)
+}
+
+func TestContextAttrs(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal([]slog.Attr{}, ContextAttrs(context.Background()))
+
+	ctx := WithBuilder(context.Background(), new().Trace("trace-123").Span("span-456").In("billing").Tenant("tenant-789", map[string]any{"plan": "pro"}))
+	is.Equal(
+		[]slog.Attr{
+			slog.String("trace", "trace-123"),
+			slog.String("span", "span-456"),
+			slog.String("domain", "billing"),
+			slog.Group("tenant", slog.String("id", "tenant-789")),
+		},
+		ContextAttrs(ctx),
+	)
+}
+
+func TestAppendToContext(t *testing.T) {
+	is := assert.New(t)
+
+	// without a stored builder
+	ctx := AppendToContext(context.Background(), new().Trace("1234").With("request_id", "req-456"))
+	err := FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Equal("1234", err.trace)
+	is.Equal(map[string]any{"request_id": "req-456"}, err.context)
+
+	// each middleware contributes attributes
+	ctx = AppendToContext(ctx, new().User("user-123", "email", "john@example.com").Tags("auth").With("role", "admin"))
+	ctx = AppendToContext(ctx, new().In("billing").Tags("auth", "billing").With("role", "owner"))
+
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Equal("1234", err.trace)
+	is.Equal("billing", err.domain)
+	is.Equal("user-123", err.userID)
+	is.Equal(map[string]any{"email": "john@example.com"}, err.userData)
+	is.Equal([]string{"auth", "billing"}, err.tags)
+	is.Equal(map[string]any{"request_id": "req-456", "role": "owner"}, err.context)
+
+	// parent contexts are kept
+	ctx = AppendToContext(context.Background(), new().With("request_id", "req-456").Child("step", "charge"))
+	ctx = AppendToContext(ctx, new().With("role", "admin"))
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Equal(map[string]any{"request_id": "req-456", "step": "charge", "role": "admin"}, err.Context())
+
+	// stored builders are not altered
+	base := new().With("request_id", "req-456")
+	ctx = WithBuilder(context.Background(), base)
+	_ = AppendToContext(ctx, new().With("role", "admin"))
+	is.Equal(map[string]any{"request_id": "req-456"}, base.context)
+
+	// nested groups are merged
+	ctx = AppendToContext(context.Background(), new().WithGroup("http", "method", "POST"))
+	ctx = AppendToContext(ctx, new().WithGroup("http", "route", "/users"))
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Equal(map[string]any{"http": map[string]any{"method": "POST", "route": "/users"}}, err.Context())
+
+	// stacktrace and goroutines options are merged
+	ctx = AppendToContext(context.Background(), new().Trace("1234"))
+	ctx = AppendToContext(ctx, new().WithoutStacktrace())
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Nil(err.stacktrace)
+	is.Equal("1234", err.trace)
+
+	ctx = AppendToContext(context.Background(), new().WithStackDepth(1))
+	ctx = AppendToContext(ctx, new().WithAllGoroutines())
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Len(err.stacktrace.getFrames(), 1)
+	is.NotEmpty(err.goroutines)
+
+	// WithBuilder replaces
+	ctx = WithBuilder(ctx, new().In("billing"))
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Empty(err.context)
+}
+
+func TestFromContextEnrichment(t *testing.T) {
+	is := assert.New(t)
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	// otel ids
+	err := FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", err.trace)
+	is.Equal("00f067aa0ba902b7", err.span)
+	is.Empty(err.context)
+
+	// ids of the stored builder are kept
+	err = FromContext(WithBuilder(ctx, new().Trace("1234"))).Errorf("permission denied").(OopsError)
+	is.Equal("1234", err.trace)
+	is.Equal("00f067aa0ba902b7", err.span)
+
+	// deadline
+	deadline := time.Now().Add(time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Empty(err.Context())
+
+	EmitContextDeadline = true
+	defer func() { EmitContextDeadline = false }()
+
+	err = FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.True(deadline.Equal(err.Context()["deadline"].(time.Time)))
+	is.InDelta(time.Minute, err.Context()["deadline_remaining"].(time.Duration), float64(time.Second))
+	is.NotContains(err.Context(), "context_err")
+	is.Empty(err.context)
+
+	// cancellation
+	cancel()
+	err = FromContext(WithBuilder(ctx, new().With("user_id", 1234))).Errorf("permission denied").(OopsError)
+	is.Equal("context canceled", err.Context()["context_err"])
+	is.Equal(1234, err.Context()["user_id"])
+
+	// attributes set by the user are kept
+	err = FromContext(WithBuilder(ctx, new().With("deadline", "tomorrow", "context_err", "timeout"))).Errorf("permission denied").(OopsError)
+	is.Equal("tomorrow", err.Context()["deadline"])
+	is.Equal("timeout", err.Context()["context_err"])
+	is.Contains(err.Context(), "deadline_remaining")
+}
+
+func TestFromContextDeadlineAtCallTime(t *testing.T) {
+	is := assert.New(t)
+
+	EmitContextDeadline = true
+	defer func() { EmitContextDeadline = false }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// a middleware stores the builder enriched from the request context
+	ctx = WithBuilder(ctx, FromContext(ctx).In("billing"))
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	err := FromContext(ctx).Errorf("permission denied").(OopsError)
+	is.Equal("billing", err.Domain())
+	is.Equal("context canceled", err.Context()["context_err"])
+	is.Less(err.Context()["deadline_remaining"].(time.Duration), 960*time.Millisecond)
+}
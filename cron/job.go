@@ -0,0 +1,75 @@
+package oopscron
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/samber/oops"
+	oopsalert "github.com/samber/oops/alert"
+)
+
+// Options configures the wrapper.
+type Options struct {
+	// Schedule is the schedule of the job (eg: "@hourly" or "*/5 * * * *"),
+	// attached to errors for context.
+	Schedule string
+	// OnError is called with the errors of the job. Defaults to logging with
+	// `slog.Default()` and sending to the sinks of `oopsalert`.
+	OnError func(err error)
+}
+
+// Wrap returns a `func()` running job, compatible with robfig/cron (`AddFunc`)
+// and gocron (`NewTask`). Errors and panics of the job become oops errors carrying
+// the job name (`cron_job`) and the run duration (`cron_duration`), and are logged
+// with `slog.Default()` and sent to the sinks registered in `oopsalert`.
+//
+//	c := cron.New()
+//	c.AddFunc("@hourly", oopscron.Wrap("invoices.reminder", sendReminders))
+func Wrap(name string, job func() error) func() {
+	return WrapWith(name, job, Options{})
+}
+
+// WrapWith returns a `func()` running job, with custom options.
+//
+//	spec := "*/5 * * * *"
+//	c.AddFunc(spec, oopscron.WrapWith("invoices.reminder", sendReminders, oopscron.Options{
+//		Schedule: spec,
+//	}))
+func WrapWith(name string, job func() error, opts Options) func() {
+	onError := opts.OnError
+	if onError == nil {
+		onError = defaultOnError
+	}
+
+	return func() {
+		builder := oops.With("cron_job", name)
+		if opts.Schedule != "" {
+			builder = builder.With("cron_schedule", opts.Schedule)
+		}
+
+		start := time.Now()
+
+		var err error
+		recovered := builder.Recoverf(func() {
+			err = job()
+		}, "cron: panic recovered")
+
+		builder = builder.With("cron_duration", time.Since(start))
+
+		switch {
+		case recovered != nil:
+			onError(builder.Wrap(recovered))
+		case err != nil:
+			onError(builder.Wrap(err))
+		}
+	}
+}
+
+func defaultOnError(err error) {
+	slog.Default().Error(err.Error(), slog.Any("error", err))
+
+	if e := oopsalert.NotifyContext(context.Background(), err); e != nil {
+		slog.Default().Error("cron: could not send alert", slog.Any("error", e))
+	}
+}
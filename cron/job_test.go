@@ -0,0 +1,66 @@
+package oopscron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	oopsalert "github.com/samber/oops/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	is := assert.New(t)
+
+	var handled error
+	opts := Options{
+		Schedule: "@hourly",
+		OnError: func(err error) {
+			handled = err
+		},
+	}
+
+	WrapWith("invoices.reminder", func() error {
+		return nil
+	}, opts)()
+	is.NoError(handled)
+
+	WrapWith("invoices.reminder", func() error {
+		return errors.New("smtp unavailable")
+	}, opts)()
+	is.EqualError(handled, "smtp unavailable")
+	oopsErr, ok := oops.AsOops(handled)
+	is.True(ok)
+	is.Equal("invoices.reminder", oopsErr.Context()["cron_job"])
+	is.Equal("@hourly", oopsErr.Context()["cron_schedule"])
+	is.IsType(time.Duration(0), oopsErr.Context()["cron_duration"])
+
+	handled = nil
+	WrapWith("invoices.reminder", func() error {
+		panic("oh no")
+	}, opts)()
+	is.ErrorContains(handled, "oh no")
+	oopsErr, ok = oops.AsOops(handled)
+	is.True(ok)
+	is.Equal("invoices.reminder", oopsErr.Context()["cron_job"])
+	is.Contains(oopsErr.Context(), "cron_duration")
+}
+
+func TestWrapAlert(t *testing.T) {
+	is := assert.New(t)
+	t.Cleanup(oopsalert.Reset)
+
+	var alerted oopsalert.Alert
+	oopsalert.RegisterDomain("billing", oopsalert.SinkFunc(func(ctx context.Context, alert oopsalert.Alert) error {
+		alerted = alert
+		return nil
+	}))
+
+	Wrap("invoices.reminder", func() error {
+		return oops.In("billing").Errorf("smtp unavailable")
+	})()
+	is.Equal("smtp unavailable", alerted.Message)
+	is.Equal("billing", alerted.Domain)
+}
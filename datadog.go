@@ -0,0 +1,98 @@
+package oops
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ToDatadogAttributes returns the attributes of the error, mapped to the Datadog
+// standard attributes (`error.kind`, `error.message`, `error.stack`, `usr.*`,
+// `dd.trace_id`, `dd.span_id`), so that logs correlate with APM traces without a
+// custom log pipeline. Other attributes are kept as in `ToMap()`.
+//
+// Datadog expects decimal ids: OpenTelemetry trace and span ids are converted,
+// and other ids are reported as `trace` and `span` only.
+func (o OopsError) ToDatadogAttributes() map[string]any {
+	payload := o.ToMapWith(ToMapOptions{Exclude: []string{"error", "stacktrace", "sources"}})
+
+	errorAttrs := map[string]any{
+		"kind":    o.datadogErrorKind(),
+		"message": o.Error(),
+	}
+	if stacktrace := o.Stacktrace(); stacktrace != "" {
+		errorAttrs["stack"] = stacktrace
+	}
+	payload["error"] = errorAttrs
+
+	if user, ok := payload["user"]; ok {
+		payload["usr"] = user
+		delete(payload, "user")
+	}
+
+	dd := map[string]any{}
+	if traceID, ok := datadogID(o.Trace(), 16); ok {
+		dd["trace_id"] = traceID
+	}
+	if spanID, ok := datadogID(o.Span(), 8); ok {
+		dd["span_id"] = spanID
+	}
+	if len(dd) > 0 {
+		payload["dd"] = dd
+	}
+
+	return payload
+}
+
+// datadogErrorKind returns the error code, or the type of the root cause.
+func (o OopsError) datadogErrorKind() string {
+	if code := o.Code(); code != "" {
+		return code
+	}
+
+	deepest := o
+	recursive(o, func(e OopsError) {
+		deepest = e
+	})
+
+	// created by oops.Errorf: no foreign root cause
+	if deepest.format != "" || deepest.err == nil {
+		return "oops.OopsError"
+	}
+
+	cause := deepest.err
+	for {
+		next := errors.Unwrap(cause)
+		if next == nil {
+			break
+		}
+		cause = next
+	}
+
+	return fmt.Sprintf("%T", cause)
+}
+
+// datadogID converts an hex OpenTelemetry id into the decimal representation of
+// its lower 64 bits. Decimal ids are kept as-is.
+func datadogID(id string, size int) (string, bool) {
+	if _, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return id, true
+	}
+
+	if len(id) != size*2 {
+		return "", false
+	}
+
+	b, err := hex.DecodeString(id)
+	if err != nil {
+		return "", false
+	}
+
+	var n uint64
+	for _, c := range b[size-8:] {
+		n = n<<8 | uint64(c)
+	}
+
+	return strconv.FormatUint(n, 10), true
+}
@@ -0,0 +1,282 @@
+package oops
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	// SensitiveHeaders lists the http headers (case insensitive) whose values are
+	// replaced by `[REDACTED]` in request and response dumps.
+	SensitiveHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Api-Key",
+		"X-Auth-Token",
+	}
+
+	// MaxBodySize limits the size (in bytes) of the body of request and response dumps,
+	// and the size of the body read to dump them. Longer bodies are cut and suffixed with
+	// a `...[truncated 1.2MB]` marker. It can be overridden per `Request()` and `Response()`
+	// call. Zero or negative means unlimited.
+	MaxBodySize = 64 * 1000
+
+	// BodyScrubbers are applied to the body of request and response dumps. Matches
+	// are replaced by `[REDACTED]`.
+	BodyScrubbers = []*regexp.Regexp{}
+)
+
+// requestDump is the wire representation of a http request, dumped on first
+// serialization of the error. Dumping the body consumes and replaces it, so it
+// is done once.
+type requestDump struct {
+	once      sync.Once
+	dump      []byte
+	truncated string
+	err       error
+}
+
+// get dumps the request. Up to maxBodySize bytes (zero means unlimited) of the
+// body are read, and req.Body is restored, so that the caller can still read it.
+func (d *requestDump) get(req *http.Request, withBody bool, maxBodySize int) ([]byte, string, error) {
+	d.once.Do(func() {
+		if !withBody || maxBodySize <= 0 || req.Body == nil || req.Body == http.NoBody {
+			d.dump, d.err = httputil.DumpRequestOut(req, withBody)
+			return
+		}
+
+		body, restored, truncated, err := captureBody(req.Body, maxBodySize)
+		req.Body = restored
+		if err != nil {
+			d.err = err
+			return
+		}
+
+		// the head keeps the original Content-Length
+		d.dump, d.err = httputil.DumpRequestOut(req, false)
+		d.dump = append(d.dump, body...)
+		if truncated {
+			d.truncated = truncatedMarker(req.ContentLength, len(body))
+		}
+	})
+
+	return d.dump, d.truncated, d.err
+}
+
+// dumpRequest returns the wire representation of the http request, or an
+// empty string when no request is attached.
+func (o OopsError) dumpRequest() (string, error) {
+	req := o.request()
+	if req == nil {
+		if replayed := o.replayedOutputs(); replayed != nil {
+			return replayed.request, nil
+		}
+
+		return "", nil
+	}
+
+	cache := o.requestDump()
+	if cache == nil {
+		cache = &requestDump{}
+	}
+
+	maxBodySize := coalesceOrEmpty(req.C, MaxBodySize)
+
+	dump, truncated, err := cache.get(req.A, req.B, maxBodySize)
+	if err != nil {
+		return "", fmt.Errorf("request dump: %w", err)
+	}
+
+	if truncated != "" {
+		return scrubDump(string(dump)) + truncated, nil
+	}
+
+	return truncateDumpBody(scrubDump(string(dump)), maxBodySize), nil
+}
+
+// dumpResponse returns the wire representation of the http response, or an
+// empty string when no response is attached.
+func (o OopsError) dumpResponse() (string, error) {
+	res := o.response()
+	if res == nil {
+		if replayed := o.replayedOutputs(); replayed != nil {
+			return replayed.response, nil
+		}
+
+		return "", nil
+	}
+
+	if res.err != nil {
+		return "", fmt.Errorf("response dump: %w", res.err)
+	}
+
+	// dump a copy, backed by the captured body
+	copied := *res.res
+	if res.withBody {
+		copied.Body = io.NopCloser(bytes.NewReader(res.body))
+		copied.ContentLength = int64(len(res.body))
+		copied.TransferEncoding = nil
+	}
+
+	dump, err := httputil.DumpResponse(&copied, res.withBody)
+	if err != nil {
+		return "", fmt.Errorf("response dump: %w", err)
+	}
+
+	output := scrubDump(string(dump))
+	if res.truncated {
+		output += truncatedMarker(res.res.ContentLength, len(res.body))
+	}
+
+	return output, nil
+}
+
+// truncatedMarker returns the marker of a body cut to captured bytes, with the
+// remaining size when the content length is known.
+func truncatedMarker(contentLength int64, captured int) string {
+	if remaining := contentLength - int64(captured); remaining > 0 {
+		return fmt.Sprintf("...[truncated %s]", formatBytes(int(remaining)))
+	}
+
+	return "...[truncated]"
+}
+
+// capturedResponse is a http response, with its body buffered at capture time.
+type capturedResponse struct {
+	res       *http.Response
+	withBody  bool
+	body      []byte
+	truncated bool
+	err       error
+}
+
+// captureResponse buffers up to maxBodySize bytes (zero means unlimited) of the
+// response body, and restores res.Body, so that the caller can still read it.
+func captureResponse(res *http.Response, withBody bool, maxBodySize int) *capturedResponse {
+	captured := &capturedResponse{res: res, withBody: withBody}
+	if res == nil || !withBody || res.Body == nil || res.Body == http.NoBody {
+		captured.withBody = false
+		return captured
+	}
+
+	body, restored, truncated, err := captureBody(res.Body, maxBodySize)
+	res.Body = restored
+	if err != nil {
+		captured.err = err
+		return captured
+	}
+
+	captured.body = body
+	captured.truncated = truncated
+	return captured
+}
+
+// captureBody reads up to maxBodySize bytes (zero means unlimited) of body, cut on
+// a rune boundary. The returned reader replays the bytes read, then the rest of body.
+func captureBody(body io.ReadCloser, maxBodySize int) ([]byte, io.ReadCloser, bool, error) {
+	var buf []byte
+	var err error
+	if maxBodySize > 0 {
+		// read one more byte to detect truncation
+		buf, err = io.ReadAll(io.LimitReader(body, int64(maxBodySize)+1))
+	} else {
+		buf, err = io.ReadAll(body)
+	}
+
+	restored := &restoredBody{
+		Reader: io.MultiReader(bytes.NewReader(buf), body),
+		Closer: body,
+	}
+
+	if err != nil {
+		return nil, restored, false, err
+	}
+
+	if maxBodySize <= 0 || len(buf) <= maxBodySize {
+		return buf, restored, false, nil
+	}
+
+	n := maxBodySize
+	for n > 0 && !utf8.RuneStart(buf[n]) {
+		n--
+	}
+
+	return buf[:n], restored, true, nil
+}
+
+type restoredBody struct {
+	io.Reader
+	io.Closer
+}
+
+// scrubDump redacts the sensitive headers and the body matches of a http dump.
+func scrubDump(dump string) string {
+	head, body, hasBody := strings.Cut(dump, "\r\n\r\n")
+
+	lines := strings.Split(head, "\r\n")
+	for i, line := range lines {
+		name, _, ok := strings.Cut(line, ":")
+		if i == 0 || !ok {
+			// request or status line
+			continue
+		}
+
+		for _, header := range SensitiveHeaders {
+			if strings.EqualFold(strings.TrimSpace(name), header) {
+				lines[i] = name + ": " + RedactedValue
+				break
+			}
+		}
+	}
+
+	output := strings.Join(lines, "\r\n")
+	if !hasBody {
+		return output
+	}
+
+	for _, scrubber := range BodyScrubbers {
+		body = scrubber.ReplaceAllString(body, RedactedValue)
+	}
+
+	return output + "\r\n\r\n" + body
+}
+
+// truncateDumpBody cuts the body of a http dump to maxSize bytes, on a rune boundary.
+func truncateDumpBody(dump string, maxSize int) string {
+	head, body, hasBody := strings.Cut(dump, "\r\n\r\n")
+	if !hasBody || maxSize <= 0 || len(body) <= maxSize {
+		return dump
+	}
+
+	n := maxSize
+	for n > 0 && !utf8.RuneStart(body[n]) {
+		n--
+	}
+
+	return fmt.Sprintf("%s\r\n\r\n%s...[truncated %s]", head, body[:n], formatBytes(len(body)-n))
+}
+
+// formatBytes pretty prints a size in bytes (eg: "1.2MB").
+func formatBytes(size int) string {
+	const unit = 1000
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := unit, 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%cB", float64(size)/float64(div), "kMGT"[exp])
+}
@@ -0,0 +1,176 @@
+package oops
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDumpScrubbing(t *testing.T) {
+	is := assert.New(t)
+
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader(`{"user":"john","password":"hunter2"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("X-Request-Id", "1234")
+
+	res := &http.Response{
+		StatusCode: 200,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Set-Cookie": []string{"session=secret"}},
+		Body:       io.NopCloser(strings.NewReader("ok")),
+	}
+
+	BodyScrubbers = []*regexp.Regexp{regexp.MustCompile(`"password":"[^"]*"`)}
+	defer func() { BodyScrubbers = []*regexp.Regexp{} }()
+
+	err := new().Request(req, true).Response(res, true).Errorf("a message").(OopsError)
+
+	dump := err.ToMap()["request"].(string)
+	is.Contains(dump, "POST /foobar HTTP/1.1\r\n")
+	is.Contains(dump, "Authorization: [REDACTED]\r\n")
+	is.Contains(dump, "Cookie: [REDACTED]\r\n")
+	is.Contains(dump, "X-Request-Id: 1234\r\n")
+	is.Contains(dump, `{"user":"john",[REDACTED]}`)
+	is.NotContains(dump, "secret")
+	is.NotContains(dump, "hunter2")
+
+	dump = err.ToMap()["response"].(string)
+	is.Contains(dump, "Set-Cookie: [REDACTED]\r\n")
+	is.NotContains(dump, "secret")
+
+	is.Equal("Bearer secret", err.Request().Header.Get("Authorization"))
+}
+
+func TestDumpMaxBodySize(t *testing.T) {
+	is := assert.New(t)
+
+	body := strings.Repeat("a", 1_200_010)
+
+	// default limit
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader(body))
+	err := new().Request(req, true).Errorf("a message").(OopsError)
+	is.True(strings.HasSuffix(err.ToMap()["request"].(string), "\r\n\r\n"+body[:64_000]+"...[truncated 1.1MB]"))
+
+	defer func(size int) { MaxBodySize = size }(MaxBodySize)
+
+	// unlimited
+	MaxBodySize = 0
+	req, _ = http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader(body))
+	err = new().Request(req, true).Errorf("a message").(OopsError)
+	is.True(strings.HasSuffix(err.ToMap()["request"].(string), "\r\n\r\n"+body))
+
+	MaxBodySize = 10
+
+	req, _ = http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader(body))
+	err = new().Request(req, true).Errorf("a message").(OopsError)
+	is.Contains(err.ToMap()["request"], "Content-Length: 1200010\r\n")
+	is.True(strings.HasSuffix(err.ToMap()["request"].(string), "\r\n\r\naaaaaaaaaa...[truncated 1.2MB]"))
+
+	// per call limit
+	res := &http.Response{StatusCode: 200, ProtoMajor: 1, ProtoMinor: 1, ContentLength: 11, Body: io.NopCloser(strings.NewReader("hello world"))}
+	err = new().Response(res, true, 5).Errorf("a message").(OopsError)
+	is.True(strings.HasSuffix(err.ToMap()["response"].(string), "\r\n\r\nhello...[truncated 6B]"))
+
+	// cut on a rune boundary
+	is.Equal("head\r\n\r\né...[truncated 2B]", truncateDumpBody("head\r\n\r\néè", 3))
+
+	is.Equal("999B", formatBytes(999))
+	is.Equal("1.5kB", formatBytes(1500))
+	is.Equal("2.0GB", formatBytes(2_000_000_000))
+}
+
+func TestDumpResponseBodyRestored(t *testing.T) {
+	is := assert.New(t)
+
+	closed := false
+	body := &closeRecorder{Reader: strings.NewReader("hello world"), closed: &closed}
+	res := &http.Response{StatusCode: 200, ProtoMajor: 1, ProtoMinor: 1, Body: body}
+
+	err := new().Response(res, true, 5).Errorf("a message").(OopsError)
+
+	// the caller can still read the whole body
+	b, readErr := io.ReadAll(res.Body)
+	is.NoError(readErr)
+	is.Equal("hello world", string(b))
+	is.NoError(res.Body.Close())
+	is.True(closed)
+
+	// the dump does not depend on the stream anymore
+	is.True(strings.HasSuffix(err.ToMap()["response"].(string), "\r\n\r\nhello...[truncated]"))
+	is.True(strings.HasSuffix(err.ToMap()["response"].(string), "\r\n\r\nhello...[truncated]"))
+	is.Same(res, err.Response())
+
+	// without body
+	res = &http.Response{StatusCode: 200, ProtoMajor: 1, ProtoMinor: 1, Body: io.NopCloser(strings.NewReader("hello world"))}
+	err = new().Response(res, false).Errorf("a message").(OopsError)
+	is.NotContains(err.ToMap()["response"], "hello world")
+	b, _ = io.ReadAll(res.Body)
+	is.Equal("hello world", string(b))
+}
+
+func TestDumpRequestBodyRestored(t *testing.T) {
+	is := assert.New(t)
+
+	body := &countingReader{Reader: strings.NewReader("hello world")}
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", body)
+	req.ContentLength = 11
+
+	err := new().Request(req, true, 5).Errorf("a message").(OopsError)
+	is.True(strings.HasSuffix(err.ToMap()["request"].(string), "\r\n\r\nhello...[truncated 6B]"))
+
+	// only the limit (plus one byte to detect truncation) is read by the dump
+	is.Equal(6, body.n)
+
+	// the caller can still read the whole body
+	b, readErr := io.ReadAll(req.Body)
+	is.NoError(readErr)
+	is.Equal("hello world", string(b))
+}
+
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.n += n
+	return n, err
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed *bool
+}
+
+func (c *closeRecorder) Close() error {
+	*c.closed = true
+	return nil
+}
+
+func TestDumpRequestOnce(t *testing.T) {
+	is := assert.New(t)
+
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader("hello world"))
+	err := new().Request(req, true).Errorf("a message").(OopsError)
+
+	dump := err.ToMap()["request"].(string)
+	is.True(strings.HasSuffix(dump, "\r\n\r\nhello world"))
+
+	// the body is consumed and replaced once, on first serialization
+	body := req.Body
+	is.Equal(dump, err.ToMap()["request"])
+	is.Equal(dump, new().Wrap(err).(OopsError).ToMap()["request"])
+	is.True(body == req.Body)
+
+	// scrubbing is applied to the cached dump
+	defer func(size int) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 5
+	is.True(strings.HasSuffix(err.ToMap()["request"].(string), "\r\n\r\nhello...[truncated 6B]"))
+}
@@ -0,0 +1,215 @@
+package oops
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+var _ encoding.TextMarshaler = (*OopsError)(nil)
+var _ encoding.BinaryMarshaler = (*OopsError)(nil)
+var _ encoding.BinaryUnmarshaler = (*OopsError)(nil)
+
+// binaryPayload is the gob representation of an error. Maps and actions are
+// JSON encoded, since gob requires the registration of the concrete types of interface values.
+type binaryPayload struct {
+	Message     string
+	Code        string
+	Severity    SeverityLevel
+	Time        time.Time
+	Duration    time.Duration
+	Domain      string
+	Tags        []string
+	Trace       string
+	Span        string
+	Hint        string
+	Public      string
+	Owner       string
+	HTTPStatus  int
+	Retryable   string // "true", "false" or unset (gob drops false values)
+	Fingerprint string
+	Actions     []byte
+	Context     []byte
+	UserID      string
+	UserData    []byte
+	TenantID    string
+	TenantData  []byte
+}
+
+// MarshalText implements encoding.TextMarshaler. The summary of the error is
+// prefixed by its code (eg: "[iam_missing_permission] permission denied").
+func (o OopsError) MarshalText() ([]byte, error) {
+	if code := o.Code(); code != "" {
+		return []byte("[" + code + "] " + o.Error()), nil
+	}
+
+	return []byte(o.Error()), nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The error chain is flattened,
+// and values of redacted keys are not exported. Values that cannot be marshaled
+// are dropped and reported under the `oops_integration_error` context key. Http
+// request, response and stacktrace are not included.
+func (o OopsError) MarshalBinary() ([]byte, error) {
+	redactedKeys := o.redactedKeys()
+
+	userID, userData := o.User()
+	tenantID, tenantData := o.Tenant()
+
+	payload := binaryPayload{
+		Message:     o.message(func(s string) string { return s }),
+		Code:        o.Code(),
+		Severity:    o.Severity(),
+		Time:        o.Time(),
+		Duration:    o.Duration(),
+		Domain:      o.Domain(),
+		Tags:        o.Tags(),
+		Trace:       o.Trace(),
+		Span:        o.Span(),
+		Hint:        o.Hint(),
+		Public:      o.Public(),
+		Owner:       o.Owner(),
+		HTTPStatus:  o.HTTPStatus(),
+		Fingerprint: o.Fingerprint(),
+		UserID:      userID,
+		TenantID:    tenantID,
+	}
+
+	if retryable := o.retryableAttribute(); retryable != nil {
+		payload.Retryable = strconv.FormatBool(*retryable)
+	}
+
+	context := redactMap(o.Context(), redactedKeys)
+	userData = redactMap(userData, redactedKeys)
+	tenantData = redactMap(tenantData, redactedKeys)
+
+	// values that cannot be marshaled are dropped, and reported in the context,
+	// as MarshalJSON does
+	integrationErrors := []string{}
+	report := func(prefix string, m map[string]any) map[string]any {
+		m, e := dropUnmarshalableValues(m)
+		if e != "" {
+			integrationErrors = append(integrationErrors, prefix+e)
+		}
+		return m
+	}
+
+	userData = report("user ", userData)
+	tenantData = report("tenant ", tenantData)
+
+	actions := o.Actions()
+	for i := range actions {
+		if actions[i].Params != nil {
+			actions[i].Params = report("action "+actions[i].Name+" ", redactMap(actions[i].Params, redactedKeys))
+		}
+	}
+
+	context = report("", context)
+	if len(integrationErrors) > 0 {
+		if context == nil {
+			context = map[string]any{}
+		}
+		context[integrationErrorKey] = strings.Join(integrationErrors, "; ")
+	}
+
+	var err error
+	if payload.Actions, err = json.Marshal(actions); err != nil {
+		return nil, err
+	}
+
+	for _, m := range []struct {
+		dst  *[]byte
+		data map[string]any
+	}{
+		{&payload.Context, context},
+		{&payload.UserData, userData},
+		{&payload.TenantData, tenantData},
+	} {
+		if *m.dst, err = json.Marshal(m.data); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(payload); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Map values are decoded
+// from JSON, so numbers become float64. The decoded error has no stacktrace.
+func (o *OopsError) UnmarshalBinary(data []byte) error {
+	var payload binaryPayload
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&payload); err != nil {
+		return err
+	}
+
+	decoded := OopsError{
+		msg:         payload.Message,
+		code:        payload.Code,
+		severity:    payload.Severity,
+		time:        payload.Time,
+		duration:    payload.Duration,
+		domain:      payload.Domain,
+		tags:        payload.Tags,
+		trace:       payload.Trace,
+		span:        payload.Span,
+		hint:        payload.Hint,
+		public:      payload.Public,
+		owner:       payload.Owner,
+		httpStatus:  payload.HTTPStatus,
+		fingerprint: payload.Fingerprint,
+		userID:      payload.UserID,
+		tenantID:    payload.TenantID,
+	}
+
+	if retryable, err := strconv.ParseBool(payload.Retryable); err == nil {
+		decoded.retryable = &retryable
+	}
+
+	if len(payload.Actions) > 0 {
+		if err := json.Unmarshal(payload.Actions, &decoded.actions); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range []struct {
+		src []byte
+		dst *map[string]any
+	}{
+		{payload.Context, &decoded.context},
+		{payload.UserData, &decoded.userData},
+		{payload.TenantData, &decoded.tenantData},
+	} {
+		*m.dst = map[string]any{}
+		if err := json.Unmarshal(m.src, m.dst); err != nil {
+			return err
+		}
+	}
+
+	*o = decoded
+	return nil
+}
+
+// dropUnmarshalableValues removes the values of a map that cannot be marshaled
+// (see dropUnmarshalableAttributes), and returns the reported errors. The input
+// map is not modified.
+func dropUnmarshalableValues(m map[string]any) (map[string]any, string) {
+	if _, err := json.Marshal(m); err == nil {
+		return m, ""
+	}
+
+	m = dropUnmarshalableAttributes(lo.Assign(m))
+	report, _ := m[integrationErrorKey].(string)
+	delete(m, integrationErrorKey)
+
+	return m, report
+}
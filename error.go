@@ -1,22 +1,69 @@
 package oops
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"log/slog"
 	"net/http"
-	"net/http/httputil"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/oklog/ulid/v2"
 	"github.com/samber/lo"
 )
 
+// integrationErrorKey is the attribute describing enrichments that failed
+// while rendering an error (eg: a request dump or a json marshaling).
+const integrationErrorKey = "oops_integration_error"
+
 var (
 	SourceFragmentsHidden                = true
 	DereferencePointers                  = true
 	Local                 *time.Location = time.UTC
+
+	// EmitUnixTime adds a `time_unix_ms` attribute (milliseconds since epoch) next to
+	// `time` in ToMap() and LogValuer() outputs, for log backends indexing numeric dates.
+	EmitUnixTime = false
+
+	// EmitChain adds a `chain` attribute to ToMap() and LogValuer() outputs: the message
+	// and the attributes declared by each wrapped error, the outermost first. It lets
+	// Replay() rebuild the chain from a log entry.
+	EmitChain = false
+
+	// EmitFingerprint adds a `fingerprint` attribute (see OopsError.Fingerprint) to
+	// ToMap() and LogValuer() outputs, for log backends grouping errors.
+	EmitFingerprint = false
+
+	// AttributeResolution defines which error of a chain provides an attribute,
+	// when many wrapped errors declare it. It can be overridden per builder.
+	AttributeResolution = ResolutionDeepestFirst
+
+	// NilPanicAsFatal considers `panic(nil)` as a programming error: errors
+	// returned by Recover() get a fatal severity.
+	NilPanicAsFatal = false
+
+	// StrictErrorsIs changes the behavior of `errors.Is` when the target is an `oops.OopsError`:
+	// errors match only when they share the same code, or when they are the same error
+	// (copies of the value returned by a builder).
+	StrictErrorsIs = false
+
+	// MaxMessageLength limits the size (in bytes) of the message and of the wrapped
+	// error string in every output. Longer strings are cut and suffixed with an ellipsis,
+	// and the original length is reported in the `message_length` context attribute.
+	// Zero means unlimited.
+	MaxMessageLength = 0
+
+	// RedactedKeys lists the context, user and tenant keys (case insensitive) whose
+	// values are replaced by `[REDACTED]` in every output. It can be extended per builder.
+	RedactedKeys = []string{}
 )
 
 var _ error = (*OopsError)(nil)
@@ -24,21 +71,33 @@ var _ error = (*OopsError)(nil)
 type OopsError struct {
 	err      error
 	msg      string
+	format   string // message template, used for grouping
 	code     string
+	severity SeverityLevel
 	time     time.Time
 	duration time.Duration
 
 	// context
-	domain  string
-	tags    []string
-	context map[string]any
+	domain         string
+	tags           []string
+	context        map[string]any
+	contextParents []map[string]any // shared by reference with parent builders, see Child()
 
 	trace string
 	span  string
 
-	hint   string
-	public string
-	owner  string
+	hint    string
+	public  string
+	owner   string
+	actions []RemediationAction
+
+	httpStatus int
+	retryable  *bool
+
+	fingerprint string
+
+	// keys whose values are hidden in outputs
+	redactKeys []string
 
 	// user
 	userID     string
@@ -47,38 +106,188 @@ type OopsError struct {
 	tenantData map[string]any
 
 	// http
-	req *lo.Tuple2[*http.Request, bool]
-	res *lo.Tuple2[*http.Response, bool]
+	req     *lo.Tuple3[*http.Request, bool, int]
+	reqDump *requestDump // set with req
+	res     *capturedResponse
 
 	// stacktrace
-	stacktrace *oopsStacktrace
+	stacktrace         *oopsStacktrace
+	stacktraceDepth    int
+	stacktraceSkip     int
+	stacktraceDisabled bool
+	foreign            []foreignStacktrace
+	linked             *oopsStacktrace
+
+	// stacks of all goroutines
+	goroutines        []Goroutine
+	goroutinesEnabled bool
+
+	// see CaptureGoroutineInfo
+	goroutineID uint64
+	pprofLabels map[string]string
+
+	// attributes of a translated error are not overridden by wrapped errors
+	translated bool
+
+	// kind of the recovered panic: "nil", "error" or "value"
+	panicKind string
+
+	resolution ResolutionStrategy
+
+	// context extractors already applied to the builder
+	extractors []*contextExtractor
+
+	// deadline of the Go context, at the FromContext call (see EmitContextDeadline)
+	deadline *contextDeadline
+
+	// outputs of a replayed error, kept as logged (see Replay)
+	replayed *replayedOutputs
+
+	// memoized outputs, shared by the copies of the error (nil before hooks)
+	cache *errorCache
+}
+
+// errorCache holds the outputs of an error that are formatted once: errors are
+// immutable, and loggers call Error() and Stacktrace() several times per line.
+type errorCache struct {
+	error      cached[string]
+	stacktrace cached[string]
+	context    cached[map[string]any]
+
+	// see LazySpanID
+	spanOnce sync.Once
+	span     string
+
+	// see chain()
+	chainOnce sync.Once
+	chain     []OopsError
+}
+
+// cached is computed again when the settings it depends on change.
+type cached[T any] struct {
+	entry atomic.Pointer[cachedEntry[T]]
+}
+
+type cachedEntry[T any] struct {
+	settings cacheSettings
+	value    T
+}
+
+type cacheSettings struct {
+	maxMessageLength    int
+	dereferencePointers bool
+	attributeResolution ResolutionStrategy
+}
+
+func (c *cached[T]) get(compute func() T) T {
+	settings := cacheSettings{
+		maxMessageLength:    MaxMessageLength,
+		dereferencePointers: DereferencePointers,
+		attributeResolution: AttributeResolution,
+	}
+
+	if entry := c.entry.Load(); entry != nil && entry.settings == settings {
+		return entry.value
+	}
+
+	entry := &cachedEntry[T]{
+		settings: settings,
+		value:    compute(),
+	}
+	c.entry.Store(entry)
+
+	return entry.value
 }
 
+// RemediationAction describes a machine-readable remediation action, to be consumed by
+// automated remediation systems.
+type RemediationAction struct {
+	Name   string         `json:"name"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// ResolutionStrategy defines which error of a chain provides an attribute.
+type ResolutionStrategy int
+
+const (
+	// ResolutionDeepestFirst resolves attributes from the deepest error (default).
+	ResolutionDeepestFirst ResolutionStrategy = iota + 1
+	// ResolutionShallowFirst resolves attributes from the outermost error.
+	ResolutionShallowFirst
+)
+
 // Unwrap returns the underlying error.
 func (o OopsError) Unwrap() error {
 	return o.err
 }
 
+// UnwrapAll returns the underlying errors. When the error wraps the result of
+// errors.Join (or any error implementing `Unwrap() []error`), joined children
+// are returned directly.
+// Go does not allow a type to implement both `Unwrap() error` and `Unwrap() []error`,
+// hence the dedicated method.
+func (o OopsError) UnwrapAll() []error {
+	if o.err == nil {
+		return nil
+	}
+
+	if joined, ok := o.err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+
+	return []error{o.err}
+}
+
+// Is implements the interface used by errors.Is.
 func (c OopsError) Is(err error) bool {
+	// wrapped errors are checked by errors.Is itself
+	if target, ok := err.(Sentinel); ok {
+		return c.code != "" && c.code == string(target)
+	}
+
+	if target, ok := err.(OopsError); ok && StrictErrorsIs {
+		if code := target.Code(); code != "" {
+			return c.Code() == code
+		}
+
+		// the cache is created once per built error, and shared by its copies
+		return c.cache != nil && c.cache == target.cache
+	}
+
+	// comparing 2 values of the same uncomparable type (such as OopsError) would panic
+	if t := reflect.TypeOf(c.err); t != nil && !t.Comparable() {
+		return false
+	}
+
 	return c.err == err
 }
 
 // Error returns the error message, without context.
 func (o OopsError) Error() string {
+	if o.cache == nil {
+		return o.message(truncateMessage)
+	}
+
+	return o.cache.error.get(func() string {
+		return o.message(truncateMessage)
+	})
+}
+
+func (o OopsError) message(format func(string) string) string {
 	if o.err != nil {
 		if o.msg == "" {
-			return o.err.Error()
+			return format(o.err.Error())
 		}
 
-		return fmt.Sprintf("%s: %s", o.msg, o.err.Error())
+		return fmt.Sprintf("%s: %s", format(o.msg), format(o.err.Error()))
 	}
 
-	return o.msg
+	return format(o.msg)
 }
 
 // Code returns the error cause. Error code is intented to be used by machines.
 func (o OopsError) Code() string {
-	return getDeepestErrorAttribute(
+	return getTranslatedErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.code
@@ -86,9 +295,19 @@ func (o OopsError) Code() string {
 	)
 }
 
+// Severity returns the severity of the error.
+func (o OopsError) Severity() SeverityLevel {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) SeverityLevel {
+			return e.severity
+		},
+	)
+}
+
 // Time returns the time when the error occured.
 func (o OopsError) Time() time.Time {
-	return getDeepestErrorAttribute(
+	return getErrorAttribute(
 		o,
 		func(e OopsError) time.Time {
 			return e.time
@@ -98,7 +317,7 @@ func (o OopsError) Time() time.Time {
 
 // Duration returns the duration of the error.
 func (o OopsError) Duration() time.Duration {
-	return getDeepestErrorAttribute(
+	return getErrorAttribute(
 		o,
 		func(e OopsError) time.Duration {
 			return e.duration
@@ -108,7 +327,7 @@ func (o OopsError) Duration() time.Duration {
 
 // Domain returns the domain of the error.
 func (o OopsError) Domain() string {
-	return getDeepestErrorAttribute(
+	return getTranslatedErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.domain
@@ -129,42 +348,83 @@ func (o OopsError) Tags() []string {
 
 // Context returns a k/v context of the error.
 func (o OopsError) Context() map[string]any {
-	return dereferencePointers(
+	if o.cache == nil {
+		return o.resolveContext()
+	}
+
+	// lazy values are evaluated once: callers get a copy of the cached map
+	return copyNestedMap(o.cache.context.get(o.resolveContext))
+}
+
+func (o OopsError) resolveContext() map[string]any {
+	context := dereferencePointers(
 		lazyMapEvaluation(
 			mergeNestedErrorMap(
 				o,
 				func(e OopsError) map[string]any {
-					return e.context
+					return e.ownContext()
 				},
 			),
 		),
 	)
+
+	if MaxMessageLength > 0 {
+		if raw := o.message(func(s string) string { return s }); raw != o.Error() {
+			context["message_length"] = len(raw)
+		}
+	}
+
+	return context
+}
+
+func (o OopsError) ownContext() map[string]any {
+	context := o.context
+	if len(o.contextParents) > 0 {
+		maps := append(append([]map[string]any{}, o.contextParents...), o.context)
+		context = lo.Assign(maps...)
+	}
+
+	if o.deadline != nil {
+		context = o.deadline.fill(context)
+	}
+
+	return context
 }
 
 // Trace returns the transaction id, trace id, request id, correlation id, etc.
 func (o OopsError) Trace() string {
-	trace := getDeepestErrorAttribute(
+	trace := getErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.trace
 		},
 	)
 
-	if trace != "" {
+	// a replayed error without trace was logged without trace
+	if trace != "" || o.replayedOutputs() != nil {
 		return trace
 	}
 
-	return ulid.Make().String()
+	return newID()
 }
 
 // Span returns the current span instead of the deepest one.
 func (o OopsError) Span() string {
-	return o.span
+	if o.span != "" || o.cache == nil {
+		return o.span
+	}
+
+	// see LazySpanID
+	o.cache.spanOnce.Do(func() {
+		o.cache.span = newID()
+	})
+
+	return o.cache.span
 }
 
 // Hint returns a hint to the user on how to resolve the error.
 func (o OopsError) Hint() string {
-	return getDeepestErrorAttribute(
+	return getErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.hint
@@ -174,7 +434,7 @@ func (o OopsError) Hint() string {
 
 // Public returns a message that is safe to show to an end user.
 func (o OopsError) Public() string {
-	return getDeepestErrorAttribute(
+	return getTranslatedErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.public
@@ -184,7 +444,199 @@ func (o OopsError) Public() string {
 
 // Owner identify the owner responsible for resolving the error.
 func (o OopsError) Owner() string {
-	return getDeepestErrorAttribute(
+	return getErrorAttribute(
+		o,
+		func(e OopsError) string {
+			return e.owner
+		},
+	)
+}
+
+// HTTPStatus returns the http status code of the error, or 0.
+func (o OopsError) HTTPStatus() int {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) int {
+			return e.httpStatus
+		},
+	)
+}
+
+// Retryable returns false when the error has been marked as non-retryable
+// (see `oops.Retryable(false)`). Errors are retryable by default.
+func (o OopsError) Retryable() bool {
+	retryable := o.retryableAttribute()
+	return retryable == nil || *retryable
+}
+
+func (o OopsError) retryableAttribute() *bool {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) *bool {
+			return e.retryable
+		},
+	)
+}
+
+// Fingerprint returns a stable key for grouping similar errors (eg: in an error tracker).
+// Unless a custom fingerprint has been set, it is a hash of the code, the domain, the
+// message template and the top frames of the deepest stacktrace. Line numbers are ignored,
+// and frames are left out when stacktraces are sampled (see StackTraceSampling).
+func (o OopsError) Fingerprint() string {
+	if fingerprint := getErrorAttribute(o, func(e OopsError) string { return e.fingerprint }); fingerprint != "" {
+		return fingerprint
+	}
+
+	format := ""
+	var frames []oopsStacktraceFrame
+
+	recursive(o, func(e OopsError) {
+		if e.format != "" {
+			format = e.format
+		}
+		if e.stacktrace != nil && !e.stacktrace.sampled && len(e.stacktrace.getFrames()) > 0 {
+			frames = e.stacktrace.getFrames()
+		}
+	})
+
+	h := sha256.New()
+	for _, part := range []string{o.Code(), o.Domain(), format} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	for _, frame := range lo.Slice(frames, 0, 3) {
+		h.Write([]byte(frame.file + ":" + frame.function))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// StackHash returns a hash of the frames captured across the error chain, to
+// group identical failure paths even when messages differ. Line numbers are
+// not hashed, so that the hash is stable across unrelated changes of the
+// source files. It returns 0 when no stack trace has been captured.
+func (o OopsError) StackHash() uint64 {
+	h := fnv.New64a()
+	empty := true
+
+	recursive(o, func(e OopsError) {
+		if e.stacktrace == nil {
+			return
+		}
+
+		for _, frame := range e.stacktrace.getFrames() {
+			h.Write([]byte(frame.file + ":" + frame.function))
+			h.Write([]byte{0})
+			empty = false
+		}
+	})
+
+	if empty {
+		return 0
+	}
+
+	return h.Sum64()
+}
+
+// Actions returns the remediation actions of the error.
+func (o OopsError) Actions() []RemediationAction {
+	actions := []RemediationAction{}
+
+	recursive(o, func(e OopsError) {
+		actions = append(actions, e.actions...)
+	})
+
+	return actions
+}
+
+// PanicKind returns the kind of the recovered panic: "nil" for `panic(nil)`,
+// "error" for an error payload, "value" for any other payload, or an empty
+// string if the error does not come from a panic.
+func (o OopsError) PanicKind() string {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) string {
+			return e.panicKind
+		},
+	)
+}
+
+// IsNilPanic returns true if the error has been recovered from a `panic(nil)`.
+func (o OopsError) IsNilPanic() bool {
+	return o.PanicKind() == "nil"
+}
+
+// ShallowCode returns the code of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowCode() string {
+	return getShallowestErrorAttribute(
+		o,
+		func(e OopsError) string {
+			return e.code
+		},
+	)
+}
+
+// ShallowTime returns the time of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowTime() time.Time {
+	return getShallowestErrorAttribute(
+		o,
+		func(e OopsError) time.Time {
+			return e.time
+		},
+	)
+}
+
+// ShallowDuration returns the duration of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowDuration() time.Duration {
+	return getShallowestErrorAttribute(
+		o,
+		func(e OopsError) time.Duration {
+			return e.duration
+		},
+	)
+}
+
+// ShallowDomain returns the domain of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowDomain() string {
+	return getShallowestErrorAttribute(
+		o,
+		func(e OopsError) string {
+			return e.domain
+		},
+	)
+}
+
+// ShallowHint returns the hint of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowHint() string {
+	return getShallowestErrorAttribute(
+		o,
+		func(e OopsError) string {
+			return e.hint
+		},
+	)
+}
+
+// ShallowPublic returns the public message of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowPublic() string {
+	return getShallowestErrorAttribute(
+		o,
+		func(e OopsError) string {
+			return e.public
+		},
+	)
+}
+
+// ShallowOwner returns the owner of the outermost error declaring one,
+// instead of the deepest one.
+func (o OopsError) ShallowOwner() string {
+	return getShallowestErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.owner
@@ -194,7 +646,7 @@ func (o OopsError) Owner() string {
 
 // User returns the user id and user data.
 func (o OopsError) User() (string, map[string]any) {
-	userID := getDeepestErrorAttribute(
+	userID := getErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.userID
@@ -214,7 +666,7 @@ func (o OopsError) User() (string, map[string]any) {
 
 // Tenant returns the tenant id and tenant data.
 func (o OopsError) Tenant() (string, map[string]any) {
-	tenantID := getDeepestErrorAttribute(
+	tenantID := getErrorAttribute(
 		o,
 		func(e OopsError) string {
 			return e.tenantID
@@ -242,29 +694,38 @@ func (o OopsError) Request() *http.Request {
 	return nil
 }
 
-func (o OopsError) request() *lo.Tuple2[*http.Request, bool] {
-	return getDeepestErrorAttribute(
+func (o OopsError) request() *lo.Tuple3[*http.Request, bool, int] {
+	return getErrorAttribute(
 		o,
-		func(e OopsError) *lo.Tuple2[*http.Request, bool] {
+		func(e OopsError) *lo.Tuple3[*http.Request, bool, int] {
 			return e.req
 		},
 	)
 }
 
+func (o OopsError) requestDump() *requestDump {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) *requestDump {
+			return e.reqDump
+		},
+	)
+}
+
 // Response returns the http response.
 func (o OopsError) Response() *http.Response {
 	t := o.response()
 	if t != nil {
-		return t.A
+		return t.res
 	}
 
 	return nil
 }
 
-func (o OopsError) response() *lo.Tuple2[*http.Response, bool] {
-	return getDeepestErrorAttribute(
+func (o OopsError) response() *capturedResponse {
+	return getErrorAttribute(
 		o,
-		func(e OopsError) *lo.Tuple2[*http.Response, bool] {
+		func(e OopsError) *capturedResponse {
 			return e.res
 		},
 	)
@@ -272,18 +733,54 @@ func (o OopsError) response() *lo.Tuple2[*http.Response, bool] {
 
 // Stacktrace returns a pretty printed stacktrace of the error.
 func (o OopsError) Stacktrace() string {
+	if o.cache == nil {
+		return o.stacktraceString()
+	}
+
+	return o.cache.stacktrace.get(o.stacktraceString)
+}
+
+func (o OopsError) stacktraceString() string {
+	if replayed := o.replayedOutputs(); replayed != nil {
+		return replayed.stacktrace
+	}
+
 	blocks := []string{}
 	topFrame := ""
+	var printedLink *oopsStacktrace
 
 	recursive(o, func(e OopsError) {
-		if e.stacktrace != nil && len(e.stacktrace.frames) > 0 {
-			err := lo.TernaryF(e.err != nil, func() string { return e.err.Error() }, func() string { return "" })
-			msg := coalesceOrEmpty(e.msg, err, "Error")
-			block := fmt.Sprintf("%s\n%s", msg, e.stacktrace.String(topFrame))
+		hasFrames := e.stacktrace != nil && len(e.stacktrace.getFrames()) > 0
+		foreign := e.foreignStacktraces()
 
-			blocks = append([]string{block}, blocks...)
+		// errors of a chain built from the same builder share the linked stack
+		linked := e.linked
+		if linked == printedLink || (linked != nil && len(linked.getFrames()) == 0) {
+			linked = nil
+		}
+
+		if hasFrames || len(foreign) > 0 || linked != nil {
+			err := lo.TernaryF(e.err != nil, func() string { return truncateMessage(e.err.Error()) }, func() string { return "" })
+			msg := coalesceOrEmpty(truncateMessage(e.msg), err, "Error")
+			lines := []string{msg}
 
-			topFrame = e.stacktrace.frames[0].String()
+			if hasFrames {
+				lines = append(lines, e.stacktrace.String(topFrame))
+				topFrame = e.stacktrace.getFrames()[0].String()
+			}
+
+			for _, st := range foreign {
+				lines = append(lines, st.String())
+			}
+
+			if linked != nil {
+				for _, frame := range linked.getFrames() {
+					lines = append(lines, "  --- [linked] at "+frame.String())
+				}
+				printedLink = linked
+			}
+
+			blocks = append([]string{strings.Join(lines, "\n")}, blocks...)
 		}
 	})
 
@@ -294,16 +791,30 @@ func (o OopsError) Stacktrace() string {
 	return "Oops: " + strings.Join(blocks, "\nThrown: ")
 }
 
+// sourcesIfShown returns the source fragments of the error, or an empty string
+// when `oops.SourceFragmentsHidden` is set, without reading the files.
+func (o OopsError) sourcesIfShown() string {
+	if SourceFragmentsHidden {
+		return ""
+	}
+
+	return o.Sources()
+}
+
 // Sources returns the source fragments of the error.
 func (o OopsError) Sources() string {
+	if replayed := o.replayedOutputs(); replayed != nil {
+		return replayed.sources
+	}
+
 	blocks := [][]string{}
 
 	recursive(o, func(e OopsError) {
-		if e.stacktrace != nil && len(e.stacktrace.frames) > 0 {
+		if e.stacktrace != nil && !e.stacktrace.sampled && len(e.stacktrace.getFrames()) > 0 {
 			header, body := e.stacktrace.Source()
 
 			if e.msg != "" {
-				header = fmt.Sprintf("%s\n%s", e.msg, header)
+				header = fmt.Sprintf("%s\n%s", truncateMessage(e.msg), header)
 			}
 
 			if header != "" && len(body) > 0 {
@@ -329,7 +840,29 @@ func (o OopsError) Sources() string {
 
 // LogValuer returns a slog.Value for logging.
 func (o OopsError) LogValuer() slog.Value {
-	attrs := []slog.Attr{slog.String("message", o.msg)}
+	return o.LogValuerWith(LogValuerOptions{})
+}
+
+// LogValuerOptions customizes the layout of LogValuerWith.
+type LogValuerOptions struct {
+	// Rename maps attribute keys (eg: "code" -> "error.code"). An empty key
+	// omits the attribute.
+	Rename map[string]string
+
+	// FlatContext emits the context attributes at the top level, instead of
+	// a "context" group.
+	FlatContext bool
+
+	// OmitDumps omits the http request and response dumps.
+	OmitDumps bool
+
+	// EmitSpan emits the span id.
+	EmitSpan bool
+}
+
+// LogValuerWith returns a slog.Value for logging, with a custom layout.
+func (o OopsError) LogValuerWith(opts LogValuerOptions) slog.Value {
+	attrs := []slog.Attr{slog.String("message", truncateMessage(o.msg))}
 
 	if err := o.Error(); err != "" {
 		attrs = append(attrs, slog.String("err", err))
@@ -339,8 +872,16 @@ func (o OopsError) LogValuer() slog.Value {
 		attrs = append(attrs, slog.String("code", code))
 	}
 
+	if severity := o.Severity(); severity != "" {
+		attrs = append(attrs, slog.String("severity", string(severity)))
+	}
+
 	if t := o.Time(); t != (time.Time{}) {
 		attrs = append(attrs, slog.Time("time", t.In(Local)))
+
+		if EmitUnixTime {
+			attrs = append(attrs, slog.Int64("time_unix_ms", t.UnixMilli()))
+		}
 	}
 
 	if duration := o.Duration(); duration != 0 {
@@ -359,9 +900,9 @@ func (o OopsError) LogValuer() slog.Value {
 		attrs = append(attrs, slog.String("trace", trace))
 	}
 
-	// if span := o.Span(); span != "" {
-	// 	attrs = append(attrs, slog.String("span", span))
-	// }
+	if span := o.Span(); span != "" && opts.EmitSpan {
+		attrs = append(attrs, slog.String("span", span))
+	}
 
 	if hint := o.Hint(); hint != "" {
 		attrs = append(attrs, slog.String("hint", hint))
@@ -375,20 +916,40 @@ func (o OopsError) LogValuer() slog.Value {
 		attrs = append(attrs, slog.String("owner", owner))
 	}
 
-	if context := o.Context(); len(context) > 0 {
-		attrs = append(attrs,
-			slog.Group(
-				"context",
-				lo.ToAnySlice(
-					lo.MapToSlice(context, func(k string, v any) slog.Attr {
-						return slog.Any(k, v)
-					}),
-				)...,
-			),
-		)
+	if status := o.HTTPStatus(); status != 0 {
+		attrs = append(attrs, slog.Int("http_status", status))
+	}
+
+	if retryable := o.retryableAttribute(); retryable != nil {
+		attrs = append(attrs, slog.Bool("retryable", *retryable))
+	}
+
+	if EmitFingerprint {
+		attrs = append(attrs, slog.String("fingerprint", o.Fingerprint()))
+	}
+
+	if actions := o.Actions(); len(actions) > 0 {
+		attrs = append(attrs, slog.Any("actions", actions))
+	}
+
+	redactedKeys := o.redactedKeys()
+
+	if context := redactMap(o.Context(), redactedKeys); len(context) > 0 {
+		if opts.FlatContext {
+			attrs = append(attrs, mapToSlogAttrs(context)...)
+		} else {
+			attrs = append(attrs,
+				slog.Group(
+					"context",
+					lo.ToAnySlice(mapToSlogAttrs(context))...,
+				),
+			)
+		}
 	}
 
 	if userID, userData := o.User(); userID != "" || len(userData) > 0 {
+		userData = redactMap(userData, redactedKeys)
+
 		userPayload := []slog.Attr{}
 		if userID != "" {
 			userPayload = append(userPayload, slog.String("id", userID))
@@ -404,6 +965,8 @@ func (o OopsError) LogValuer() slog.Value {
 	}
 
 	if tenantID, tenantData := o.Tenant(); tenantID != "" || len(tenantData) > 0 {
+		tenantData = redactMap(tenantData, redactedKeys)
+
 		tenantPayload := []slog.Attr{}
 		if tenantID != "" {
 			tenantPayload = append(tenantPayload, slog.String("id", tenantID))
@@ -418,33 +981,86 @@ func (o OopsError) LogValuer() slog.Value {
 		attrs = append(attrs, slog.Group("tenant", lo.ToAnySlice(tenantPayload)...))
 	}
 
-	if req := o.request(); req != nil {
-		dump, e := httputil.DumpRequestOut(req.A, req.B)
-		if e == nil {
-			attrs = append(attrs, slog.String("request", string(dump)))
+	integrationErrors := []string{}
+
+	if !opts.OmitDumps {
+		if dump, e := o.dumpRequest(); e != nil {
+			integrationErrors = append(integrationErrors, e.Error())
+		} else if dump != "" {
+			attrs = append(attrs, slog.String("request", dump))
 		}
-	}
 
-	if res := o.response(); res != nil {
-		dump, e := httputil.DumpResponse(res.A, res.B)
-		if e == nil {
-			attrs = append(attrs, slog.String("response", string(dump)))
+		if dump, e := o.dumpResponse(); e != nil {
+			integrationErrors = append(integrationErrors, e.Error())
+		} else if dump != "" {
+			attrs = append(attrs, slog.String("response", dump))
 		}
 	}
 
+	if len(integrationErrors) > 0 {
+		attrs = append(attrs, slog.String(integrationErrorKey, strings.Join(integrationErrors, "; ")))
+	}
+
+	if EmitChain {
+		attrs = append(attrs, slog.Any("chain", o.chainLayers()))
+	}
+
 	if stacktrace := o.Stacktrace(); stacktrace != "" {
 		attrs = append(attrs, slog.String("stacktrace", stacktrace))
 	}
 
-	if sources := o.Sources(); sources != "" && !SourceFragmentsHidden {
+	if sources := o.sourcesIfShown(); sources != "" {
 		attrs = append(attrs, slog.String("sources", sources))
 	}
 
+	if len(opts.Rename) > 0 {
+		attrs = renameSlogAttrs(attrs, opts.Rename)
+	}
+
 	return slog.GroupValue(attrs...)
 }
 
+func renameSlogAttrs(attrs []slog.Attr, rename map[string]string) []slog.Attr {
+	output := make([]slog.Attr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		if key, ok := rename[attr.Key]; ok {
+			if key == "" {
+				continue
+			}
+			attr.Key = key
+		}
+
+		output = append(output, attr)
+	}
+
+	return output
+}
+
 // ToMap returns a map representation of the error.
 func (o OopsError) ToMap() map[string]any {
+	return o.ToMapWith(ToMapOptions{})
+}
+
+// ToMapOptions selects the keys exported by ToMapWith (eg: "code", "context",
+// "user", "request", "stacktrace"...).
+type ToMapOptions struct {
+	// Include restricts the output to the given keys. Empty means all keys.
+	Include []string
+	// Exclude removes the given keys from the output.
+	Exclude []string
+}
+
+func (opts ToMapOptions) selects(key string) bool {
+	return (len(opts.Include) == 0 || lo.Contains(opts.Include, key)) && !lo.Contains(opts.Exclude, key)
+}
+
+// ToMapWith returns a map representation of the error, restricted to the selected
+// keys. Unselected sections (eg: request dumps) are not computed.
+//
+//	audit := err.ToMapWith(oops.ToMapOptions{Include: []string{"code", "domain", "context"}})
+//	debug := err.ToMapWith(oops.ToMapOptions{Exclude: []string{"user", "tenant"}})
+func (o OopsError) ToMapWith(opts ToMapOptions) map[string]any {
 	payload := map[string]any{}
 
 	if err := o.Error(); err != "" {
@@ -455,8 +1071,16 @@ func (o OopsError) ToMap() map[string]any {
 		payload["code"] = code
 	}
 
+	if severity := o.Severity(); severity != "" {
+		payload["severity"] = string(severity)
+	}
+
 	if t := o.Time(); t != (time.Time{}) {
 		payload["time"] = t.In(Local)
+
+		if EmitUnixTime {
+			payload["time_unix_ms"] = t.UnixMilli()
+		}
 	}
 
 	if duration := o.Duration(); duration != 0 {
@@ -471,8 +1095,12 @@ func (o OopsError) ToMap() map[string]any {
 		payload["tags"] = tags
 	}
 
-	if context := o.Context(); len(context) > 0 {
-		payload["context"] = context
+	redactedKeys := o.redactedKeys()
+
+	if opts.selects("context") {
+		if context := redactMap(o.Context(), redactedKeys); len(context) > 0 {
+			payload["context"] = context
+		}
 	}
 
 	if trace := o.Trace(); trace != "" {
@@ -495,52 +1123,199 @@ func (o OopsError) ToMap() map[string]any {
 		payload["owner"] = owner
 	}
 
-	if userID, userData := o.User(); userID != "" || len(userData) > 0 {
-		user := lo.Assign(map[string]any{}, userData)
-		if userID != "" {
-			user["id"] = userID
+	if status := o.HTTPStatus(); status != 0 {
+		payload["http_status"] = status
+	}
+
+	if retryable := o.retryableAttribute(); retryable != nil {
+		payload["retryable"] = *retryable
+	}
+
+	if goroutineID := o.GoroutineID(); goroutineID != 0 {
+		payload["goroutine_id"] = goroutineID
+	}
+
+	if labels := o.PprofLabels(); len(labels) > 0 {
+		payload["pprof_labels"] = labels
+	}
+
+	if EmitFingerprint && opts.selects("fingerprint") {
+		payload["fingerprint"] = o.Fingerprint()
+	}
+
+	if actions := o.Actions(); len(actions) > 0 {
+		payload["actions"] = actions
+	}
+
+	if opts.selects("user") {
+		if userID, userData := o.User(); userID != "" || len(userData) > 0 {
+			userData = redactMap(userData, redactedKeys)
+
+			user := lo.Assign(map[string]any{}, userData)
+			if userID != "" {
+				user["id"] = userID
+			}
+
+			payload["user"] = user
 		}
+	}
+
+	if opts.selects("tenant") {
+		if tenantID, tenantData := o.Tenant(); tenantID != "" || len(tenantData) > 0 {
+			tenantData = redactMap(tenantData, redactedKeys)
 
-		payload["user"] = user
+			tenant := lo.Assign(map[string]any{}, tenantData)
+			if tenantID != "" {
+				tenant["id"] = tenantID
+			}
+
+			payload["tenant"] = tenant
+		}
 	}
 
-	if tenantID, tenantData := o.Tenant(); tenantID != "" || len(tenantData) > 0 {
-		tenant := lo.Assign(map[string]any{}, tenantData)
-		if tenantID != "" {
-			tenant["id"] = tenantID
+	integrationErrors := []string{}
+
+	if opts.selects("request") {
+		if dump, e := o.dumpRequest(); e != nil {
+			integrationErrors = append(integrationErrors, e.Error())
+		} else if dump != "" {
+			payload["request"] = dump
+		}
+	}
+
+	if opts.selects("response") {
+		if dump, e := o.dumpResponse(); e != nil {
+			integrationErrors = append(integrationErrors, e.Error())
+		} else if dump != "" {
+			payload["response"] = dump
 		}
+	}
+
+	if replayed := o.replayedOutputs(); replayed != nil {
+		integrationErrors = append(integrationErrors, replayed.integrationErrors...)
+	}
+
+	if len(integrationErrors) > 0 {
+		payload[integrationErrorKey] = strings.Join(integrationErrors, "; ")
+	}
 
-		payload["tenant"] = tenant
+	if EmitChain && opts.selects("chain") {
+		payload["chain"] = o.chainLayers()
 	}
 
-	if req := o.request(); req != nil {
-		dump, e := httputil.DumpRequestOut(req.A, req.B)
-		if e == nil {
-			payload["request"] = string(dump)
+	if opts.selects("stacktrace") {
+		if stacktrace := o.Stacktrace(); stacktrace != "" {
+			payload["stacktrace"] = stacktrace
 		}
 	}
 
-	if res := o.response(); res != nil {
-		dump, e := httputil.DumpResponse(res.A, res.B)
-		if e == nil {
-			payload["response"] = string(dump)
+	if opts.selects("sources") {
+		if sources := o.sourcesIfShown(); sources != "" {
+			payload["sources"] = sources
 		}
 	}
 
-	if stacktrace := o.Stacktrace(); stacktrace != "" {
-		payload["stacktrace"] = stacktrace
+	if opts.selects("goroutines") {
+		if goroutines := o.goroutinesString(); goroutines != "" {
+			payload["goroutines"] = goroutines
+		}
 	}
 
-	if sources := o.Sources(); sources != "" && !SourceFragmentsHidden {
-		payload["sources"] = sources
+	for k := range payload {
+		if !opts.selects(k) {
+			delete(payload, k)
+		}
 	}
 
 	return payload
 }
 
+// ToFlatMap returns a flat representation of the error, for exporters that cannot
+// handle nesting (eg: StatsD tags). Nested maps (context, user, tenant...) are
+// flattened into keys joined by the separator, and values are stringified.
+//
+//	err.ToFlatMap(".") // {"code": "...", "context.user_id": "1234", "user.id": "..."}
+func (o OopsError) ToFlatMap(separator string) map[string]string {
+	output := map[string]string{}
+	flattenMap(output, "", separator, o.ToMap())
+	return output
+}
+
+func flattenMap(output map[string]string, prefix string, separator string, data map[string]any) {
+	for k, v := range data {
+		key := k
+		if prefix != "" {
+			key = prefix + separator + k
+		}
+
+		switch value := v.(type) {
+		case map[string]any:
+			flattenMap(output, key, separator, value)
+		case []string:
+			output[key] = strings.Join(value, ",")
+		case []RemediationAction:
+			output[key] = strings.Join(lo.Map(value, func(action RemediationAction, _ int) string { return action.Name }), ",")
+		case time.Time:
+			output[key] = value.Format(time.RFC3339Nano)
+		default:
+			output[key] = fmt.Sprint(value)
+		}
+	}
+}
+
 // MarshalJSON implements json.Marshaler.
+// Attributes that cannot be marshaled are dropped and reported under the
+// `oops_integration_error` key, instead of failing the whole payload.
 func (o OopsError) MarshalJSON() ([]byte, error) {
-	return json.Marshal(o.ToMap())
+	payload := o.ToMap()
+
+	b, err := json.Marshal(payload)
+	if err == nil {
+		return b, nil
+	}
+
+	return json.Marshal(dropUnmarshalableAttributes(payload))
+}
+
+// WriteJSON encodes the error as a JSON object followed by a newline into w,
+// as `MarshalJSON()` does, without returning the intermediate bytes. It suits
+// log sinks expecting newline delimited JSON.
+func (o OopsError) WriteJSON(w io.Writer) error {
+	payload := o.ToMap()
+
+	// the encoder writes nothing when marshaling fails
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(payload)
+
+	var unsupportedType *json.UnsupportedTypeError
+	var unsupportedValue *json.UnsupportedValueError
+	var marshalerError *json.MarshalerError
+	if errors.As(err, &unsupportedType) || errors.As(err, &unsupportedValue) || errors.As(err, &marshalerError) {
+		return encoder.Encode(dropUnmarshalableAttributes(payload))
+	}
+
+	return err
+}
+
+// dropUnmarshalableAttributes removes the attributes that cannot be marshaled,
+// and reports them under the `oops_integration_error` key.
+func dropUnmarshalableAttributes(payload map[string]any) map[string]any {
+	integrationErrors := []string{}
+	if e, ok := payload[integrationErrorKey].(string); ok {
+		integrationErrors = append(integrationErrors, e)
+	}
+
+	for _, k := range lo.Keys(payload) {
+		if _, e := json.Marshal(payload[k]); e != nil {
+			integrationErrors = append(integrationErrors, fmt.Sprintf("%s marshaling: %s", k, e.Error()))
+			delete(payload, k)
+		}
+	}
+
+	sort.Strings(integrationErrors)
+	payload[integrationErrorKey] = strings.Join(integrationErrors, "; ")
+
+	return payload
 }
 
 // Format implements fmt.Formatter.
@@ -548,129 +1323,210 @@ func (o OopsError) MarshalJSON() ([]byte, error) {
 // Otherwise, using "%v", just the summary is included.
 func (o OopsError) Format(s fmt.State, verb rune) {
 	if verb == 'v' && s.Flag('+') {
-		fmt.Fprint(s, o.formatVerbose())
+		_, _ = o.writeVerbose(s, FormatterOptions{})
 	} else {
 		fmt.Fprint(s, o.formatSummary())
 	}
 }
 
-func (o *OopsError) formatVerbose() string {
-	output := fmt.Sprintf("Oops: %s\n", o.Error())
+func (o *OopsError) formatVerbose(opts FormatterOptions) string {
+	var output strings.Builder
+	_, _ = o.writeVerbose(&output, opts)
+	return output.String()
+}
+
+// writeVerbose streams the verbose representation of the error into w, without
+// building the whole output in memory.
+func (o *OopsError) writeVerbose(writer io.Writer, opts FormatterOptions) (int64, error) {
+	p := palette(opts.Color)
+	w := &verboseWriter{w: writer}
+
+	w.printf("%s: %s\n", p.label("Oops"), p.message(o.Error()))
 
 	if code := o.Code(); code != "" {
-		output += fmt.Sprintf("Code: %s\n", code)
+		w.printf("%s: %s\n", p.label("Code"), p.code(code))
+	}
+
+	if severity := o.Severity(); severity != "" {
+		w.printf("%s: %s\n", p.label("Severity"), severity)
 	}
 
 	if t := o.Time(); t != (time.Time{}) {
-		output += fmt.Sprintf("Time: %s\n", t.In(Local))
+		w.printf("%s: %s\n", p.label("Time"), t.In(Local))
 	}
 
 	if duration := o.Duration(); duration != 0 {
-		output += fmt.Sprintf("Duration: %s\n", duration.String())
+		w.printf("%s: %s\n", p.label("Duration"), duration.String())
 	}
 
 	if domain := o.Domain(); domain != "" {
-		output += fmt.Sprintf("Domain: %s\n", domain)
+		w.printf("%s: %s\n", p.label("Domain"), domain)
 	}
 
 	if tags := o.Tags(); len(tags) > 0 {
-		output += fmt.Sprintf("Tags: %s\n", strings.Join(tags, ", "))
+		w.printf("%s: %s\n", p.label("Tags"), strings.Join(tags, ", "))
 	}
 
 	if trace := o.Trace(); trace != "" {
-		output += fmt.Sprintf("Trace: %s\n", trace)
+		w.printf("%s: %s\n", p.label("Trace"), trace)
 	}
 
 	// if span := o.Span(); span != "" {
-	// 	output += fmt.Sprintf("Span: %s\n", span)
+	// 	w.printf("Span: %s\n", span)
 	// }
 
 	if hint := o.Hint(); hint != "" {
-		output += fmt.Sprintf("Hint: %s\n", hint)
+		w.printf("%s: %s\n", p.label("Hint"), hint)
 	}
 
 	if owner := o.Owner(); owner != "" {
-		output += fmt.Sprintf("Owner: %s\n", owner)
+		w.printf("%s: %s\n", p.label("Owner"), owner)
 	}
 
-	if context := o.Context(); len(context) > 0 {
-		output += "Context:\n"
-		for k, v := range context {
-			output += fmt.Sprintf("  * %s: %v\n", k, v)
+	if status := o.HTTPStatus(); status != 0 {
+		w.printf("%s: %d\n", p.label("HTTP status"), status)
+	}
+
+	if retryable := o.retryableAttribute(); retryable != nil {
+		w.printf("%s: %t\n", p.label("Retryable"), *retryable)
+	}
+
+	if goroutineID := o.GoroutineID(); goroutineID != 0 {
+		w.printf("%s: %d\n", p.label("Goroutine"), goroutineID)
+	}
+
+	if labels := o.PprofLabels(); len(labels) > 0 {
+		w.write(p.label("Pprof labels") + ":\n")
+		for _, k := range sortedKeys(labels) {
+			w.printf("  * %s: %s\n", k, labels[k])
+		}
+	}
+
+	if actions := o.Actions(); len(actions) > 0 {
+		w.write(p.label("Actions") + ":\n")
+		for _, action := range actions {
+			w.printf("  * %s: %v\n", action.Name, action.Params)
+		}
+	}
+
+	redactedKeys := o.redactedKeys()
+
+	if context := redactMap(o.Context(), redactedKeys); len(context) > 0 {
+		w.write(p.label("Context") + ":\n")
+		for _, k := range sortedKeys(context) {
+			w.printf("  * %s: %v\n", k, context[k])
 		}
 	}
 
 	if userID, userData := o.User(); userID != "" || len(userData) > 0 {
-		output += "User:\n"
+		userData = redactMap(userData, redactedKeys)
+
+		w.write(p.label("User") + ":\n")
 
 		if userID != "" {
-			output += fmt.Sprintf("  * id: %s\n", userID)
+			w.printf("  * id: %s\n", userID)
 		}
 
-		for k, v := range userData {
-			output += fmt.Sprintf("  * %s: %v\n", k, v)
+		for _, k := range sortedKeys(userData) {
+			w.printf("  * %s: %v\n", k, userData[k])
 		}
 	}
 
 	if tenantID, tenantData := o.Tenant(); tenantID != "" || len(tenantData) > 0 {
-		output += "Tenant:\n"
+		tenantData = redactMap(tenantData, redactedKeys)
+
+		w.write(p.label("Tenant") + ":\n")
 
 		if tenantID != "" {
-			output += fmt.Sprintf("  * id: %s\n", tenantID)
+			w.printf("  * id: %s\n", tenantID)
 		}
 
-		for k, v := range tenantData {
-			output += fmt.Sprintf("  * %s: %v\n", k, v)
+		for _, k := range sortedKeys(tenantData) {
+			w.printf("  * %s: %v\n", k, tenantData[k])
 		}
 	}
 
-	if req := o.request(); req != nil {
-		dump, e := httputil.DumpRequestOut(req.A, req.B)
-		if e == nil {
-			lines := strings.Split(string(dump), "\n")
-			lines = lo.Map(lines, func(line string, _ int) string {
-				return "  * " + line
-			})
-			output += fmt.Sprintf("Request:\n%s\n", strings.Join(lines, "\n"))
-		}
+	integrationErrors := []string{}
+
+	if dump, e := o.dumpRequest(); e != nil {
+		integrationErrors = append(integrationErrors, e.Error())
+	} else if dump != "" {
+		w.write(p.label("Request") + ":\n")
+		w.lines(dump, "  * ")
 	}
 
-	if res := o.response(); res != nil {
-		dump, e := httputil.DumpResponse(res.A, res.B)
-		if e == nil {
-			lines := strings.Split(string(dump), "\n")
-			lines = lo.Map(lines, func(line string, _ int) string {
-				return "  * " + line
-			})
-			output += fmt.Sprintf("Response:\n%s\n", strings.Join(lines, "\n"))
+	if dump, e := o.dumpResponse(); e != nil {
+		integrationErrors = append(integrationErrors, e.Error())
+	} else if dump != "" {
+		w.write(p.label("Response") + ":\n")
+		w.lines(dump, "  * ")
+	}
+
+	if replayed := o.replayedOutputs(); replayed != nil {
+		integrationErrors = append(integrationErrors, replayed.integrationErrors...)
+	}
+
+	if len(integrationErrors) > 0 {
+		w.write(p.label("Integration errors") + ":\n")
+		for _, e := range integrationErrors {
+			w.printf("  * %s\n", e)
 		}
 	}
 
 	if stacktrace := o.Stacktrace(); stacktrace != "" {
-		lines := strings.Split(stacktrace, "\n")
-		stacktrace = "  " + strings.Join(lines, "\n  ")
-		output += fmt.Sprintf("Stacktrace:\n%s\n", stacktrace)
+		w.write(p.label("Stacktrace") + ":\n")
+		w.lines(stacktrace, "  ")
 	}
 
-	if sources := o.Sources(); sources != "" && !SourceFragmentsHidden {
-		output += fmt.Sprintf("Sources:\n%s\n", sources)
+	if sources := o.sourcesIfShown(); sources != "" {
+		w.printf("%s:\n%s\n", p.label("Sources"), p.sources(sources))
 	}
 
-	return output
+	if goroutines := o.goroutinesString(); goroutines != "" {
+		w.write(p.label("Goroutines") + ":\n")
+		w.lines(goroutines, "  ")
+	}
+
+	return w.n, w.err
 }
 
 func (o *OopsError) formatSummary() string {
 	return o.Error()
 }
 
+// recursive calls tap on each error of the chain, the outermost first.
 func recursive(err OopsError, tap func(OopsError)) {
-	tap(err)
+	for _, e := range err.chain() {
+		tap(e)
+	}
+}
 
-	if err.err == nil {
-		return
+// chain returns the errors of the chain, the outermost first. The chain is
+// walked once per error, and reused by all getters.
+func (o OopsError) chain() []OopsError {
+	if o.cache == nil {
+		return walkChain(o)
 	}
 
-	if child, ok := AsOops(err.err); ok {
-		recursive(child, tap)
+	o.cache.chainOnce.Do(func() {
+		o.cache.chain = walkChain(o)
+	})
+
+	return o.cache.chain
+}
+
+func walkChain(err OopsError) []OopsError {
+	chain := []OopsError{err}
+
+	for err.err != nil {
+		child, ok := AsOops(err.err)
+		if !ok {
+			break
+		}
+
+		chain = append(chain, child)
+		err = child
 	}
+
+	return chain
 }
@@ -1,10 +1,19 @@
 package oops
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/fs"
+	"log/slog"
+	"net/http"
+	"runtime"
 	"testing"
+	"testing/iotest"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +75,226 @@ func TestErrorsAs(t *testing.T) {
 	}, "Error: %w", assert.AnError)
 	is.True(errors.As(err, &target))
 }
+
+func TestErrorsUnwrapAll(t *testing.T) {
+	is := assert.New(t)
+
+	err := Join(fs.ErrExist, assert.AnError)
+	is.Equal([]error{fs.ErrExist, assert.AnError}, err.(OopsError).UnwrapAll())
+
+	err = Wrap(fs.ErrExist)
+	is.Equal([]error{fs.ErrExist}, err.(OopsError).UnwrapAll())
+
+	err = Errorf("permission denied")
+	is.Len(err.(OopsError).UnwrapAll(), 1)
+
+	is.Nil(OopsError{}.UnwrapAll())
+}
+
+func TestErrorsIsStrict(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { StrictErrorsIs = false }()
+
+	err1 := Code("iam_missing_permission").Errorf("permission denied")
+	err2 := Code("iam_missing_permission").Errorf("permission denied")
+	err3 := Code("iam_unknown_user").Errorf("unknown user")
+	err4 := Errorf("permission denied")
+
+	StrictErrorsIs = false
+	is.False(errors.Is(Wrap(err1), err2))
+	is.False(errors.Is(Wrap(err1), err4))
+
+	StrictErrorsIs = true
+	is.True(errors.Is(Wrap(err1), err1))
+	is.True(errors.Is(Wrap(err1), err2))
+	is.False(errors.Is(Wrap(err1), err3))
+	is.True(errors.Is(Wrap(err4), err4))
+	is.False(errors.Is(Wrap(err1), err4))
+	is.True(errors.Is(Wrap(fs.ErrExist), fs.ErrExist))
+
+	// without stacktrace
+	err5 := WithoutStacktrace().Errorf("permission denied")
+	err6 := WithoutStacktrace().Errorf("permission denied")
+	is.True(errors.Is(err5, err5))
+	is.True(errors.Is(WithoutStacktrace().Wrap(err5), err5))
+	is.False(errors.Is(err5, err6))
+	is.False(errors.Is(err4, err5))
+
+	// errors of the same span are different errors
+	builder := WithoutStacktrace().Span("1234")
+	err7 := builder.Errorf("permission denied")
+	err8 := builder.Errorf("permission denied")
+	is.True(errors.Is(err7, err7))
+	is.False(errors.Is(err7, err8))
+	is.False(errors.Is(OopsError{}, OopsError{}))
+}
+
+func TestErrorsRecoverPanicKind(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { NilPanicAsFatal = false }()
+
+	err := Recover(func() {
+		panic(nil)
+	})
+	is.Error(err)
+	is.True(err.(OopsError).IsNilPanic())
+	is.Equal("nil", err.(OopsError).PanicKind())
+	is.Equal(SeverityUnknown, err.(OopsError).Severity())
+
+	var panicNil *runtime.PanicNilError
+	is.True(errors.As(err, &panicNil))
+
+	NilPanicAsFatal = true
+	err = Recoverf(func() {
+		panic(nil)
+	}, "unexpected error")
+	is.True(err.(OopsError).IsNilPanic())
+	is.Equal(SeverityFatal, err.(OopsError).Severity())
+
+	err = Recover(func() {
+		panic(fs.ErrExist)
+	})
+	is.False(err.(OopsError).IsNilPanic())
+	is.Equal("error", err.(OopsError).PanicKind())
+	is.Equal(SeverityUnknown, err.(OopsError).Severity())
+
+	err = Recover(func() {
+		panic("caramba!")
+	})
+	is.Equal("value", err.(OopsError).PanicKind())
+	is.Equal("caramba!", err.Error())
+
+	is.Empty(Errorf("permission denied").(OopsError).PanicKind())
+}
+
+func TestMaxMessageLength(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { MaxMessageLength = 0 }()
+
+	err := new().Wrapf(errors.New("0123456789"), "abcdefghij").(OopsError)
+
+	MaxMessageLength = 0
+	is.Equal("abcdefghij: 0123456789", err.Error())
+	is.NotContains(err.Context(), "message_length")
+
+	MaxMessageLength = 4
+	is.Equal("abcd…: 0123…", err.Error())
+	is.Equal(22, err.Context()["message_length"])
+	is.Equal("abcd…: 0123…", err.ToMap()["error"])
+	is.Contains(err.Stacktrace(), "Oops: abcd…\n")
+
+	MaxMessageLength = 10
+	is.Equal("abcdefghij: 0123456789", err.Error())
+	is.NotContains(err.Context(), "message_length")
+
+	// cut on a rune boundary
+	MaxMessageLength = 2
+	is.Equal("é…", new().Errorf("éèà").Error())
+}
+
+type countingError struct {
+	calls *int
+}
+
+func (e countingError) Error() string {
+	*e.calls++
+	return "connection refused"
+}
+
+func TestErrorCache(t *testing.T) {
+	is := assert.New(t)
+
+	calls := 0
+	err := new().Wrapf(countingError{calls: &calls}, "could not fetch user").(OopsError)
+
+	is.Equal("could not fetch user: connection refused", err.Error())
+	is.Equal("could not fetch user: connection refused", err.Error())
+	is.Equal(1, calls)
+
+	is.Equal(err.Stacktrace(), err.Stacktrace())
+	is.Equal(2, calls)
+
+	// shared by the copies of the error
+	var copied error = err
+	is.Equal("could not fetch user: connection refused", copied.Error())
+	is.Equal(2, calls)
+
+	// computed again when the message length limit changes
+	defer func() { MaxMessageLength = 0 }()
+	MaxMessageLength = 4
+	is.Equal("coul…: conn…", err.Error())
+	is.Equal(3, calls)
+
+	// builders derived from the error have their own cache
+	is.Equal("coul…: conn…", OopsErrorBuilder(err).Code("network").Wrapf(err.err, "could not fetch user").Error())
+	is.Equal(4, calls)
+}
+
+func BenchmarkErrorCache(b *testing.B) {
+	err := new().Wrapf(errors.New("connection refused"), "could not fetch user")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+		_ = err.(OopsError).Stacktrace()
+	}
+}
+
+func TestIntegrationErrors(t *testing.T) {
+	is := assert.New(t)
+
+	// request dump failure
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", iotest.ErrReader(assert.AnError))
+	err := new().Code("dump").Request(req, true).Errorf("a message").(OopsError)
+
+	payload := err.ToMap()
+	is.Equal("a message", payload["error"])
+	is.Equal("dump", payload["code"])
+	is.NotContains(payload, "request")
+	is.Equal("request dump: "+assert.AnError.Error(), payload["oops_integration_error"])
+
+	attrs := lo.Associate(err.LogValuer().Group(), func(attr slog.Attr) (string, string) {
+		return attr.Key, attr.Value.String()
+	})
+	is.Equal("a message", attrs["err"])
+	is.NotContains(attrs, "request")
+	is.Equal("request dump: "+assert.AnError.Error(), attrs["oops_integration_error"])
+
+	verbose := fmt.Sprintf("%+v", err)
+	is.Contains(verbose, "Oops: a message\n")
+	is.NotContains(verbose, "Request:\n")
+	is.Contains(verbose, "Integration errors:\n  * request dump: "+assert.AnError.Error()+"\n")
+
+	// response dump failure
+	res := &http.Response{StatusCode: 200, ProtoMajor: 1, ProtoMinor: 1, Body: io.NopCloser(iotest.ErrReader(assert.AnError))}
+	err = new().Response(res, true).Errorf("a message").(OopsError)
+
+	payload = err.ToMap()
+	is.Equal("a message", payload["error"])
+	is.NotContains(payload, "response")
+	is.Equal("response dump: "+assert.AnError.Error(), payload["oops_integration_error"])
+
+	// json marshaling failure
+	err = new().Code("marshal").Trace("1234").With("chan", make(chan int)).Errorf("a message").(OopsError)
+
+	got, jsonErr := json.Marshal(err)
+	is.NoError(jsonErr)
+
+	var decoded map[string]any
+	is.NoError(json.Unmarshal(got, &decoded))
+	is.Equal("a message", decoded["error"])
+	is.Equal("marshal", decoded["code"])
+	is.NotContains(decoded, "context")
+	is.Equal("context marshaling: json: unsupported type: chan int", decoded["oops_integration_error"])
+
+	var buf bytes.Buffer
+	is.NoError(err.WriteJSON(&buf))
+	is.Equal(string(got)+"\n", buf.String())
+
+	// no failure
+	err = new().Errorf("a message").(OopsError)
+	is.NotContains(err.ToMap(), "oops_integration_error")
+}
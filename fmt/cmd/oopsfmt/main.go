@@ -0,0 +1,61 @@
+// Command oopsfmt renders oops errors serialized as JSON, read from files or
+// from stdin, in the human verbose format.
+//
+//	kubectl logs deploy/api | oopsfmt
+//	oopsfmt -key fields.error export.ndjson
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	oopsfmt "github.com/samber/oops/fmt"
+)
+
+func main() {
+	color := flag.Bool("color", isTerminal(os.Stdout), "highlight the output with ANSI colors")
+	key := flag.String("key", "", "dotted path of the error in log entries (eg: \"error\")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: oopsfmt [flags] [file...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	opts := oopsfmt.Options{
+		Color: *color,
+		Key:   *key,
+	}
+
+	if flag.NArg() == 0 {
+		run(os.Stdin, opts)
+		return
+	}
+
+	for _, path := range flag.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fail(err)
+		}
+
+		run(f, opts)
+		f.Close()
+	}
+}
+
+func run(r io.Reader, opts oopsfmt.Options) {
+	if err := oopsfmt.Stream(os.Stdout, r, opts); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "oopsfmt: %s\n", err.Error())
+	os.Exit(1)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,99 @@
+package oopsfmt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// Options customizes the rendering of serialized errors.
+type Options struct {
+	// Color highlights the message, the code and the error lines of source
+	// fragments with ANSI escape codes, for terminal output.
+	Color bool
+	// Key is the dotted path of the error in log entries (eg: "error" or
+	// "fields.err"). By default, the error is looked up in the "error" and "err"
+	// attributes of the entry, and the entry itself is used as a fallback.
+	Key string
+}
+
+// Format renders the JSON of an error, as produced by `err.MarshalJSON()` or by
+// the logger integrations, in the verbose format of `fmt.Sprintf("%+v", err)`.
+// The error is rebuilt with `oops.Replay`.
+//
+//	output, err := oopsfmt.Format([]byte(`{"error":"permission denied","code":"iam_missing_permission"}`))
+func Format(data []byte) (string, error) {
+	return FormatWith(data, Options{})
+}
+
+// FormatWith renders the JSON of an error in the verbose format, customized by
+// opts.
+func FormatWith(data []byte, opts Options) (string, error) {
+	if opts.Key != "" {
+		payload, err := locate(data, opts.Key)
+		if err != nil {
+			return "", err
+		}
+
+		data = payload
+	}
+
+	err, e := oops.Replay(data)
+	if e != nil {
+		return "", e
+	}
+
+	return err.FormatWith(oops.FormatterOptions{Color: opts.Color}), nil
+}
+
+// Stream renders the newline delimited JSON entries read from r into w, for
+// log files and `kubectl logs` outputs. Lines that are not JSON objects are
+// copied unchanged, and entries are separated by an empty line.
+func Stream(w io.Writer, r io.Reader, opts Options) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		output, err := FormatWith(line, opts)
+		if err != nil {
+			output = string(line) + "\n"
+		} else {
+			output += "\n"
+		}
+
+		if _, err := io.WriteString(w, output); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// locate returns the JSON of the object at the dotted path key of the entry.
+func locate(data []byte, key string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep integers as they were logged (eg: ids, http statuses)
+	decoder.UseNumber()
+
+	var current map[string]any
+	if err := decoder.Decode(&current); err != nil {
+		return nil, fmt.Errorf("oopsfmt: %w", err)
+	}
+
+	for _, part := range strings.Split(key, ".") {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("oopsfmt: no error object at %q", key)
+		}
+		current = next
+	}
+
+	return json.Marshal(current)
+}
@@ -0,0 +1,93 @@
+package oopsfmt
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("iam_missing_permission").
+		In("authz").
+		Trace("1234").
+		Hint("ask an admin").
+		Public("you cannot do that").
+		With("user_id", 1234567890, "action", "read").
+		User("user-123", "email", "john@example.com").
+		Errorf("permission denied")
+
+	data, e := err.(oops.OopsError).MarshalJSON()
+	is.NoError(e)
+
+	output, e := Format(data)
+	is.NoError(e)
+	is.True(strings.HasPrefix(output, "Oops: permission denied\nCode: iam_missing_permission\n"))
+	is.Contains(output, "Domain: authz\nTrace: 1234\nHint: ask an admin\n")
+	is.Contains(output, "Context:\n  * action: read\n  * user_id: 1234567890\n")
+	is.Contains(output, "User:\n  * id: user-123\n  * email: john@example.com\n")
+	is.Contains(output, "Stacktrace:\n  Oops: permission denied\n")
+	is.NotContains(output, "\033[")
+
+	// the output of the core formatter
+	is.Equal(err.(oops.OopsError).FormatWith(oops.FormatterOptions{}), output)
+
+	output, e = FormatWith(data, Options{Color: true})
+	is.NoError(e)
+	is.Contains(output, "\033[36mOops\033[0m: \033[1m\033[31mpermission denied\033[0m\n")
+	is.Contains(output, "\033[36mCode\033[0m: \033[1m\033[33miam_missing_permission\033[0m\n")
+	is.Equal(err.(oops.OopsError).FormatWith(oops.FormatterOptions{Color: true}), output)
+
+	_, e = Format([]byte(`not json`))
+	is.Error(e)
+	_, e = Format([]byte(`null`))
+	is.Error(e)
+}
+
+func TestFormatLogEntry(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("iam_missing_permission").
+		Trace("1234").
+		Duration(1500000000).
+		Errorf("permission denied")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error(err.Error(), slog.Any("error", err))
+
+	output, e := Format(buf.Bytes())
+	is.NoError(e)
+	is.True(strings.HasPrefix(output, "Oops: permission denied\nCode: iam_missing_permission\n"))
+	is.Contains(output, "Duration: 1.5s\n")
+	is.Contains(output, "Trace: 1234\n")
+
+	// custom path
+	output, e = FormatWith([]byte(`{"fields":{"error":{"error":"permission denied","code":"iam_missing_permission"}}}`), Options{Key: "fields.error"})
+	is.NoError(e)
+	is.Equal("Oops: permission denied\nCode: iam_missing_permission\n", output)
+
+	_, e = FormatWith([]byte(`{"fields":{}}`), Options{Key: "fields.error"})
+	is.EqualError(e, `oopsfmt: no error object at "fields.error"`)
+}
+
+func TestStream(t *testing.T) {
+	is := assert.New(t)
+
+	input := strings.Join([]string{
+		`{"error":"permission denied","code":"iam_missing_permission"}`,
+		`server started`,
+		`{"error":"not found"}`,
+	}, "\n")
+
+	var buf bytes.Buffer
+	is.NoError(Stream(&buf, strings.NewReader(input), Options{}))
+	is.Equal("Oops: permission denied\nCode: iam_missing_permission\n\nserver started\nOops: not found\n\n", buf.String())
+}
@@ -0,0 +1,142 @@
+package oops
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// FormatterOptions customizes the verbose output of `err.FormatWith()`.
+type FormatterOptions struct {
+	// Color highlights the message, the code and the error lines of source
+	// fragments with ANSI escape codes, for terminal output.
+	Color bool
+}
+
+// FormatWith returns the verbose representation of the error, as printed by
+// `fmt.Sprintf("%+v", err)`, customized by opts.
+//
+//	fmt.Fprintln(os.Stderr, err.FormatWith(oops.FormatterOptions{Color: true}))
+func (o OopsError) FormatWith(opts FormatterOptions) string {
+	return o.formatVerbose(opts)
+}
+
+// WriteTo implements io.WriterTo. It streams the verbose representation of the
+// error, as printed by `fmt.Sprintf("%+v", err)`, into w.
+func (o OopsError) WriteTo(w io.Writer) (int64, error) {
+	return o.writeVerbose(w, FormatterOptions{})
+}
+
+// WriteWith streams the verbose representation of the error into w, customized
+// by opts. See FormatWith.
+func (o OopsError) WriteWith(w io.Writer, opts FormatterOptions) (int64, error) {
+	return o.writeVerbose(w, opts)
+}
+
+// verboseWriter counts the bytes written to w, and stops at the first error.
+type verboseWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (w *verboseWriter) write(s string) {
+	if w.err != nil {
+		return
+	}
+
+	n, err := io.WriteString(w.w, s)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *verboseWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
+
+// lines writes each line of s, prefixed with indent.
+func (w *verboseWriter) lines(s string, indent string) {
+	for {
+		line, rest, more := strings.Cut(s, "\n")
+		w.write(indent + line + "\n")
+
+		if !more {
+			return
+		}
+		s = rest
+	}
+}
+
+// sortedKeys returns the keys of a map in order, for a stable verbose output.
+func sortedKeys[V any](data map[string]V) []string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// palette paints the verbose output when enabled.
+type palette bool
+
+func (p palette) paint(s string, codes ...string) string {
+	if !p || s == "" {
+		return s
+	}
+
+	return strings.Join(codes, "") + s + ansiReset
+}
+
+func (p palette) label(s string) string {
+	return p.paint(s, ansiCyan)
+}
+
+func (p palette) message(s string) string {
+	return p.paint(s, ansiBold, ansiRed)
+}
+
+func (p palette) code(s string) string {
+	return p.paint(s, ansiBold, ansiYellow)
+}
+
+// sources highlights the error lines of source fragments: the lines followed
+// by a `^^^` marker.
+func (p palette) sources(s string) string {
+	if !p {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if i == 0 || !isSourceMarker(line) {
+			continue
+		}
+
+		lines[i-1] = p.paint(lines[i-1], ansiBold, ansiRed)
+		lines[i] = p.paint(line, ansiRed)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isSourceMarker(line string) bool {
+	trimmed := strings.TrimLeft(line, " \t")
+	return trimmed != "" && strings.Trim(trimmed, "^") == ""
+}
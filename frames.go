@@ -0,0 +1,64 @@
+package oops
+
+// StackFrame is a frame of the stacktrace of an error.
+type StackFrame struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// SourceLine is a line of code around a stack frame.
+type SourceLine struct {
+	Number  int
+	Code    string
+	Current bool
+}
+
+// Source returns the lines of code around the frame, or an empty slice when
+// the file cannot be read.
+func (f StackFrame) Source() []SourceLine {
+	return getSourceLines(f.File, f.Line)
+}
+
+// LinkedStackFrames returns the frames captured by `LinkStack()` on the builder
+// of the error, the innermost call first.
+func (o OopsError) LinkedStackFrames() []StackFrame {
+	if o.linked == nil {
+		return []StackFrame{}
+	}
+
+	linkedFrames := o.linked.getFrames()
+
+	frames := make([]StackFrame, 0, len(linkedFrames))
+	for _, frame := range linkedFrames {
+		frames = append(frames, StackFrame{
+			File:     frame.file,
+			Line:     frame.line,
+			Function: frame.function,
+		})
+	}
+
+	return frames
+}
+
+// StackFrames returns the frames captured when this error was created or
+// wrapped, the innermost call first. Frames of wrapped errors are not included:
+// use `errors.Unwrap` to walk the chain.
+func (o OopsError) StackFrames() []StackFrame {
+	if o.stacktrace == nil {
+		return []StackFrame{}
+	}
+
+	stacktraceFrames := o.stacktrace.getFrames()
+
+	frames := make([]StackFrame, 0, len(stacktraceFrames))
+	for _, frame := range stacktraceFrames {
+		frames = append(frames, StackFrame{
+			File:     frame.file,
+			Line:     frame.line,
+			Function: frame.function,
+		})
+	}
+
+	return frames
+}
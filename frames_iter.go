@@ -0,0 +1,50 @@
+//go:build go1.23
+
+package oops
+
+import (
+	"iter"
+	"runtime"
+)
+
+// Frames returns an iterator over the frames of the whole error chain: the
+// frames of the deepest error first, then the frames of the wrapping errors
+// that were not yielded yet. PC, File, Line and Function are the ones printed
+// by `Stacktrace()`: File is trimmed (see `StackTracePathTrimmer`) and
+// Function is the short name of the function.
+//
+//	for frame := range err.Frames() {
+//		fmt.Printf("%s:%d %s()\n", frame.File, frame.Line, frame.Function)
+//	}
+func (o OopsError) Frames() iter.Seq[runtime.Frame] {
+	return func(yield func(runtime.Frame) bool) {
+		stacktraces := []*oopsStacktrace{}
+
+		recursive(o, func(e OopsError) {
+			if e.stacktrace != nil {
+				stacktraces = append([]*oopsStacktrace{e.stacktrace}, stacktraces...)
+			}
+		})
+
+		seen := map[string]struct{}{}
+
+		for _, st := range stacktraces {
+			for _, frame := range st.getFrames() {
+				key := frame.String()
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				if !yield(runtime.Frame{
+					PC:       frame.pc,
+					File:     frame.file,
+					Line:     frame.line,
+					Function: frame.function,
+				}) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+//go:build go1.23
+
+package oops
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrames(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().Errorf("permission denied").(OopsError)
+	wrapped := func() OopsError {
+		return new().Wrapf(err, "could not fetch user").(OopsError)
+	}()
+
+	frames := []runtime.Frame{}
+	for frame := range wrapped.Frames() {
+		frames = append(frames, frame)
+	}
+
+	// frames of the deepest error first
+	is.GreaterOrEqual(len(frames), len(err.StackFrames())+1)
+	for i, frame := range err.StackFrames() {
+		is.Equal(frame.File, frames[i].File)
+		is.Equal(frame.Line, frames[i].Line)
+		is.Equal(frame.Function, frames[i].Function)
+		is.NotZero(frames[i].PC)
+	}
+
+	// then the new frames of the wrapping error, deduplicated
+	is.Equal("TestFrames.func1", frames[len(err.StackFrames())].Function)
+	seen := map[runtime.Frame]struct{}{}
+	for _, frame := range frames {
+		is.NotContains(seen, frame)
+		seen[frame] = struct{}{}
+	}
+
+	// early break
+	count := 0
+	for range wrapped.Frames() {
+		count++
+		break
+	}
+	is.Equal(1, count)
+
+	for range new().WithoutStacktrace().Errorf("permission denied").(OopsError).Frames() {
+		is.Fail("unexpected frame")
+	}
+}
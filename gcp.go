@@ -0,0 +1,133 @@
+package oops
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// GCPErrorReportType flags a structured log entry as an error event for Google
+// Cloud Error Reporting.
+const GCPErrorReportType = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// GCPErrorReport is a structured log entry recognized by Google Cloud Error Reporting.
+type GCPErrorReport struct {
+	Type           string                `json:"@type"`
+	Severity       string                `json:"severity"`
+	Message        string                `json:"message"`
+	EventTime      string                `json:"eventTime,omitempty"`
+	ServiceContext GCPServiceContext     `json:"serviceContext"`
+	Context        GCPErrorReportContext `json:"context"`
+
+	// oops attributes
+	Oops map[string]any `json:"oops,omitempty"`
+}
+
+// GCPServiceContext identifies the service reporting the error.
+type GCPServiceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+// GCPErrorReportContext describes where the error was reported.
+type GCPErrorReportContext struct {
+	User           string             `json:"user,omitempty"`
+	ReportLocation *GCPReportLocation `json:"reportLocation,omitempty"`
+}
+
+// GCPReportLocation is the location in the source code where the error was created.
+type GCPReportLocation struct {
+	FilePath     string `json:"filePath"`
+	LineNumber   int    `json:"lineNumber"`
+	FunctionName string `json:"functionName"`
+}
+
+// ToGCPErrorReport returns the Google Cloud Error Reporting representation of
+// the error. The stacktrace of the deepest error is appended to the message in
+// the Go panic format, so that errors logged as JSON from GKE or Cloud Run are
+// grouped in Error Reporting.
+func (o OopsError) ToGCPErrorReport(service string, version string) GCPErrorReport {
+	severity := o.Severity()
+	userID, _ := o.User()
+
+	payload := o.ToMapWith(ToMapOptions{Exclude: []string{"error", "time", "severity", "stacktrace", "sources"}})
+
+	report := GCPErrorReport{
+		Type:      GCPErrorReportType,
+		Severity:  gcpSeverity(severity),
+		Message:   o.Error(),
+		EventTime: o.Time().Format(time.RFC3339Nano),
+		ServiceContext: GCPServiceContext{
+			Service: service,
+			Version: version,
+		},
+		Context: GCPErrorReportContext{
+			User: userID,
+		},
+		Oops: payload,
+	}
+
+	if st := o.deepestStacktrace(); st != nil {
+		frame := st.getFrames()[0]
+		report.Message += "\n\n" + st.goPanicFormat()
+		report.Context.ReportLocation = &GCPReportLocation{
+			FilePath:     frame.file,
+			LineNumber:   frame.line,
+			FunctionName: frame.fullFuncName(),
+		}
+	}
+
+	return report
+}
+
+func (o OopsError) deepestStacktrace() *oopsStacktrace {
+	var st *oopsStacktrace
+
+	recursive(o, func(e OopsError) {
+		if e.stacktrace != nil && len(e.stacktrace.getFrames()) > 0 {
+			st = e.stacktrace
+		}
+	})
+
+	return st
+}
+
+// goPanicFormat prints the frames as a goroutine dump, the format expected by
+// Error Reporting for Go.
+func (st *oopsStacktrace) goPanicFormat() string {
+	lines := []string{"goroutine 1 [running]:"}
+
+	for _, frame := range st.getFrames() {
+		lines = append(
+			lines,
+			frame.fullFuncName()+"(...)",
+			fmt.Sprintf("\t%s:%d", frame.file, frame.line),
+		)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (frame *oopsStacktraceFrame) fullFuncName() string {
+	if f := runtime.FuncForPC(frame.pc); f != nil {
+		return f.Name()
+	}
+
+	return frame.function
+}
+
+func gcpSeverity(severity SeverityLevel) string {
+	switch severity {
+	case SeverityDebug:
+		return "DEBUG"
+	case SeverityInfo:
+		return "INFO"
+	case SeverityWarning:
+		return "WARNING"
+	case SeverityFatal:
+		return "CRITICAL"
+	default:
+		return "ERROR"
+	}
+}
@@ -0,0 +1,215 @@
+package oops
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+)
+
+var (
+	// GoroutinesMaxSize limits the size (in bytes) of the goroutine dump captured
+	// by `WithAllGoroutines()`. Goroutines beyond the limit are dropped.
+	GoroutinesMaxSize = 64 << 10
+
+	// CaptureGoroutineInfo records the id of the goroutine creating an error, and
+	// the pprof labels of the context given to `WithContext()`, to correlate
+	// errors with profiles and goroutine dumps.
+	CaptureGoroutineInfo = false
+)
+
+// Goroutine is a goroutine captured by `WithAllGoroutines()`.
+type Goroutine struct {
+	ID int
+	// State is the wait reason of the goroutine (eg: "running", "chan receive, 2 minutes").
+	State  string
+	Frames []StackFrame
+}
+
+func (g Goroutine) String() string {
+	lines := []string{fmt.Sprintf("goroutine %d [%s]:", g.ID, g.State)}
+	for _, frame := range g.Frames {
+		currentFrame := fmt.Sprintf("%v:%v", frame.File, frame.Line)
+		if frame.Function != "" {
+			currentFrame = fmt.Sprintf("%v:%v %v()", frame.File, frame.Line, frame.Function)
+		}
+
+		lines = append(lines, "  --- at "+currentFrame)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Goroutines returns the stacks of all goroutines, captured when the deepest
+// error of the chain was created with `WithAllGoroutines()`.
+func (o OopsError) Goroutines() []Goroutine {
+	goroutines := []Goroutine{}
+
+	recursive(o, func(e OopsError) {
+		if len(e.goroutines) > 0 {
+			goroutines = e.goroutines
+		}
+	})
+
+	return goroutines
+}
+
+// GoroutineID returns the id of the goroutine that created the deepest error of
+// the chain, or 0 when `oops.CaptureGoroutineInfo` is disabled.
+func (o OopsError) GoroutineID() uint64 {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) uint64 {
+			return e.goroutineID
+		},
+	)
+}
+
+// PprofLabels returns the pprof labels of the context of the error (see
+// `pprof.Labels`), when `oops.CaptureGoroutineInfo` is enabled.
+func (o OopsError) PprofLabels() map[string]string {
+	labels := map[string]string{}
+
+	recursive(o, func(e OopsError) {
+		for k, v := range e.pprofLabels {
+			labels[k] = v
+		}
+	})
+
+	return labels
+}
+
+// currentGoroutineID parses the id of the current goroutine from the header of
+// its stack: "goroutine 18 [running]:".
+func currentGoroutineID() uint64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	header, _ := strings.CutPrefix(string(buf), "goroutine ")
+	id, _, _ := strings.Cut(header, " ")
+
+	goroutineID, _ := strconv.ParseUint(id, 10, 64)
+	return goroutineID
+}
+
+func pprofLabels(ctx context.Context) map[string]string {
+	labels := map[string]string{}
+
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels[key] = value
+		return true
+	})
+
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return labels
+}
+
+func (o OopsError) goroutinesString() string {
+	if replayed := o.replayedOutputs(); replayed != nil {
+		return replayed.goroutines
+	}
+
+	goroutines := o.Goroutines()
+	if len(goroutines) == 0 {
+		return ""
+	}
+
+	blocks := make([]string, 0, len(goroutines))
+	for _, g := range goroutines {
+		blocks = append(blocks, g.String())
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+// captureGoroutines snapshots the stacks of all goroutines, up to maxSize bytes.
+func captureGoroutines(maxSize int) []Goroutine {
+	if maxSize <= 0 {
+		return nil
+	}
+
+	buf := make([]byte, maxSize)
+	n := runtime.Stack(buf, true)
+	dump := string(buf[:n])
+
+	if n == len(buf) {
+		// the last goroutine is incomplete
+		if i := strings.LastIndex(dump, "\n\ngoroutine "); i >= 0 {
+			dump = dump[:i]
+		}
+	}
+
+	return parseGoroutines(dump)
+}
+
+// parseGoroutines parses the output of `runtime.Stack(buf, true)`.
+func parseGoroutines(dump string) []Goroutine {
+	goroutines := []Goroutine{}
+
+	for _, block := range strings.Split(strings.TrimSpace(dump), "\n\n") {
+		lines := strings.Split(block, "\n")
+
+		// goroutine 1 [running]:
+		header, ok := strings.CutPrefix(lines[0], "goroutine ")
+		if !ok {
+			continue
+		}
+
+		id, state, ok := strings.Cut(strings.TrimSuffix(header, ":"), " [")
+		if !ok {
+			continue
+		}
+
+		goroutineID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+
+		goroutine := Goroutine{
+			ID:     goroutineID,
+			State:  strings.TrimSuffix(state, "]"),
+			Frames: []StackFrame{},
+		}
+
+		// each frame is a function line, followed by a tab-indented location line
+		for i := 1; i < len(lines)-1; i++ {
+			if !strings.HasPrefix(lines[i+1], "\t") {
+				// eg: "...additional frames elided..."
+				continue
+			}
+
+			function := lines[i]
+			location := strings.TrimSpace(lines[i+1])
+			i++
+
+			// main.main(...) or created by main.main in goroutine 1
+			if created, ok := strings.CutPrefix(function, "created by "); ok {
+				function, _, _ = strings.Cut(created, " in goroutine ")
+			} else if j := strings.LastIndex(function, "("); j > 0 {
+				function = function[:j]
+			}
+
+			// /path/to/file.go:42 +0x1d
+			location, _, _ = strings.Cut(location, " +0x")
+			file, line, _ := strings.Cut(location, ":")
+			lineNumber, _ := strconv.Atoi(line)
+
+			_, display := framePath(file, function)
+
+			goroutine.Frames = append(goroutine.Frames, StackFrame{
+				File:     display,
+				Line:     lineNumber,
+				Function: shortFunctionName(function),
+			})
+		}
+
+		goroutines = append(goroutines, goroutine)
+	}
+
+	return goroutines
+}
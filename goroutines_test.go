@@ -0,0 +1,111 @@
+package oops
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGoroutines(t *testing.T) {
+	is := assert.New(t)
+
+	dump := `goroutine 1 [running]:
+main.main()
+	/app/main.go:10 +0x1d
+
+goroutine 7 [chan receive, 2 minutes]:
+github.com/acme/api/worker.(*Pool).run(0xc000010000, {0x1, 0x2})
+	/app/worker/pool.go:42 +0x65
+...additional frames elided...
+created by github.com/acme/api/worker.NewPool in goroutine 1
+	/app/worker/pool.go:21 +0x8f
+`
+
+	goroutines := parseGoroutines(dump)
+	is.Len(goroutines, 2)
+
+	is.Equal(Goroutine{
+		ID:     1,
+		State:  "running",
+		Frames: []StackFrame{{File: "/app/main.go", Line: 10, Function: "main"}},
+	}, goroutines[0])
+
+	is.Equal(Goroutine{
+		ID:    7,
+		State: "chan receive, 2 minutes",
+		Frames: []StackFrame{
+			{File: "/app/worker/pool.go", Line: 42, Function: "Pool.run"},
+			{File: "/app/worker/pool.go", Line: 21, Function: "NewPool"},
+		},
+	}, goroutines[1])
+
+	is.Equal("goroutine 7 [chan receive, 2 minutes]:\n  --- at /app/worker/pool.go:42 Pool.run()\n  --- at /app/worker/pool.go:21 NewPool()", goroutines[1].String())
+}
+
+func TestOopsWithAllGoroutines(t *testing.T) {
+	is := assert.New(t)
+
+	block := make(chan struct{})
+	defer close(block)
+
+	go func() {
+		<-block
+	}()
+
+	err := new().Errorf("deadline exceeded").(OopsError)
+	is.Empty(err.Goroutines())
+	is.NotContains(err.ToMap(), "goroutines")
+
+	err = new().WithAllGoroutines().Errorf("deadline exceeded").(OopsError)
+	is.GreaterOrEqual(len(err.Goroutines()), 2)
+	is.NotNil(err.stacktrace)
+	is.Equal("running", err.Goroutines()[0].State)
+	is.True(strings.HasPrefix(err.ToMap()["goroutines"].(string), "goroutine "))
+	is.Contains(fmt.Sprintf("%+v", err), "Goroutines:\n  goroutine ")
+	is.NotContains(err.ToMapWith(ToMapOptions{Exclude: []string{"goroutines"}}), "goroutines")
+
+	wrapped := new().Wrapf(err, "could not fetch invoices").(OopsError)
+	is.Equal(err.Goroutines(), wrapped.Goroutines())
+
+	previous := GoroutinesMaxSize
+	GoroutinesMaxSize = 300
+	defer func() { GoroutinesMaxSize = previous }()
+
+	err = WithAllGoroutines().Errorf("deadline exceeded").(OopsError)
+	is.LessOrEqual(len(err.Goroutines()), 1)
+}
+
+func TestGoroutineInfo(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("worker", "billing"))
+
+	err := new().WithContext(ctx).Errorf("could not charge customer").(OopsError)
+	is.Zero(err.GoroutineID())
+	is.Empty(err.PprofLabels())
+	is.NotContains(err.ToMap(), "goroutine_id")
+	is.NotContains(err.ToMap(), "pprof_labels")
+
+	CaptureGoroutineInfo = true
+	defer func() { CaptureGoroutineInfo = false }()
+
+	err = new().WithContext(ctx).Errorf("could not charge customer").(OopsError)
+	is.Equal(currentGoroutineID(), err.GoroutineID())
+	is.NotZero(err.GoroutineID())
+	is.Equal(map[string]string{"worker": "billing"}, err.PprofLabels())
+	is.Equal(err.GoroutineID(), err.ToMap()["goroutine_id"])
+	is.Equal(map[string]string{"worker": "billing"}, err.ToMap()["pprof_labels"])
+	is.Contains(fmt.Sprintf("%+v", err), "  * worker: billing\n")
+
+	done := make(chan uint64)
+	go func() {
+		wrapped := new().Wrapf(err, "could not process invoice").(OopsError)
+		is.Equal(map[string]string{"worker": "billing"}, wrapped.PprofLabels())
+		done <- wrapped.GoroutineID()
+	}()
+	is.Equal(err.GoroutineID(), <-done)
+}
@@ -0,0 +1,99 @@
+package oopsgqlgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/samber/oops"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Options configures the error presenter.
+type Options struct {
+	// Logger is called with oops errors before presentation, with their internal
+	// details. Defaults to `slog.Default()`.
+	Logger func(ctx context.Context, err error)
+	// PublicMessage is the GraphQL message of errors without public message.
+	// Defaults to "internal system error".
+	PublicMessage string
+}
+
+// ErrorPresenter is a gqlgen `graphql.ErrorPresenterFunc`: the public message
+// of oops errors becomes the GraphQL message, and the code and the trace id go
+// into `extensions`. Oops errors are logged with `slog.Default()`. Other errors
+// are presented as is.
+//
+//	srv := handler.NewDefaultServer(generated.NewExecutableSchema(cfg))
+//	srv.SetErrorPresenter(oopsgqlgen.ErrorPresenter)
+//	srv.SetRecoverFunc(oopsgqlgen.Recover)
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	return ErrorPresenterWith(Options{})(ctx, err)
+}
+
+// ErrorPresenterWith returns an error presenter with custom options.
+func ErrorPresenterWith(opts Options) func(ctx context.Context, err error) *gqlerror.Error {
+	logger := opts.Logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	publicMessage := opts.PublicMessage
+	if publicMessage == "" {
+		publicMessage = "internal system error"
+	}
+
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		// gqlgen wraps errors with the path of the field
+		var gqlErr *gqlerror.Error
+		if !errors.As(err, &gqlErr) {
+			gqlErr = gqlerror.WrapPath(nil, err)
+		}
+
+		oopsError, ok := oops.AsOops(err)
+		if !ok {
+			return gqlErr
+		}
+
+		logger(ctx, oopsError)
+
+		extensions := map[string]any{}
+		for k, v := range gqlErr.Extensions {
+			extensions[k] = v
+		}
+		if code := oopsError.Code(); code != "" {
+			extensions["code"] = code
+		}
+		if trace := oopsError.Trace(); trace != "" {
+			extensions["trace"] = trace
+		}
+
+		return &gqlerror.Error{
+			Err:        oopsError,
+			Message:    oops.GetPublic(oopsError, publicMessage),
+			Path:       gqlErr.Path,
+			Locations:  gqlErr.Locations,
+			Extensions: extensions,
+			Rule:       gqlErr.Rule,
+		}
+	}
+}
+
+// Recover is a gqlgen `graphql.RecoverFunc`, recovering panics of resolvers
+// into oops errors built from the context (see `oops.FromContext`). The error
+// is then presented by the error presenter.
+func Recover(ctx context.Context, p any) error {
+	err, ok := p.(error)
+	if !ok {
+		err = fmt.Errorf("%v", p)
+	}
+
+	return oops.
+		FromContext(ctx).
+		Wrapf(err, "graphql: panic recovered")
+}
+
+func defaultLogger(ctx context.Context, err error) {
+	slog.Default().ErrorContext(ctx, err.Error(), slog.Any("error", err))
+}
@@ -0,0 +1,62 @@
+package oopsgqlgen
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	is := assert.New(t)
+
+	var logged error
+	presenter := ErrorPresenterWith(Options{
+		Logger: func(ctx context.Context, err error) {
+			logged = err
+		},
+	})
+
+	path := ast.Path{ast.PathName("invoice"), ast.PathName("total")}
+	err := oops.
+		Code("invoice_not_found").
+		Public("Invoice not found.").
+		Trace("1234").
+		Errorf("sql: no rows")
+
+	// gqlgen wraps errors with the path of the field
+	presented := presenter(context.Background(), gqlerror.WrapPath(path, err))
+	is.Equal("Invoice not found.", presented.Message)
+	is.Equal(path, presented.Path)
+	is.Equal(map[string]any{"code": "invoice_not_found", "trace": "1234"}, presented.Extensions)
+	is.EqualError(logged, "sql: no rows")
+	is.Equal(err, presented.Unwrap())
+
+	// without public message
+	presented = presenter(context.Background(), oops.Errorf("sql: no rows"))
+	is.Equal("internal system error", presented.Message)
+
+	// other errors are presented as is
+	logged = nil
+	presented = presenter(context.Background(), gqlerror.WrapPath(path, errors.New("invalid id")))
+	is.Equal("invalid id", presented.Message)
+	is.Equal(path, presented.Path)
+	is.Nil(logged)
+}
+
+func TestRecover(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := oops.WithBuilder(context.Background(), oops.Trace("1234"))
+
+	err := Recover(ctx, "boom")
+	is.EqualError(err, "graphql: panic recovered: boom")
+	is.Equal("1234", oops.GetTrace(err))
+
+	cause := errors.New("boom")
+	is.ErrorIs(Recover(ctx, cause), cause)
+}
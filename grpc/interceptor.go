@@ -0,0 +1,133 @@
+package oopsgrpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/samber/oops"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// TraceMetadataKeys are the incoming metadata keys of the trace id, by priority.
+// The W3C `traceparent` is also supported.
+var TraceMetadataKeys = []string{"x-request-id", "x-trace-id"}
+
+// UnaryServerInterceptor stores a builder in the context of the handler (see
+// `oops.WithBuilder`), with the method name, the peer address and the trace id
+// found in the incoming metadata. Panics are recovered into oops errors, and
+// oops errors are converted into gRPC statuses with details (see ToGRPCStatus).
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(oopsgrpc.UnaryServerInterceptor()),
+//		grpc.ChainStreamInterceptor(oopsgrpc.StreamServerInterceptor()),
+//	)
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return UnaryServerInterceptorWith(StatusOptions{})
+}
+
+// UnaryServerInterceptorWith is UnaryServerInterceptor, with custom options for
+// the conversion of errors into gRPC statuses (see ToGRPCStatusWith).
+func UnaryServerInterceptorWith(opts StatusOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		builder := newBuilder(ctx, info.FullMethod)
+		ctx = oops.WithBuilder(ctx, builder)
+
+		recovered := builder.
+			HTTPStatus(http.StatusInternalServerError).
+			Recoverf(func() {
+				resp, err = handler(ctx, req)
+			}, "grpc: panic recovered")
+		if recovered != nil {
+			return nil, ToGRPCStatusWith(recovered, opts).Err()
+		}
+
+		return resp, toStatusError(err, opts)
+	}
+}
+
+// StreamServerInterceptor is the stream variant of UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return StreamServerInterceptorWith(StatusOptions{})
+}
+
+// StreamServerInterceptorWith is the stream variant of UnaryServerInterceptorWith.
+func StreamServerInterceptorWith(opts StatusOptions) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		builder := newBuilder(ss.Context(), info.FullMethod)
+		ss = &serverStream{
+			ServerStream: ss,
+			ctx:          oops.WithBuilder(ss.Context(), builder),
+		}
+
+		recovered := builder.
+			HTTPStatus(http.StatusInternalServerError).
+			Recoverf(func() {
+				err = handler(srv, ss)
+			}, "grpc: panic recovered")
+		if recovered != nil {
+			return ToGRPCStatusWith(recovered, opts).Err()
+		}
+
+		return toStatusError(err, opts)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func newBuilder(ctx context.Context, method string) oops.OopsErrorBuilder {
+	builder := oops.
+		FromContext(ctx).
+		With("grpc_method", method)
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		builder = builder.With("grpc_peer", p.Addr.String())
+	}
+
+	if trace := traceFromMetadata(ctx); trace != "" {
+		builder = builder.Trace(trace)
+	}
+
+	return builder
+}
+
+func traceFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, key := range TraceMetadataKeys {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	// version-traceid-parentid-flags
+	if values := md.Get("traceparent"); len(values) > 0 {
+		if parts := strings.Split(values[0], "-"); len(parts) == 4 && len(parts[1]) == 32 {
+			return parts[1]
+		}
+	}
+
+	return ""
+}
+
+// toStatusError converts oops errors into gRPC status errors. Other errors
+// are returned as is.
+func toStatusError(err error, opts StatusOptions) error {
+	if _, ok := oops.AsOops(err); ok {
+		return ToGRPCStatusWith(err, opts).Err()
+	}
+
+	return err
+}
@@ -0,0 +1,106 @@
+package oopsgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "1234"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4242}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/billing.Billing/GetInvoice"}
+	interceptor := UnaryServerInterceptor()
+
+	var built error
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		built = oops.FromContext(ctx).HTTPStatus(404).Errorf("invoice not found")
+		return nil, built
+	})
+
+	oopsError, ok := oops.AsOops(built)
+	is.True(ok)
+	is.Equal("1234", oopsError.Trace())
+	is.Equal("/billing.Billing/GetInvoice", oopsError.Context()["grpc_method"])
+	is.Equal("10.0.0.1:4242", oopsError.Context()["grpc_peer"])
+
+	st, _ := status.FromError(err)
+	is.Equal(codes.NotFound, st.Code())
+	is.Equal("Not Found", st.Message())
+
+	// panics
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	st, _ = status.FromError(err)
+	is.Equal(codes.Internal, st.Code())
+	is.Equal("Internal Server Error", st.Message())
+
+	// other errors are returned as is
+	_, err = interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.Aborted, "aborted")
+	})
+	is.Equal(status.Error(codes.Aborted, "aborted"), err)
+
+	resp, err := interceptor(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	is.NoError(err)
+	is.Equal("ok", resp)
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	info := &grpc.StreamServerInfo{FullMethod: "/billing.Billing/ListInvoices"}
+	interceptor := StreamServerInterceptor()
+
+	var built error
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv any, ss grpc.ServerStream) error {
+		built = oops.FromContext(ss.Context()).Errorf("stream closed")
+		return built
+	})
+
+	oopsError, ok := oops.AsOops(built)
+	is.True(ok)
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", oopsError.Trace())
+	is.Equal("/billing.Billing/ListInvoices", oopsError.Context()["grpc_method"])
+
+	st, _ := status.FromError(err)
+	is.Equal(codes.Unknown, st.Code())
+
+	err = interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv any, ss grpc.ServerStream) error {
+		panic("boom")
+	})
+	st, _ = status.FromError(err)
+	is.Equal(codes.Internal, st.Code())
+
+	// context attributes are sent when allowed
+	interceptor = StreamServerInterceptorWith(StatusOptions{ContextKeys: []string{"grpc_method"}})
+	err = interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv any, ss grpc.ServerStream) error {
+		return oops.FromContext(ss.Context()).Code("stream_closed").Errorf("stream closed")
+	})
+	got, ok := oops.AsOops(FromGRPCError(err))
+	is.True(ok)
+	is.Equal("/billing.Billing/ListInvoices", got.Context()["grpc_method"])
+}
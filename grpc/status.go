@@ -0,0 +1,217 @@
+package oopsgrpc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/samber/oops"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Locale of the public message, sent as a `LocalizedMessage` detail.
+var Locale = "en-US"
+
+// StatusOptions configures the conversion of errors into gRPC statuses.
+type StatusOptions struct {
+	// ContextKeys are the context attributes sent in the `ErrorInfo` metadata.
+	// The context is not sent by default, since it may hold internal data.
+	ContextKeys []string
+}
+
+// ToGRPCStatus converts an error into a gRPC status. The status code is derived
+// from the http status of the error, and the status message is the public
+// message of the error (default: the text of the http status). Code and domain
+// are sent as an `ErrorInfo` detail, the public message as a `LocalizedMessage`
+// detail and the trace as a `RequestInfo` detail. Non-oops errors are reported
+// as `codes.Unknown`.
+//
+// The context of the error is not sent. Use ToGRPCStatusWith to send some
+// attributes in the `ErrorInfo` metadata.
+//
+//	func (s *server) GetInvoice(ctx context.Context, req *pb.GetInvoiceRequest) (*pb.Invoice, error) {
+//		invoice, err := s.repo.Get(ctx, req.Id)
+//		if err != nil {
+//			return nil, oopsgrpc.ToGRPCStatus(err).Err()
+//		}
+//		return invoice, nil
+//	}
+func ToGRPCStatus(err error) *status.Status {
+	return ToGRPCStatusWith(err, StatusOptions{})
+}
+
+// ToGRPCStatusWith converts an error into a gRPC status, with custom options.
+//
+//	st := oopsgrpc.ToGRPCStatusWith(err, oopsgrpc.StatusOptions{
+//		ContextKeys: []string{"invoice_id"},
+//	})
+func ToGRPCStatusWith(err error, opts StatusOptions) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return status.New(codes.Unknown, http.StatusText(http.StatusInternalServerError))
+	}
+
+	grpcCode := httpStatusToGRPCCode(oopsError.HTTPStatus())
+	st := status.New(grpcCode, oops.GetPublic(oopsError, http.StatusText(grpcCodeToHTTPStatus(grpcCode))))
+
+	details := []protoadapt.MessageV1{}
+
+	if code, domain := oopsError.Code(), oopsError.Domain(); code != "" || domain != "" {
+		metadata := map[string]string{}
+		if context, ok := oopsError.ToMap()["context"].(map[string]any); ok {
+			for _, k := range opts.ContextKeys {
+				if v, ok := context[k]; ok {
+					metadata[k] = fmt.Sprint(v)
+				}
+			}
+		}
+
+		details = append(details, &errdetails.ErrorInfo{
+			Reason:   code,
+			Domain:   domain,
+			Metadata: metadata,
+		})
+	}
+
+	if public := oopsError.Public(); public != "" {
+		details = append(details, &errdetails.LocalizedMessage{
+			Locale:  Locale,
+			Message: public,
+		})
+	}
+
+	if trace := oopsError.Trace(); trace != "" {
+		details = append(details, &errdetails.RequestInfo{
+			RequestId: trace,
+		})
+	}
+
+	if len(details) == 0 {
+		return st
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		// never drop the error because details cannot be attached
+		return st
+	}
+
+	return withDetails
+}
+
+// FromGRPCStatus rehydrates an `oops.OopsError` from a gRPC status, built with
+// ToGRPCStatus or by any server sending standard error details. The message of
+// the error is the status message. It returns nil for an OK status.
+func FromGRPCStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	builder := oops.
+		HTTPStatus(grpcCodeToHTTPStatus(st.Code())).
+		With("grpc_code", st.Code().String())
+
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			builder = builder.Code(d.GetReason()).In(d.GetDomain())
+			for k, v := range d.GetMetadata() {
+				builder = builder.With(k, v)
+			}
+		case *errdetails.LocalizedMessage:
+			builder = builder.Public(d.GetMessage())
+		case *errdetails.RequestInfo:
+			builder = builder.Trace(d.GetRequestId())
+		}
+	}
+
+	return builder.Errorf("%s", st.Message())
+}
+
+// FromGRPCError rehydrates an `oops.OopsError` from an error returned by a gRPC
+// client. Errors that are not gRPC statuses are wrapped as is.
+//
+//	invoice, err := client.GetInvoice(ctx, &pb.GetInvoiceRequest{Id: id})
+//	if err != nil {
+//		return oopsgrpc.FromGRPCError(err)
+//	}
+func FromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return oops.Wrap(err)
+	}
+
+	return FromGRPCStatus(st)
+}
+
+func httpStatusToGRPCCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499: // client closed request
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	if httpStatus >= 500 {
+		return codes.Internal
+	}
+
+	return codes.Unknown
+}
+
+func grpcCodeToHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+
+	return http.StatusInternalServerError
+}
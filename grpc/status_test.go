@@ -0,0 +1,84 @@
+package oopsgrpc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("billing.invoice_not_found").
+		In("billing").
+		Trace("1234").
+		HTTPStatus(404).
+		Public("Invoice not found.").
+		With("invoice_id", 42).
+		Errorf("invoice 42 not found")
+
+	st := ToGRPCStatusWith(err, StatusOptions{ContextKeys: []string{"invoice_id", "unknown"}})
+	is.Equal(codes.NotFound, st.Code())
+	is.Equal("Invoice not found.", st.Message())
+	is.Len(st.Details(), 3)
+
+	// through the wire
+	st, ok := status.FromError(st.Err())
+	is.True(ok)
+
+	got, ok := oops.AsOops(FromGRPCStatus(st))
+	is.True(ok)
+	is.Equal("Invoice not found.", got.Error())
+	is.Equal("billing.invoice_not_found", got.Code())
+	is.Equal("billing", got.Domain())
+	is.Equal("1234", got.Trace())
+	is.Equal(404, got.HTTPStatus())
+	is.Equal("Invoice not found.", got.Public())
+	is.Equal("42", got.Context()["invoice_id"])
+	is.Equal("NotFound", got.Context()["grpc_code"])
+	is.Len(got.Context(), 2)
+}
+
+func TestToGRPCStatus(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(codes.OK, ToGRPCStatus(nil).Code())
+
+	st := ToGRPCStatus(errors.New("a message"))
+	is.Equal(codes.Unknown, st.Code())
+	is.Equal("Internal Server Error", st.Message())
+	is.Empty(st.Details())
+
+	st = ToGRPCStatus(oops.HTTPStatus(502).Errorf("a message"))
+	is.Equal(codes.Internal, st.Code())
+	is.Equal("Internal Server Error", st.Message())
+	is.Equal(codes.Unknown, ToGRPCStatus(oops.Errorf("a message")).Code())
+
+	// the context is not sent by default
+	st = ToGRPCStatus(oops.Code("invoice_not_found").With("sql", "SELECT * FROM invoices").Errorf("a message"))
+	got, ok := oops.AsOops(FromGRPCStatus(st))
+	is.True(ok)
+	is.Equal("invoice_not_found", got.Code())
+	is.Nil(got.Context()["sql"])
+}
+
+func TestFromGRPCError(t *testing.T) {
+	is := assert.New(t)
+
+	is.Nil(FromGRPCError(nil))
+	is.Nil(FromGRPCStatus(status.New(codes.OK, "")))
+
+	err := FromGRPCError(status.Error(codes.Unavailable, "connection refused"))
+	got, ok := oops.AsOops(err)
+	is.True(ok)
+	is.Equal("connection refused", got.Error())
+	is.Equal(503, got.HTTPStatus())
+
+	err = FromGRPCError(assert.AnError)
+	is.ErrorIs(err, assert.AnError)
+}
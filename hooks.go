@@ -0,0 +1,34 @@
+package oops
+
+import "sync"
+
+var hooksMutex sync.RWMutex
+var hooks = []func(OopsError) OopsError{}
+
+// OnError registers a global hook, invoked whenever an `oops.OopsError` is built
+// (Wrap, Wrapf, Errorf, Join, Recover...). Hooks are invoked in registration order,
+// and may return an altered error, to enforce conventions, scrub secrets, emit metrics...
+//
+//	oops.OnError(func(err oops.OopsError) oops.OopsError {
+//		if err.Owner() == "" && err.Domain() == "billing" {
+//			return oops.OopsError(oops.OopsErrorBuilder(err).Owner("billing-team@acme.org"))
+//		}
+//		return err
+//	})
+func OnError(hook func(OopsError) OopsError) {
+	hooksMutex.Lock()
+	hooks = append(hooks, hook)
+	hooksMutex.Unlock()
+}
+
+func runHooks(err OopsError) OopsError {
+	hooksMutex.RLock()
+	h := hooks
+	hooksMutex.RUnlock()
+
+	for _, hook := range h {
+		err = hook(err)
+	}
+
+	return err
+}
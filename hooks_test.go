@@ -0,0 +1,46 @@
+package oops
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnError(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { hooks = []func(OopsError) OopsError{} }()
+
+	calls := 0
+	OnError(func(err OopsError) OopsError {
+		calls++
+		return err
+	})
+	OnError(func(err OopsError) OopsError {
+		if err.Owner() == "" && err.Domain() == "billing" {
+			return OopsError(OopsErrorBuilder(err).Owner("billing-team@acme.org"))
+		}
+		return err
+	})
+
+	err := new().In("billing").Wrapf(assert.AnError, "a message %d", 42)
+	is.Equal(1, calls)
+	is.Equal("billing-team@acme.org", err.(OopsError).Owner())
+	is.Equal("a message 42: assert.AnError general error for testing", err.Error())
+	is.True(errors.Is(err, assert.AnError))
+	is.NotNil(err.(OopsError).stacktrace)
+
+	err = new().In("iam").Errorf("permission denied")
+	is.Equal(2, calls)
+	is.Empty(err.(OopsError).Owner())
+
+	_ = Recover(func() { panic("caramba!") })
+	is.Equal(3, calls)
+
+	_ = Join(assert.AnError)
+	is.Equal(4, calls)
+
+	is.Nil(Wrap(nil))
+	is.Equal(4, calls)
+}
@@ -0,0 +1,181 @@
+package oopshtml
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/samber/oops"
+)
+
+//go:embed page.html.tmpl
+var pageTemplate string
+
+var page = template.Must(template.New("oops").Parse(pageTemplate))
+
+type pageData struct {
+	Title      string
+	Status     int
+	Attributes []attribute
+	Chain      []layer
+	Context    []attribute
+	User       []attribute
+	Tenant     []attribute
+}
+
+type layer struct {
+	Message string
+	Code    string
+	Domain  string
+	Frames  []frame
+}
+
+type frame struct {
+	oops.StackFrame
+	Source []oops.SourceLine
+}
+
+type attribute struct {
+	Key   string
+	Value any
+}
+
+// Render writes err as a standalone HTML debug page: error chain, context
+// tables, stack frames with source fragments.
+//
+// The page exposes internal details and must not be served in production.
+func Render(w io.Writer, err error) error {
+	return page.Execute(w, newPageData(err))
+}
+
+// WriteError writes err as an HTML debug page, with the http status of the
+// error. Errors without http status are reported as 500 Internal Server Error.
+func WriteError(w http.ResponseWriter, err error) {
+	data := newPageData(err)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(data.Status)
+	_ = page.Execute(w, data)
+}
+
+// Handler is an http handler returning an error. Errors are written as HTML
+// debug pages, for development mode.
+//
+//	http.Handle("/invoices", oopshtml.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		return oops.HTTPStatus(404).Errorf("invoice not found")
+//	}))
+type Handler func(w http.ResponseWriter, r *http.Request) error
+
+var _ http.Handler = (Handler)(nil)
+
+// ServeHTTP implements http.Handler.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		WriteError(w, err)
+	}
+}
+
+func newPageData(err error) pageData {
+	data := pageData{
+		Title:  err.Error(),
+		Status: http.StatusInternalServerError,
+	}
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		data.Chain = []layer{{Message: err.Error()}}
+		return data
+	}
+
+	if status := oopsError.HTTPStatus(); status != 0 {
+		data.Status = status
+	}
+
+	data.Attributes = nonEmptyAttributes(
+		attribute{"Code", oopsError.Code()},
+		attribute{"Severity", string(oopsError.Severity())},
+		attribute{"Time", oopsError.Time()},
+		attribute{"Duration", oopsError.Duration()},
+		attribute{"Domain", oopsError.Domain()},
+		attribute{"Tags", oopsError.Tags()},
+		attribute{"Trace", oopsError.Trace()},
+		attribute{"Span", oopsError.Span()},
+		attribute{"Hint", oopsError.Hint()},
+		attribute{"Public", oopsError.Public()},
+		attribute{"Owner", oopsError.Owner()},
+		attribute{"HTTP status", oopsError.HTTPStatus()},
+	)
+
+	payload := oopsError.ToMapWith(oops.ToMapOptions{Include: []string{"context", "user", "tenant"}})
+	data.Context = sortedAttributes(payload["context"])
+	data.User = sortedAttributes(payload["user"])
+	data.Tenant = sortedAttributes(payload["tenant"])
+
+	for current := error(oopsError); current != nil; current = errors.Unwrap(current) {
+		e, ok := current.(oops.OopsError)
+		if !ok {
+			data.Chain = append(data.Chain, layer{Message: current.Error()})
+			break
+		}
+
+		l := layer{
+			Message: e.Error(),
+			Code:    e.ShallowCode(),
+			Domain:  e.ShallowDomain(),
+		}
+
+		for _, f := range e.StackFrames() {
+			l.Frames = append(l.Frames, frame{StackFrame: f, Source: f.Source()})
+		}
+
+		data.Chain = append(data.Chain, l)
+	}
+
+	return data
+}
+
+func nonEmptyAttributes(attrs ...attribute) []attribute {
+	output := []attribute{}
+
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			if v == "" {
+				continue
+			}
+		case []string:
+			if len(v) == 0 {
+				continue
+			}
+		case int:
+			if v == 0 {
+				continue
+			}
+		}
+
+		output = append(output, attr)
+	}
+
+	return output
+}
+
+func sortedAttributes(v any) []attribute {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	output := make([]attribute, 0, len(m))
+	for k, v := range m {
+		output = append(output, attribute{k, v})
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Key < output[j].Key
+	})
+
+	return output
+}
@@ -0,0 +1,71 @@
+package oopshtml
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("billing.invoice_not_found").
+		In("billing").
+		With("invoice_id", "<42>").
+		User("user-123", "email", "john@example.com").
+		Wrapf(oops.In("db").Wrap(errors.New("no rows")), "invoice not found")
+
+	var buf bytes.Buffer
+	is.NoError(Render(&buf, err))
+
+	html := buf.String()
+	is.Contains(html, "<title>invoice not found: no rows</title>")
+	is.Contains(html, "<tr><th>Code</th><td>billing.invoice_not_found</td></tr>")
+	is.Contains(html, "<span class=\"badge\">domain: db</span>")
+	is.Contains(html, "<h3>no rows</h3>")
+	is.Contains(html, "<tr><th>invoice_id</th><td>&lt;42&gt;</td></tr>")
+	is.Contains(html, "<tr><th>email</th><td>john@example.com</td></tr>")
+	is.Contains(html, "render_test.go:")
+	is.Contains(html, `class="current"`)
+
+	buf.Reset()
+	is.NoError(Render(&buf, errors.New("<script>")))
+	is.Contains(buf.String(), "<h3>&lt;script&gt;</h3>")
+}
+
+func TestHandler(t *testing.T) {
+	is := assert.New(t)
+
+	handler := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/oops":
+			return oops.HTTPStatus(http.StatusNotFound).Errorf("invoice not found")
+		case "/error":
+			return errors.New("secret")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/oops", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	is.Contains(rec.Body.String(), "<h1>invoice not found</h1>")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/error", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	is.Equal(http.StatusNoContent, rec.Code)
+	is.Empty(rec.Body.String())
+}
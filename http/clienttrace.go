@@ -0,0 +1,98 @@
+package oopshttp
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// WithClientTrace returns a context recording DNS, connect, TLS and TTFB timings
+// of an http request, using `net/http/httptrace`. Timings are attached under the
+// `http_timings` context key of any error built with `oops.FromContext(ctx)`.
+//
+//	ctx := oopshttp.WithClientTrace(ctx)
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	res, err := http.DefaultClient.Do(req)
+//	if err != nil {
+//		return oops.FromContext(ctx).Wrap(err)
+//	}
+func WithClientTrace(ctx context.Context) context.Context {
+	timings := &clientTimings{}
+
+	ctx = httptrace.WithClientTrace(ctx, timings.clientTrace())
+
+	return oops.WithBuilder(
+		ctx,
+		oops.FromContext(ctx).With("http_timings", timings.toMap),
+	)
+}
+
+type clientTimings struct {
+	mu sync.Mutex
+
+	getConn           time.Time
+	dnsStart          time.Time
+	dnsDone           time.Time
+	connectStart      time.Time
+	connectDone       time.Time
+	tlsHandshakeStart time.Time
+	tlsHandshakeDone  time.Time
+	firstResponseByte time.Time
+	connReused        bool
+}
+
+func (t *clientTimings) record(field *time.Time) {
+	t.mu.Lock()
+	*field = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *clientTimings) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn:  func(string) { t.record(&t.getConn) },
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			if t.connectStart.IsZero() { // many attempts may run in parallel
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone:          func(string, string, error) { t.record(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.record(&t.tlsHandshakeStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.record(&t.tlsHandshakeDone) },
+		GotFirstResponseByte: func() { t.record(&t.firstResponseByte) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.connReused = info.Reused
+			t.mu.Unlock()
+		},
+	}
+}
+
+func (t *clientTimings) toMap() map[string]any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timings := map[string]any{
+		"conn_reused": t.connReused,
+	}
+
+	set := func(key string, start time.Time, end time.Time) {
+		if !start.IsZero() && !end.IsZero() {
+			timings[key] = end.Sub(start).String()
+		}
+	}
+
+	set("dns", t.dnsStart, t.dnsDone)
+	set("connect", t.connectStart, t.connectDone)
+	set("tls", t.tlsHandshakeStart, t.tlsHandshakeDone)
+	set("ttfb", t.getConn, t.firstResponseByte)
+
+	return timings
+}
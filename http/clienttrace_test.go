@@ -0,0 +1,40 @@
+package oopshttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithClientTrace(t *testing.T) {
+	is := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx := WithClientTrace(context.Background())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	is.NoError(err)
+
+	res, err := http.DefaultClient.Do(req)
+	is.NoError(err)
+	is.NoError(res.Body.Close())
+
+	err = oops.FromContext(ctx).Errorf("unexpected status code %d", res.StatusCode)
+
+	oopsErr, ok := oops.AsOops(err)
+	is.True(ok)
+
+	timings, ok := oopsErr.Context()["http_timings"].(map[string]any)
+	is.True(ok)
+	is.Contains(timings, "connect")
+	is.Contains(timings, "ttfb")
+	is.Equal(false, timings["conn_reused"])
+}
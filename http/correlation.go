@@ -0,0 +1,69 @@
+package oopshttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/samber/oops"
+)
+
+// CorrelationOptions configures the correlation middleware.
+type CorrelationOptions struct {
+	// ResponseHeader echoes the trace id in the response. Defaults to `X-Request-ID`.
+	ResponseHeader string
+}
+
+// Correlation seeds a builder in the context of each request (see `oops.WithBuilder`),
+// with a trace id extracted from the correlation headers of the request, by priority:
+// `X-Request-ID`, W3C `traceparent` and `X-Amzn-Trace-Id`. A trace id is generated
+// when none is found (see `oops.SetIDGenerator`). The trace id is echoed in
+// the `X-Request-ID` response header.
+//
+//	http.ListenAndServe(":8080", oopshttp.Correlation(mux))
+func Correlation(next http.Handler) http.Handler {
+	return CorrelationWith(CorrelationOptions{})(next)
+}
+
+// CorrelationWith returns a correlation middleware with custom options.
+func CorrelationWith(opts CorrelationOptions) func(http.Handler) http.Handler {
+	responseHeader := opts.ResponseHeader
+	if responseHeader == "" {
+		responseHeader = "X-Request-ID"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace := TraceFromHeaders(r.Header)
+			if trace == "" {
+				trace = oops.NewID()
+			}
+
+			w.Header().Set(responseHeader, trace)
+
+			ctx := oops.WithBuilder(r.Context(), oops.FromContext(r.Context()).Trace(trace))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TraceFromHeaders returns the trace id found in the `X-Request-ID`, `traceparent`
+// or `X-Amzn-Trace-Id` headers, or an empty string.
+func TraceFromHeaders(header http.Header) string {
+	if id := header.Get("X-Request-ID"); id != "" {
+		return id
+	}
+
+	// version-traceid-parentid-flags
+	if parts := strings.Split(header.Get("traceparent"), "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+
+	// Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1
+	for _, field := range strings.Split(header.Get("X-Amzn-Trace-Id"), ";") {
+		if root, ok := strings.CutPrefix(strings.TrimSpace(field), "Root="); ok && root != "" {
+			return root
+		}
+	}
+
+	return ""
+}
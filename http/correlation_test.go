@@ -0,0 +1,50 @@
+package oopshttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceFromHeaders(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("", TraceFromHeaders(http.Header{}))
+	is.Equal("1234", TraceFromHeaders(http.Header{"X-Request-Id": []string{"1234"}, "Traceparent": []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}))
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", TraceFromHeaders(http.Header{"Traceparent": []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}))
+	is.Equal("1-5759e988-bd862e3fe1be46a994272793", TraceFromHeaders(http.Header{"X-Amzn-Trace-Id": []string{"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"}}))
+	is.Equal("", TraceFromHeaders(http.Header{"Traceparent": []string{"invalid"}}))
+}
+
+func TestCorrelation(t *testing.T) {
+	is := assert.New(t)
+
+	var err error
+	handler := Correlation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err = oops.FromContext(r.Context()).Errorf("invoice not found")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Amzn-Trace-Id", "Root=1-5759e988-bd862e3fe1be46a994272793")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	is.Equal("1-5759e988-bd862e3fe1be46a994272793", rec.Header().Get("X-Request-ID"))
+	is.Equal("1-5759e988-bd862e3fe1be46a994272793", oops.GetTrace(err))
+
+	// generated trace id
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	is.NotEmpty(rec.Header().Get("X-Request-ID"))
+	is.Equal(rec.Header().Get("X-Request-ID"), oops.GetTrace(err))
+
+	// custom response header
+	handler = CorrelationWith(CorrelationOptions{ResponseHeader: "X-Trace-Id"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "1234")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	is.Equal("1234", rec.Header().Get("X-Trace-Id"))
+}
@@ -0,0 +1,122 @@
+package oopshttp
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/samber/oops"
+)
+
+// HandlerOptions configures the error-returning handler.
+type HandlerOptions struct {
+	// Logger is called with the errors returned by handlers. Defaults to `slog.Default()`.
+	Logger func(r *http.Request, err error)
+	// StatusCodes maps error codes to http statuses, for errors without http status.
+	// Defaults to the codes of the oops integrations: `not_found` (404),
+	// `unique_violation`, `foreign_key_violation` and `duplicate_key` (409),
+	// `not_null_violation` and `check_violation` (422), `too_many_connections`
+	// (503) and `timeout` (504).
+	StatusCodes map[string]int
+	// Problem writes errors as RFC 7807 problem details (see WriteProblem),
+	// instead of plain text.
+	Problem bool
+}
+
+var defaultStatusCodes = map[string]int{
+	// oopssql, oopsredis, oopsmongo
+	"not_found": http.StatusNotFound,
+	// oopssql
+	"unique_violation":      http.StatusConflict,
+	"foreign_key_violation": http.StatusConflict,
+	"not_null_violation":    http.StatusUnprocessableEntity,
+	"check_violation":       http.StatusUnprocessableEntity,
+	"too_many_connections":  http.StatusServiceUnavailable,
+	// oopsmongo
+	"duplicate_key": http.StatusConflict,
+	"timeout":       http.StatusGatewayTimeout,
+}
+
+func defaultLogger(r *http.Request, err error) {
+	slog.Default().ErrorContext(r.Context(), err.Error(), slog.Any("error", err))
+}
+
+// Handler is an http handler returning an error. Errors are logged with
+// `slog.Default()`, and written as plain text: the public message of the error
+// (default: the text of the status) with its http status. Without http status,
+// the status is derived from the error code (eg: `not_found` set by oopssql,
+// oopsredis or oopsmongo is 404), or defaults to 500.
+//
+//	http.Handle("/invoices", oopshttp.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		invoice, err := repo.Get(r.Context(), r.URL.Query().Get("id"))
+//		if err != nil {
+//			return oops.HTTPStatus(404).Public("Invoice not found.").Wrap(err)
+//		}
+//		return json.NewEncoder(w).Encode(invoice)
+//	}))
+type Handler func(w http.ResponseWriter, r *http.Request) error
+
+var _ http.Handler = (Handler)(nil)
+
+// ServeHTTP implements http.Handler.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serve(w, r, h, HandlerOptions{Logger: defaultLogger, StatusCodes: defaultStatusCodes})
+}
+
+// HandlerWith returns a handler wrapper with custom options.
+//
+//	handler := oopshttp.HandlerWith(oopshttp.HandlerOptions{Problem: true})
+//	http.Handle("/invoices", handler(func(w http.ResponseWriter, r *http.Request) error {
+//		...
+//	}))
+func HandlerWith(opts HandlerOptions) func(Handler) http.Handler {
+	if opts.Logger == nil {
+		opts.Logger = defaultLogger
+	}
+
+	if opts.StatusCodes == nil {
+		opts.StatusCodes = defaultStatusCodes
+	}
+
+	return func(h Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			serve(w, r, h, opts)
+		})
+	}
+}
+
+func serve(w http.ResponseWriter, r *http.Request, h Handler, opts HandlerOptions) {
+	err := h(w, r)
+	if err == nil {
+		return
+	}
+
+	opts.Logger(r, err)
+
+	status := statusOf(err, opts.StatusCodes)
+
+	if opts.Problem {
+		writeProblem(w, err, status)
+		return
+	}
+
+	http.Error(w, oops.GetPublic(err, http.StatusText(status)), status)
+}
+
+// statusOf returns the http status of the error, or the status of its code,
+// or 500.
+func statusOf(err error, codes map[string]int) int {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	if status := oopsError.HTTPStatus(); status != 0 {
+		return status
+	}
+
+	if status, ok := codes[oopsError.Code()]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
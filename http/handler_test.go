@@ -0,0 +1,99 @@
+package oopshttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	is := assert.New(t)
+
+	var logged error
+	handler := HandlerWith(HandlerOptions{
+		Logger: func(r *http.Request, err error) {
+			logged = err
+		},
+	})(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/oops":
+			return oops.HTTPStatus(404).Public("Invoice not found.").Errorf("sql: no rows")
+		case "/not_found":
+			return oops.Code("not_found").Errorf("sql: no rows")
+		case "/error":
+			return errors.New("secret")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	is.Equal(http.StatusNoContent, rec.Code)
+	is.Nil(logged)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oops", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("Invoice not found.\n", rec.Body.String())
+	is.EqualError(logged, "sql: no rows")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/not_found", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("Not Found\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/error", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.Equal("Internal Server Error\n", rec.Body.String())
+	is.EqualError(logged, "secret")
+}
+
+func TestHandlerDefaultStatusCodes(t *testing.T) {
+	is := assert.New(t)
+
+	handler := HandlerWith(HandlerOptions{
+		Logger: func(r *http.Request, err error) {},
+	})(func(w http.ResponseWriter, r *http.Request) error {
+		return oops.Code(r.URL.Path[1:]).Errorf("failed")
+	})
+
+	for code, status := range map[string]int{
+		"not_found":             http.StatusNotFound,
+		"unique_violation":      http.StatusConflict,
+		"foreign_key_violation": http.StatusConflict,
+		"duplicate_key":         http.StatusConflict,
+		"check_violation":       http.StatusUnprocessableEntity,
+		"timeout":               http.StatusGatewayTimeout,
+		"unknown":               http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+code, nil))
+		is.Equal(status, rec.Code, code)
+	}
+}
+
+func TestHandlerStatusCodes(t *testing.T) {
+	is := assert.New(t)
+
+	handler := HandlerWith(HandlerOptions{
+		Logger:      func(r *http.Request, err error) {},
+		StatusCodes: map[string]int{"quota_exceeded": http.StatusTooManyRequests},
+	})(func(w http.ResponseWriter, r *http.Request) error {
+		return oops.Code(r.URL.Path[1:]).Errorf("failed")
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/quota_exceeded", nil))
+	is.Equal(http.StatusTooManyRequests, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/not_found", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+}
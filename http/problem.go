@@ -0,0 +1,34 @@
+package oopshttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/samber/oops"
+)
+
+// WriteProblem writes err as an RFC 7807 `application/problem+json` response
+// (see `oops.OopsError.ToProblemDetails`). Without http status, the status is
+// derived from the error code, as Handler does. Non-oops errors are reported as
+// 500 Internal Server Error, without detail.
+func WriteProblem(w http.ResponseWriter, err error) {
+	writeProblem(w, err, statusOf(err, defaultStatusCodes))
+}
+
+func writeProblem(w http.ResponseWriter, err error, status int) {
+	problem := oops.ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	if oopsError, ok := oops.AsOops(err); ok {
+		problem = oopsError.ToProblemDetails()
+		problem.Title = http.StatusText(status)
+		problem.Status = status
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
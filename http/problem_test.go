@@ -0,0 +1,59 @@
+package oopshttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerProblem(t *testing.T) {
+	is := assert.New(t)
+
+	handler := HandlerWith(HandlerOptions{
+		Logger:  func(r *http.Request, err error) {},
+		Problem: true,
+	})(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/oops":
+			return oops.
+				Code("billing.invoice_not_found").
+				In("billing").
+				Trace("1234").
+				HTTPStatus(http.StatusNotFound).
+				Public("Invoice not found.").
+				Errorf("invoice 42 not found")
+		case "/not_found":
+			return oops.Code("not_found").Trace("5678").Errorf("sql: no rows")
+		case "/error":
+			return errors.New("secret")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/oops", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("application/problem+json", rec.Header().Get("Content-Type"))
+	is.JSONEq(`{"type":"billing.invoice_not_found","title":"Not Found","status":404,"detail":"Invoice not found.","domain":"billing","trace":"1234"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/not_found", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.JSONEq(`{"type":"not_found","title":"Not Found","status":404,"trace":"5678"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/error", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.JSONEq(`{"type":"about:blank","title":"Internal Server Error","status":500}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	is.Equal(http.StatusNoContent, rec.Code)
+	is.Empty(rec.Body.String())
+}
@@ -0,0 +1,46 @@
+package oops
+
+import (
+	"sync"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var idGeneratorMutex sync.RWMutex
+var idGenerator = defaultIDGenerator
+
+// LazySpanID defers the generation of the default span id of an error to the
+// first call to `err.Span()`, so that errors whose span is never read do not
+// pay for it. Spans set with `Span()` or extracted from a context are kept.
+var LazySpanID = false
+
+func defaultIDGenerator() string {
+	return ulid.Make().String()
+}
+
+// SetIDGenerator overrides the generator of default trace and span ids
+// (eg: W3C trace context or UUIDv7 identifiers). Default: ULID.
+// A nil generator restores the default one.
+func SetIDGenerator(generator func() string) {
+	if generator == nil {
+		generator = defaultIDGenerator
+	}
+
+	idGeneratorMutex.Lock()
+	idGenerator = generator
+	idGeneratorMutex.Unlock()
+}
+
+func newID() string {
+	idGeneratorMutex.RLock()
+	generator := idGenerator
+	idGeneratorMutex.RUnlock()
+
+	return generator()
+}
+
+// NewID returns a new id, from the generator set with SetIDGenerator
+// (eg: to seed the trace of a request).
+func NewID() string {
+	return newID()
+}
@@ -0,0 +1,54 @@
+package oops
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetIDGenerator(t *testing.T) {
+	is := assert.New(t)
+
+	defer SetIDGenerator(nil)
+
+	SetIDGenerator(func() string { return "0af7651916cd43dd8448eb211c80319c" })
+
+	err := new().Errorf("permission denied")
+	is.Equal("0af7651916cd43dd8448eb211c80319c", err.(OopsError).Span())
+	is.Equal("0af7651916cd43dd8448eb211c80319c", err.(OopsError).Trace())
+
+	SetIDGenerator(nil)
+
+	err = new().Errorf("permission denied")
+	is.Len(err.(OopsError).Span(), 26) // ULID
+}
+
+func TestLazySpanID(t *testing.T) {
+	is := assert.New(t)
+
+	defer SetIDGenerator(nil)
+
+	generated := 0
+	SetIDGenerator(func() string {
+		generated++
+		return fmt.Sprintf("span-%d", generated)
+	})
+
+	LazySpanID = true
+	defer func() { LazySpanID = false }()
+
+	err := new().Errorf("permission denied").(OopsError)
+	is.Empty(err.span)
+	is.Equal(0, generated)
+
+	is.Equal("span-1", err.Span())
+	is.Equal("span-1", err.Span())
+	is.Equal(1, generated)
+
+	// explicit spans are kept
+	generated = 0
+	err = new().Span("1234").Errorf("permission denied").(OopsError)
+	is.Equal("1234", err.Span())
+	is.Equal(0, generated)
+}
@@ -0,0 +1,263 @@
+// Package integrationtest is a conformance test suite for logger integrations
+// (formatters, hooks, handlers...), including third-party ones. It checks that
+// an integration emits the attributes of oops errors, respects redaction, drops
+// stacktraces below the error level, and handles joined and regular errors.
+//
+//	func TestConformance(t *testing.T) {
+//		integrationtest.Run(t, func(t testing.TB, err error, level slog.Level) map[string]any {
+//			var buf bytes.Buffer
+//			logger := slog.New(oopsslog.NewHandler(slog.NewJSONHandler(&buf, nil), nil))
+//			logger.Log(context.Background(), level, "could not fetch user", slog.Any("error", err))
+//			return integrationtest.Decode(t, buf.Bytes(), "error")
+//		}, func(b oops.OopsErrorBuilder) error {
+//			return b.Errorf("permission denied")
+//		})
+//	}
+package integrationtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/samber/oops"
+)
+
+// Names of the checks, to be skipped with Options.Skip.
+const (
+	// CheckFields verifies that the attributes of the error are emitted.
+	CheckFields = "fields"
+	// CheckStacktraceLevel verifies that stacktraces are emitted at the error
+	// level, and dropped below.
+	CheckStacktraceLevel = "stacktrace_level"
+	// CheckRedaction verifies that values of redacted keys are not emitted.
+	CheckRedaction = "redaction"
+	// CheckJoinedErrors verifies that all messages of joined errors are emitted.
+	CheckJoinedErrors = "joined_errors"
+	// CheckRegularErrors verifies that errors not built with oops are left
+	// untouched.
+	CheckRegularErrors = "regular_errors"
+)
+
+// Log logs err with the integration under test, at level, and returns the
+// attributes emitted for the error: eg: the "error" object of a JSON entry, or
+// the fields of a logrus entry. See Decode.
+type Log func(t testing.TB, err error, level slog.Level) map[string]any
+
+// NewError builds an error with a builder prepared by a check, eg:
+// `func(b oops.OopsErrorBuilder) error { return b.Errorf("permission denied") }`.
+// It must be declared by the caller: the frames of the oops module, including
+// this package, are skipped from stacktraces.
+type NewError func(builder oops.OopsErrorBuilder) error
+
+// Options customizes the suite.
+type Options struct {
+	// Skip lists the checks that do not apply to the integration (eg:
+	// CheckStacktraceLevel for loggers without levels).
+	Skip []string
+}
+
+// Run runs the conformance suite against an integration, as subtests of t.
+func Run(t *testing.T, log Log, newError NewError) {
+	RunWith(t, log, newError, Options{})
+}
+
+// RunWith runs the conformance suite against an integration, with custom options.
+func RunWith(t *testing.T, log Log, newError NewError, opts Options) {
+	checks := []struct {
+		name string
+		run  func(t *testing.T, log Log, newError NewError)
+	}{
+		{CheckFields, checkFields},
+		{CheckStacktraceLevel, checkStacktraceLevel},
+		{CheckRedaction, checkRedaction},
+		{CheckJoinedErrors, checkJoinedErrors},
+		{CheckRegularErrors, checkRegularErrors},
+	}
+
+	for _, check := range checks {
+		check := check
+
+		t.Run(check.name, func(t *testing.T) {
+			for _, skipped := range opts.Skip {
+				if skipped == check.name {
+					t.Skip("skipped by the integration")
+				}
+			}
+
+			check.run(t, log, newError)
+		})
+	}
+}
+
+// Decode decodes a JSON log entry, and returns the object found at key, or the
+// entry itself when key is empty.
+func Decode(t testing.TB, data []byte, key string) map[string]any {
+	t.Helper()
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(data), &entry); err != nil {
+		t.Fatalf("integrationtest: invalid JSON entry: %s: %q", err.Error(), data)
+	}
+
+	if key == "" {
+		return entry
+	}
+
+	attrs, _ := entry[key].(map[string]any)
+	return attrs
+}
+
+// DecodeBuffer decodes the JSON log entry written to buf, like Decode, and
+// resets buf for the next entry.
+func DecodeBuffer(t testing.TB, buf *bytes.Buffer, key string) map[string]any {
+	t.Helper()
+
+	defer buf.Reset()
+	return Decode(t, buf.Bytes(), key)
+}
+
+func checkFields(t *testing.T, log Log, _ NewError) {
+	err := oops.
+		Code("iam_missing_permission").
+		In("authz").
+		Tags("auth").
+		Trace("1234").
+		Hint("ask an admin").
+		Owner("iam-team@acme.org").
+		With("user_id", 1234).
+		User("user-123", "email", "john@example.com").
+		Errorf("permission denied")
+
+	attrs := log(t, err, slog.LevelError)
+	if len(attrs) == 0 {
+		t.Fatalf("no attributes emitted")
+	}
+
+	expectEqual(t, "message", "permission denied", message(attrs))
+	expectEqual(t, "code", "iam_missing_permission", attrs["code"])
+	expectEqual(t, "domain", "authz", attrs["domain"])
+	expectEqual(t, "trace", "1234", attrs["trace"])
+	expectEqual(t, "hint", "ask an admin", attrs["hint"])
+	expectEqual(t, "owner", "iam-team@acme.org", attrs["owner"])
+	expectContains(t, "tags", fmt.Sprint(attrs["tags"]), "auth")
+	expectKey(t, attrs, "time")
+
+	if context, ok := attrs["context"].(map[string]any); ok {
+		expectEqual(t, "context.user_id", "1234", fmt.Sprint(context["user_id"]))
+	} else {
+		t.Errorf("context attribute is not an object: %#v", attrs["context"])
+	}
+
+	if user, ok := attrs["user"].(map[string]any); ok {
+		expectEqual(t, "user.id", "user-123", user["id"])
+		expectEqual(t, "user.email", "john@example.com", user["email"])
+	} else {
+		t.Errorf("user attribute is not an object: %#v", attrs["user"])
+	}
+}
+
+func checkStacktraceLevel(t *testing.T, log Log, newError NewError) {
+	err := newError(oops.Code("iam_missing_permission"))
+
+	attrs := log(t, err, slog.LevelError)
+	expectKey(t, attrs, "stacktrace")
+
+	attrs = log(t, err, slog.LevelWarn)
+	expectEqual(t, "code", "iam_missing_permission", attrs["code"])
+	expectNoKey(t, attrs, "stacktrace")
+	expectNoKey(t, attrs, "sources")
+}
+
+func checkRedaction(t *testing.T, log Log, _ NewError) {
+	err := oops.
+		RedactKeys("password", "api_key").
+		With("password", "hunter2").
+		User("user-123", "api_key", "sk_live_1234").
+		Errorf("could not sign in")
+
+	attrs := log(t, err, slog.LevelError)
+
+	context, _ := attrs["context"].(map[string]any)
+	expectEqual(t, "context.password", oops.RedactedValue, context["password"])
+
+	user, _ := attrs["user"].(map[string]any)
+	expectEqual(t, "user.api_key", oops.RedactedValue, user["api_key"])
+
+	output := fmt.Sprint(attrs)
+	for _, secret := range []string{"hunter2", "sk_live_1234"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("redacted value %q emitted: %s", secret, output)
+		}
+	}
+}
+
+func checkJoinedErrors(t *testing.T, log Log, _ NewError) {
+	err := oops.
+		Code("batch_failed").
+		Join(
+			oops.In("billing").Errorf("invoice 42 failed"),
+			errors.New("invoice 43 failed"),
+		)
+
+	attrs := log(t, err, slog.LevelError)
+	expectEqual(t, "code", "batch_failed", attrs["code"])
+	expectEqual(t, "domain", "billing", attrs["domain"])
+	expectContains(t, "message", message(attrs), "invoice 42 failed")
+	expectContains(t, "message", message(attrs), "invoice 43 failed")
+}
+
+func checkRegularErrors(t *testing.T, log Log, _ NewError) {
+	// attrs may be nil: regular errors are usually emitted as strings
+	attrs := log(t, errors.New("permission denied"), slog.LevelError)
+	expectNoKey(t, attrs, "code")
+	expectNoKey(t, attrs, "fingerprint")
+	expectNoKey(t, attrs, "stacktrace")
+}
+
+func expectEqual(t *testing.T, name string, want any, got any) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("%s: want %#v, got %#v", name, want, got)
+	}
+}
+
+func expectContains(t *testing.T, name string, s string, substr string) {
+	t.Helper()
+
+	if !strings.Contains(s, substr) {
+		t.Errorf("%s: %q does not contain %q", name, s, substr)
+	}
+}
+
+func expectKey(t *testing.T, attrs map[string]any, key string) {
+	t.Helper()
+
+	if _, ok := attrs[key]; !ok {
+		t.Errorf("%s attribute not emitted", key)
+	}
+}
+
+func expectNoKey(t *testing.T, attrs map[string]any, key string) {
+	t.Helper()
+
+	if value, ok := attrs[key]; ok {
+		t.Errorf("%s attribute emitted: %#v", key, value)
+	}
+}
+
+// message returns the error message: "error" in ToMap() outputs, "err" in
+// LogValuer() outputs.
+func message(attrs map[string]any) string {
+	if msg, ok := attrs["error"].(string); ok {
+		return msg
+	}
+
+	msg, _ := attrs["err"].(string)
+	return msg
+}
@@ -0,0 +1,95 @@
+package oopsjwt
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/samber/oops"
+)
+
+// Options maps the claims of a token to the user and the tenant of errors.
+type Options struct {
+	// UserClaim is the claim of the user id. Defaults to "sub".
+	UserClaim string
+	// TenantClaim is the claim of the tenant id. Defaults to "org_id".
+	TenantClaim string
+	// UserAttributes are claims copied into the user data (eg: "email").
+	UserAttributes []string
+	// TenantAttributes are claims copied into the tenant data (eg: "org_name").
+	TenantAttributes []string
+}
+
+// Parser returns the verified claims of a request, eg: with github.com/golang-jwt/jwt.
+type Parser func(r *http.Request) (map[string]any, error)
+
+// FromClaims sets the user (`sub` claim) and the tenant (`org_id` claim) of a builder.
+func FromClaims(builder oops.OopsErrorBuilder, claims map[string]any) oops.OopsErrorBuilder {
+	return FromClaimsWith(builder, claims, Options{})
+}
+
+// FromClaimsWith sets the user and the tenant of a builder, with custom claims.
+// Missing claims are ignored.
+func FromClaimsWith(builder oops.OopsErrorBuilder, claims map[string]any, opts Options) oops.OopsErrorBuilder {
+	userClaim := opts.UserClaim
+	if userClaim == "" {
+		userClaim = "sub"
+	}
+
+	tenantClaim := opts.TenantClaim
+	if tenantClaim == "" {
+		tenantClaim = "org_id"
+	}
+
+	if userID, ok := claims[userClaim]; ok {
+		builder = builder.User(fmt.Sprint(userID), attributes(claims, opts.UserAttributes)...)
+	}
+
+	if tenantID, ok := claims[tenantClaim]; ok {
+		builder = builder.Tenant(fmt.Sprint(tenantID), attributes(claims, opts.TenantAttributes)...)
+	}
+
+	return builder
+}
+
+// Middleware stores a builder in the context of each request (see `oops.WithBuilder`),
+// with the user and the tenant of the claims returned by parse. Requests
+// without valid token are served without identity.
+//
+//	router.Use(oopsjwt.Middleware(func(r *http.Request) (map[string]any, error) {
+//		token, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), keyFunc)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return token.Claims.(jwt.MapClaims), nil
+//	}))
+func Middleware(parse Parser) func(http.Handler) http.Handler {
+	return MiddlewareWith(parse, Options{})
+}
+
+// MiddlewareWith returns a middleware with custom claims.
+func MiddlewareWith(parse Parser, opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := parse(r)
+			if err != nil || len(claims) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			builder := FromClaimsWith(oops.FromContext(r.Context()), claims, opts)
+			next.ServeHTTP(w, r.WithContext(oops.WithBuilder(r.Context(), builder)))
+		})
+	}
+}
+
+func attributes(claims map[string]any, keys []string) []any {
+	output := []any{}
+
+	for _, key := range keys {
+		if v, ok := claims[key]; ok {
+			output = append(output, key, v)
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,65 @@
+package oopsjwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromClaims(t *testing.T) {
+	is := assert.New(t)
+
+	claims := map[string]any{"sub": "user-123", "org_id": "acme", "email": "john@example.com", "tenant": 42.0}
+
+	err := FromClaims(oops.In("billing"), claims).Errorf("permission denied").(oops.OopsError)
+	userID, userData := err.User()
+	is.Equal("user-123", userID)
+	is.Empty(userData)
+	tenantID, _ := err.Tenant()
+	is.Equal("acme", tenantID)
+	is.Equal("billing", err.Domain())
+
+	err = FromClaimsWith(oops.OopsErrorBuilder{}, claims, Options{TenantClaim: "tenant", UserAttributes: []string{"email", "missing"}}).Errorf("permission denied").(oops.OopsError)
+	userID, userData = err.User()
+	is.Equal("user-123", userID)
+	is.Equal(map[string]any{"email": "john@example.com"}, userData)
+	tenantID, _ = err.Tenant()
+	is.Equal("42", tenantID)
+
+	err = FromClaims(oops.OopsErrorBuilder{}, map[string]any{}).Errorf("permission denied").(oops.OopsError)
+	userID, _ = err.User()
+	is.Empty(userID)
+}
+
+func TestMiddleware(t *testing.T) {
+	is := assert.New(t)
+
+	var err error
+	handler := Middleware(func(r *http.Request) (map[string]any, error) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			return nil, errors.New("invalid token")
+		}
+		return map[string]any{"sub": "user-123", "org_id": "acme"}, nil
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err = oops.FromContext(r.Context()).Errorf("permission denied")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer valid")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	oopsError, _ := oops.AsOops(err)
+	userID, _ := oopsError.User()
+	tenantID, _ := oopsError.Tenant()
+	is.Equal("user-123", userID)
+	is.Equal("acme", tenantID)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	oopsError, _ = oops.AsOops(err)
+	userID, _ = oopsError.User()
+	is.Empty(userID)
+}
@@ -1,7 +1,9 @@
 package oops
 
 import (
+	"log/slog"
 	"reflect"
+	"time"
 
 	"github.com/samber/lo"
 )
@@ -12,8 +14,17 @@ func dereferencePointers(data map[string]any) map[string]any {
 	}
 
 	for key, value := range data {
+		if isPlainValue(value) {
+			continue
+		}
+
 		val := reflect.ValueOf(value)
 		if val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				data[key] = nil
+				continue
+			}
+
 			// @TODO: might be a pointer to a pointer
 			data[key] = val.Elem().Interface()
 		}
@@ -23,11 +34,37 @@ func dereferencePointers(data map[string]any) map[string]any {
 
 }
 
+// isPlainValue reports whether value has a common type that is neither a
+// pointer nor a function, so that it can skip reflection.
+func isPlainValue(value any) bool {
+	switch value.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64,
+		float32, float64, time.Time, time.Duration, []string, []any, map[string]any:
+		return true
+	}
+
+	return false
+}
+
+// mapToSlogAttrs converts a map into slog attributes. Nested maps are converted
+// into slog groups.
+func mapToSlogAttrs(data map[string]any) []slog.Attr {
+	// keys are sorted for a stable output
+	return lo.Map(sortedKeys(data), func(k string, _ int) slog.Attr {
+		if nested, ok := data[k].(map[string]any); ok {
+			return slog.Group(k, lo.ToAnySlice(mapToSlogAttrs(nested))...)
+		}
+
+		return slog.Any(k, data[k])
+	})
+}
+
 func lazyMapEvaluation(data map[string]any) map[string]any {
 	for key, value := range data {
 		switch v := value.(type) {
 		case map[string]any:
-			data[key] = lazyMapEvaluation(v)
+			// nested maps are shared with the builders: evaluate into a copy
+			data[key] = lazyMapEvaluation(lo.Assign(map[string]any{}, v))
 		default:
 			data[key] = lazyValueEvaluation(value)
 		}
@@ -37,6 +74,10 @@ func lazyMapEvaluation(data map[string]any) map[string]any {
 }
 
 func lazyValueEvaluation(value any) any {
+	if isPlainValue(value) {
+		return value
+	}
+
 	v := reflect.ValueOf(value)
 	if !v.IsValid() || v.Kind() != reflect.Func {
 		return value
@@ -49,26 +90,99 @@ func lazyValueEvaluation(value any) any {
 	return v.Call([]reflect.Value{})[0].Interface()
 }
 
+func getErrorAttribute[T comparable](err OopsError, getter func(OopsError) T) T {
+	if getResolutionStrategy(err) == ResolutionShallowFirst {
+		return getShallowestErrorAttribute(err, getter)
+	}
+
+	return getDeepestErrorAttribute(err, getter)
+}
+
+// getTranslatedErrorAttribute resolves an attribute rewritten by Translate
+// (code, domain, public message): the outermost translated value is not
+// overridden by wrapped errors.
+func getTranslatedErrorAttribute[T comparable](err OopsError, getter func(OopsError) T) T {
+	var zero T
+
+	for _, e := range err.chain() {
+		if !e.translated {
+			continue
+		}
+
+		if value := getter(e); value != zero {
+			return value
+		}
+	}
+
+	return getErrorAttribute(err, getter)
+}
+
+func getResolutionStrategy(err OopsError) ResolutionStrategy {
+	strategy := getShallowestErrorAttribute(
+		err,
+		func(e OopsError) ResolutionStrategy {
+			return e.resolution
+		},
+	)
+
+	if strategy == 0 {
+		return AttributeResolution
+	}
+
+	return strategy
+}
+
 func getDeepestErrorAttribute[T comparable](err OopsError, getter func(OopsError) T) T {
-	if err.err == nil {
-		return getter(err)
+	var zero T
+
+	chain := err.chain()
+	for i := len(chain) - 1; i >= 0; i-- {
+		if value := getter(chain[i]); value != zero {
+			return value
+		}
 	}
 
-	if child, ok := AsOops(err.err); ok {
-		return coalesceOrEmpty(getDeepestErrorAttribute(child, getter), getter(err))
+	return zero
+}
+
+func getShallowestErrorAttribute[T comparable](err OopsError, getter func(OopsError) T) T {
+	var zero T
+
+	for _, e := range err.chain() {
+		if value := getter(e); value != zero {
+			return value
+		}
 	}
 
-	return getter(err)
+	return zero
 }
 
 func mergeNestedErrorMap(err OopsError, getter func(OopsError) map[string]any) map[string]any {
-	if err.err == nil {
-		return getter(err)
+	maps := []map[string]any{}
+
+	recursive(err, func(e OopsError) {
+		maps = append(maps, getter(e))
+	})
+
+	// last map wins
+	if getResolutionStrategy(err) == ResolutionShallowFirst {
+		maps = lo.Reverse(maps)
 	}
 
-	if child, ok := AsOops(err.err); ok {
-		return lo.Assign(map[string]any{}, getter(err), mergeNestedErrorMap(child, getter))
+	return lo.Assign(maps...)
+}
+
+// copyNestedMap copies a map and its nested maps.
+func copyNestedMap(data map[string]any) map[string]any {
+	output := make(map[string]any, len(data))
+
+	for k, v := range data {
+		if nested, ok := v.(map[string]any); ok {
+			output[k] = copyNestedMap(nested)
+		} else {
+			output[k] = v
+		}
 	}
 
-	return getter(err)
+	return output
 }
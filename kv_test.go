@@ -2,6 +2,7 @@ package oops
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -20,3 +21,53 @@ func TestDereferencePointers(t *testing.T) {
 	err = With("hello", nil).Errorf(assert.AnError.Error()).(OopsError) //nolint:govet
 	is.EqualValues(map[string]any{"hello": nil}, err.Context())
 }
+
+func TestDereferencePointersNil(t *testing.T) {
+	is := assert.New(t)
+
+	var user *string
+	err := With("user", user, "count", 42).Errorf(assert.AnError.Error()).(OopsError) //nolint:govet
+	is.EqualValues(map[string]any{"user": nil, "count": 42}, err.Context())
+}
+
+func TestContextCache(t *testing.T) {
+	is := assert.New(t)
+
+	calls := 0
+	group := map[string]any{"bar": func() string { calls++; return "baz" }}
+	err := With("foo", group).Errorf(assert.AnError.Error()).(OopsError) //nolint:govet
+
+	is.Equal(map[string]any{"foo": map[string]any{"bar": "baz"}}, err.Context())
+	is.Equal(map[string]any{"foo": map[string]any{"bar": "baz"}}, err.Context())
+	is.Equal(1, calls)
+
+	// the maps of the builder are not altered
+	_, isFunc := group["bar"].(func() string)
+	is.True(isFunc)
+
+	// callers get a copy
+	err.Context()["foo"].(map[string]any)["bar"] = "qux"
+	is.Equal(map[string]any{"foo": map[string]any{"bar": "baz"}}, err.Context())
+}
+
+func BenchmarkContext(b *testing.B) {
+	ptr := func(v string) *string { return &v }
+	err := With("user_id", 1234, "email", ptr("foo@bar.com"), "query", "SELECT 1", "duration", time.Second).Errorf("a message").(OopsError)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Context()
+	}
+}
+
+func BenchmarkAttributeResolution(b *testing.B) {
+	err := error(assert.AnError)
+	for i := 0; i < 10; i++ {
+		err = With("depth", i).Code("code").WithoutStacktrace().Wrapf(err, "level %d", i)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.(OopsError).ToMap()
+	}
+}
@@ -0,0 +1,125 @@
+package oopslogr
+
+import (
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/oops"
+)
+
+// KeysAndValues returns the attributes of an oops error as logr key/value
+// pairs, sorted by key. The error message is not included, since logr sinks
+// receive the error itself. Non-oops errors have no attributes.
+//
+//	logger.Error(err, "could not fetch user", oopslogr.KeysAndValues(err)...)
+func KeysAndValues(err error) []any {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return []any{}
+	}
+
+	payload := oopsError.ToMap()
+	delete(payload, "error")
+
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kv := make([]any, 0, len(keys)*2)
+	for _, k := range keys {
+		kv = append(kv, k, payload[k])
+	}
+
+	return kv
+}
+
+// NewLogSink wraps the logr.LogSink of an initialized logger (see
+// `logr.Logger.GetSink`): attributes of oops errors passed to
+// `logger.Error(err, ...)` are appended to the key/value pairs, and oops errors
+// found in values are converted to maps.
+//
+//	logger := logr.New(oopslogr.NewLogSink(zapr.NewLogger(zapLogger).GetSink()))
+func NewLogSink(inner logr.LogSink) logr.LogSink {
+	return &logSink{
+		inner: inner,
+	}
+}
+
+type logSink struct {
+	inner logr.LogSink
+}
+
+var _ logr.LogSink = (*logSink)(nil)
+var _ logr.CallDepthLogSink = (*logSink)(nil)
+
+// Init implements logr.LogSink.
+func (s *logSink) Init(info logr.RuntimeInfo) {
+	// calls are forwarded to the inner sink, which adds a frame. The frames
+	// of logr are already skipped: the inner sink comes from an initialized
+	// logger (see NewLogSink), and sinks add up the call depths.
+	s.inner.Init(logr.RuntimeInfo{CallDepth: 1})
+}
+
+// Enabled implements logr.LogSink.
+func (s *logSink) Enabled(level int) bool {
+	return s.inner.Enabled(level)
+}
+
+// Info implements logr.LogSink.
+func (s *logSink) Info(level int, msg string, keysAndValues ...any) {
+	s.inner.Info(level, msg, transformValues(keysAndValues)...)
+}
+
+// Error implements logr.LogSink.
+func (s *logSink) Error(err error, msg string, keysAndValues ...any) {
+	keysAndValues = append(transformValues(keysAndValues), KeysAndValues(err)...)
+	s.inner.Error(err, msg, keysAndValues...)
+}
+
+// WithValues implements logr.LogSink.
+func (s *logSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logSink{
+		inner: s.inner.WithValues(transformValues(keysAndValues)...),
+	}
+}
+
+// WithName implements logr.LogSink.
+func (s *logSink) WithName(name string) logr.LogSink {
+	return &logSink{
+		inner: s.inner.WithName(name),
+	}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink.
+func (s *logSink) WithCallDepth(depth int) logr.LogSink {
+	if inner, ok := s.inner.(logr.CallDepthLogSink); ok {
+		return &logSink{
+			inner: inner.WithCallDepth(depth),
+		}
+	}
+
+	return s
+}
+
+func transformValues(keysAndValues []any) []any {
+	output := make([]any, len(keysAndValues))
+
+	for i, v := range keysAndValues {
+		output[i] = v
+
+		// keys are at even indexes
+		if i%2 == 0 {
+			continue
+		}
+
+		if err, ok := v.(error); ok {
+			if oopsError, ok := oops.AsOops(err); ok {
+				output[i] = oopsError.ToMap()
+			}
+		}
+	}
+
+	return output
+}
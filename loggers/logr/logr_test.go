@@ -0,0 +1,123 @@
+package oopslogr
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/samber/oops"
+	"github.com/samber/oops/integrationtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysAndValues(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Date(2023, 5, 2, 5, 26, 48, 570837000, time.UTC)
+
+	err := oops.
+		Code("iam_missing_permission").
+		Time(now).
+		Trace("1234").
+		With("user_id", 1234).
+		WithoutStacktrace().
+		Errorf("permission denied")
+
+	is.Equal(
+		[]any{
+			"code", "iam_missing_permission",
+			"context", map[string]any{"user_id": 1234},
+			"time", now,
+			"trace", "1234",
+		},
+		KeysAndValues(err),
+	)
+	is.Empty(KeysAndValues(errors.New("secret")))
+}
+
+func TestLogSink(t *testing.T) {
+	is := assert.New(t)
+
+	lines := []string{}
+	inner := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+
+	logger := logr.New(NewLogSink(inner.GetSink()))
+
+	err := oops.
+		Code("iam_missing_permission").
+		Trace("1234").
+		WithoutStacktrace().
+		Errorf("permission denied")
+
+	logger.Error(err, "could not fetch user", "user_id", 1234)
+	is.Len(lines, 1)
+	is.Contains(lines[0], `"msg"="could not fetch user" "error"="permission denied" "user_id"=1234 "code"="iam_missing_permission"`)
+	is.Contains(lines[0], `"trace"="1234"`)
+
+	logger.Info("could not fetch user", "cause", err)
+	is.Len(lines, 2)
+	is.Contains(lines[1], `"cause"={`)
+	is.Contains(lines[1], `"code"="iam_missing_permission"`)
+
+	logger.WithName("controller").WithValues("cause", err).Info("reconciling")
+	is.Len(lines, 3)
+	is.Contains(lines[2], `"code"="iam_missing_permission"`)
+
+	// non-oops errors
+	logger.Error(errors.New("secret"), "could not fetch user")
+	is.Len(lines, 4)
+	is.Equal(`"msg"="could not fetch user" "error"="secret"`, lines[3])
+}
+
+func TestLogSinkCaller(t *testing.T) {
+	is := assert.New(t)
+
+	var output string
+	inner := funcr.NewJSON(func(obj string) {
+		output = obj
+	}, funcr.Options{LogCaller: funcr.All})
+
+	logger := logr.New(NewLogSink(inner.GetSink()))
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Error(oops.Errorf("permission denied"), "could not fetch user")
+
+	var entry map[string]any
+	is.NoError(json.Unmarshal([]byte(output), &entry))
+	is.Equal(map[string]any{"file": "logr_test.go", "line": float64(line + 1)}, entry["caller"])
+
+	_, _, line, _ = runtime.Caller(0)
+	logger.WithName("controller").Info("reconciling")
+
+	is.NoError(json.Unmarshal([]byte(output), &entry))
+	is.Equal(map[string]any{"file": "logr_test.go", "line": float64(line + 1)}, entry["caller"])
+}
+
+func TestConformance(t *testing.T) {
+	// logr has no error level below which stacktraces would be dropped
+	integrationtest.RunWith(t, func(t testing.TB, err error, level slog.Level) map[string]any {
+		var output string
+		inner := funcr.NewJSON(func(obj string) {
+			output = obj
+		}, funcr.Options{})
+
+		logger := logr.New(NewLogSink(inner.GetSink()))
+		logger.Error(err, "could not fetch user")
+
+		// attributes are emitted at the top level of the entry
+		return integrationtest.Decode(t, []byte(output), "")
+	}, newError, integrationtest.Options{
+		Skip: []string{integrationtest.CheckStacktraceLevel},
+	})
+}
+
+func newError(builder oops.OopsErrorBuilder) error {
+	return builder.Errorf("permission denied")
+}
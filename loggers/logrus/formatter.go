@@ -1,40 +1,72 @@
 package oopslogrus
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/samber/oops"
 	"github.com/sirupsen/logrus"
 )
 
 func NewOopsFormatter(secondaryFormatter logrus.Formatter) *oopsFormatter {
+	return NewOopsFormatterWithOptions(secondaryFormatter, FormatterOptions{})
+}
+
+// FormatterOptions configures the oops formatter.
+type FormatterOptions struct {
+	// LevelFromSeverity sets the level of entries from the severity of the
+	// error (eg: a `warning` error logged with `logrus.Error` is emitted as
+	// warning). Errors without severity keep the level of the entry.
+	LevelFromSeverity bool
+}
+
+func NewOopsFormatterWithOptions(secondaryFormatter logrus.Formatter, opts FormatterOptions) *oopsFormatter {
 	if secondaryFormatter == nil {
 		secondaryFormatter = logrus.StandardLogger().Formatter
 	}
 
 	return &oopsFormatter{
-		formatter: secondaryFormatter,
+		formatter:         secondaryFormatter,
+		levelFromSeverity: opts.LevelFromSeverity,
 	}
 }
 
 type oopsFormatter struct {
-	formatter logrus.Formatter
+	formatter         logrus.Formatter
+	levelFromSeverity bool
 }
 
 func (f *oopsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	isOops := false
+
 	errField, ok := entry.Data["error"]
 	if ok {
 		switch err := errField.(type) {
 		case error:
 			var oopsError oops.OopsError
 			if errors.As(err, &oopsError) {
+				if f.levelFromSeverity {
+					entry.Level = severityToLevel(oopsError.Severity(), entry.Level)
+				}
+
 				oopsErrorToEntryData(&oopsError, entry)
+				isOops = true
 			}
 		case any:
 		}
 	}
 
-	return f.formatter.Format(entry)
+	output, err := f.formatter.Format(entry)
+	if err != nil && isOops {
+		// never drop the error because an enrichment cannot be rendered
+		dropUnmarshalableEntryData(entry, err)
+		return f.formatter.Format(entry)
+	}
+
+	return output, err
 }
 
 func oopsErrorToEntryData(err *oops.OopsError, entry *logrus.Entry) {
@@ -42,7 +74,8 @@ func oopsErrorToEntryData(err *oops.OopsError, entry *logrus.Entry) {
 
 	payload := err.ToMap()
 
-	if entry.Level < logrus.ErrorLevel {
+	// logrus levels decrease with severity: panic < fatal < error < warn...
+	if entry.Level > logrus.ErrorLevel {
 		delete(payload, "stacktrace")
 		delete(payload, "sources")
 	}
@@ -51,3 +84,43 @@ func oopsErrorToEntryData(err *oops.OopsError, entry *logrus.Entry) {
 		entry.Data[k] = v
 	}
 }
+
+func severityToLevel(severity oops.SeverityLevel, fallback logrus.Level) logrus.Level {
+	switch severity {
+	case oops.SeverityDebug:
+		return logrus.DebugLevel
+	case oops.SeverityInfo:
+		return logrus.InfoLevel
+	case oops.SeverityWarning:
+		return logrus.WarnLevel
+	case oops.SeverityError:
+		return logrus.ErrorLevel
+	case oops.SeverityFatal:
+		return logrus.FatalLevel
+	default:
+		return fallback
+	}
+}
+
+func dropUnmarshalableEntryData(entry *logrus.Entry, formatErr error) {
+	integrationErrors := []string{}
+
+	for k, v := range entry.Data {
+		if _, err := json.Marshal(v); err != nil {
+			integrationErrors = append(integrationErrors, fmt.Sprintf("%s marshaling: %s", k, err.Error()))
+			delete(entry.Data, k)
+		}
+	}
+
+	if len(integrationErrors) == 0 {
+		integrationErrors = append(integrationErrors, "formatting: "+formatErr.Error())
+	}
+
+	sort.Strings(integrationErrors)
+
+	if previous, ok := entry.Data["oops_integration_error"].(string); ok {
+		integrationErrors = append([]string{previous}, integrationErrors...)
+	}
+
+	entry.Data["oops_integration_error"] = strings.Join(integrationErrors, "; ")
+}
@@ -1 +1,114 @@
 package oopslogrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/samber/oops/integrationtest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatterLevelFromSeverity(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.Severity(oops.SeverityWarning).Errorf("permission denied")
+
+	entry := logrus.NewEntry(logrus.New()).WithError(err)
+	entry.Level = logrus.ErrorLevel
+
+	_, e := NewOopsFormatter(&logrus.JSONFormatter{}).Format(entry)
+	is.NoError(e)
+	is.Equal(logrus.ErrorLevel, entry.Level)
+
+	entry = logrus.NewEntry(logrus.New()).WithError(err)
+	entry.Level = logrus.ErrorLevel
+
+	_, e = NewOopsFormatterWithOptions(&logrus.JSONFormatter{}, FormatterOptions{LevelFromSeverity: true}).Format(entry)
+	is.NoError(e)
+	is.Equal(logrus.WarnLevel, entry.Level)
+
+	// errors without severity keep the level of the entry
+	entry = logrus.NewEntry(logrus.New()).WithError(oops.Errorf("permission denied"))
+	entry.Level = logrus.ErrorLevel
+
+	_, e = NewOopsFormatterWithOptions(&logrus.JSONFormatter{}, FormatterOptions{LevelFromSeverity: true}).Format(entry)
+	is.NoError(e)
+	is.Equal(logrus.ErrorLevel, entry.Level)
+}
+
+func TestFormatterStacktraceLevel(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.Errorf("permission denied")
+
+	for _, level := range []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel} {
+		entry := logrus.NewEntry(logrus.New()).WithError(err)
+		entry.Level = level
+
+		_, e := NewOopsFormatter(&logrus.JSONFormatter{}).Format(entry)
+		is.NoError(e)
+		is.Contains(entry.Data, "stacktrace", level.String())
+	}
+
+	for _, level := range []logrus.Level{logrus.WarnLevel, logrus.InfoLevel, logrus.DebugLevel} {
+		entry := logrus.NewEntry(logrus.New()).WithError(err)
+		entry.Level = level
+
+		_, e := NewOopsFormatter(&logrus.JSONFormatter{}).Format(entry)
+		is.NoError(e)
+		is.NotContains(entry.Data, "stacktrace", level.String())
+		is.Equal("permission denied", entry.Data["error"])
+	}
+}
+
+func TestFormatterUnmarshalableEntryData(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		With("channel", make(chan int)).
+		With("callback", func() {}).
+		Errorf("permission denied")
+
+	entry := logrus.NewEntry(logrus.New()).WithError(err).WithField("request_id", "1234")
+	entry.Level = logrus.ErrorLevel
+
+	output, e := NewOopsFormatter(&logrus.JSONFormatter{}).Format(entry)
+	is.NoError(e)
+
+	// the error is logged, without the attributes that cannot be marshaled
+	var payload map[string]any
+	is.NoError(json.Unmarshal(output, &payload))
+	is.Equal("permission denied", payload["error"])
+	is.Equal("1234", payload["request_id"])
+	is.NotContains(payload, "context")
+	is.Contains(payload["oops_integration_error"], "context marshaling: json: unsupported type: ")
+}
+
+func TestConformance(t *testing.T) {
+	levels := map[slog.Level]logrus.Level{
+		slog.LevelDebug: logrus.DebugLevel,
+		slog.LevelInfo:  logrus.InfoLevel,
+		slog.LevelWarn:  logrus.WarnLevel,
+		slog.LevelError: logrus.ErrorLevel,
+	}
+
+	integrationtest.Run(t, func(t testing.TB, err error, level slog.Level) map[string]any {
+		var buf bytes.Buffer
+		logger := logrus.New()
+		logger.SetOutput(&buf)
+		logger.SetLevel(logrus.DebugLevel)
+		logger.SetFormatter(NewOopsFormatter(&logrus.JSONFormatter{}))
+
+		// fields are emitted at the top level of the entry
+		logger.WithError(err).Log(levels[level], "could not fetch user")
+		return integrationtest.Decode(t, buf.Bytes(), "")
+	}, newError)
+}
+
+func newError(builder oops.OopsErrorBuilder) error {
+	return builder.Errorf("permission denied")
+}
@@ -0,0 +1,202 @@
+package oopsslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/samber/oops"
+)
+
+// HandlerOptions configures the attributes emitted for oops errors.
+type HandlerOptions struct {
+	// StacktraceLevel is the minimum level of records including stacktraces and
+	// source fragments. Defaults to slog.LevelError.
+	StacktraceLevel slog.Leveler
+
+	// Rename maps oops attribute keys (`code`, `domain`, `trace`...) to new keys.
+	// An empty key disables the attribute.
+	Rename map[string]string
+
+	// LevelFromSeverity sets the level of records from the severity of the
+	// first oops error found in attributes (eg: a `warning` error logged with
+	// `logger.Error` is emitted as warning). Errors without severity keep the
+	// level of the record.
+	LevelFromSeverity bool
+}
+
+// NewHandler returns a slog.Handler exploding oops errors found in record
+// attributes into structured groups, before passing records to inner.
+//
+//	logger := slog.New(oopsslog.NewHandler(slog.NewJSONHandler(os.Stdout, nil), nil))
+//	logger.Error("could not fetch user", slog.Any("error", err))
+//
+// Errors attached with `logger.With(...)` are exploded when attached: the level
+// of the records is unknown, so their stacktrace is always kept.
+func NewHandler(inner slog.Handler, opts *HandlerOptions) slog.Handler {
+	if opts == nil {
+		opts = &HandlerOptions{}
+	}
+
+	level := slog.Leveler(slog.LevelError)
+	if opts.StacktraceLevel != nil {
+		level = opts.StacktraceLevel
+	}
+
+	return &handler{
+		inner:             inner,
+		stacktraceLevel:   level,
+		rename:            opts.Rename,
+		levelFromSeverity: opts.LevelFromSeverity,
+	}
+}
+
+type handler struct {
+	inner             slog.Handler
+	stacktraceLevel   slog.Leveler
+	rename            map[string]string
+	levelFromSeverity bool
+}
+
+var _ slog.Handler = (*handler)(nil)
+
+// Enabled implements slog.Handler.
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	level := record.Level
+	if h.levelFromSeverity {
+		level = h.levelFromAttrs(record, level)
+		if level != record.Level && !h.inner.Enabled(ctx, level) {
+			return nil
+		}
+	}
+
+	withStacktrace := level >= h.stacktraceLevel.Level()
+
+	output := slog.NewRecord(record.Time, level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		output.AddAttrs(h.transform(attr, withStacktrace))
+		return true
+	})
+
+	return h.inner.Handle(ctx, output)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	transformed := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		transformed = append(transformed, h.transform(attr, true))
+	}
+
+	return &handler{
+		inner:             h.inner.WithAttrs(transformed),
+		stacktraceLevel:   h.stacktraceLevel,
+		rename:            h.rename,
+		levelFromSeverity: h.levelFromSeverity,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{
+		inner:             h.inner.WithGroup(name),
+		stacktraceLevel:   h.stacktraceLevel,
+		rename:            h.rename,
+		levelFromSeverity: h.levelFromSeverity,
+	}
+}
+
+// levelFromAttrs returns the level matching the severity of the first oops
+// error found in the record attributes.
+func (h *handler) levelFromAttrs(record slog.Record, fallback slog.Level) slog.Level {
+	level := fallback
+
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Value.Kind() != slog.KindAny {
+			return true
+		}
+
+		if err, ok := attr.Value.Any().(error); ok {
+			if oopsError, ok := oops.AsOops(err); ok {
+				level = severityToLevel(oopsError.Severity(), fallback)
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return level
+}
+
+func severityToLevel(severity oops.SeverityLevel, fallback slog.Level) slog.Level {
+	switch severity {
+	case oops.SeverityDebug:
+		return slog.LevelDebug
+	case oops.SeverityInfo:
+		return slog.LevelInfo
+	case oops.SeverityWarning:
+		return slog.LevelWarn
+	case oops.SeverityError:
+		return slog.LevelError
+	case oops.SeverityFatal:
+		// slog has no fatal level
+		return slog.LevelError + 4
+	default:
+		return fallback
+	}
+}
+
+func (h *handler) transform(attr slog.Attr, withStacktrace bool) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		attrs := make([]slog.Attr, 0, len(group))
+		for _, a := range group {
+			attrs = append(attrs, h.transform(a, withStacktrace))
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+
+	case slog.KindAny:
+		err, ok := attr.Value.Any().(error)
+		if !ok {
+			return attr
+		}
+
+		oopsError, ok := oops.AsOops(err)
+		if !ok {
+			return attr
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(h.errorAttrs(oopsError, withStacktrace)...)}
+
+	default:
+		return attr
+	}
+}
+
+func (h *handler) errorAttrs(err oops.OopsError, withStacktrace bool) []slog.Attr {
+	group := err.LogValuer().Group()
+	attrs := make([]slog.Attr, 0, len(group))
+
+	for _, attr := range group {
+		if !withStacktrace && (attr.Key == "stacktrace" || attr.Key == "sources") {
+			continue
+		}
+
+		if key, ok := h.rename[attr.Key]; ok {
+			if key == "" {
+				continue
+			}
+			attr.Key = key
+		}
+
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
@@ -0,0 +1,91 @@
+package oopsslog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/samber/oops/integrationtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLogger(level slog.Level, opts *HandlerOptions) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	inner := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level})
+	return slog.New(NewHandler(inner, opts)), &buf
+}
+
+// attributes, stacktrace levels and regular errors are covered by TestConformance
+func TestHandler(t *testing.T) {
+	is := assert.New(t)
+
+	logger, buf := newTestLogger(slog.LevelDebug, nil)
+
+	err := oops.Code("iam_missing_permission").Errorf("permission denied")
+
+	// nested in a group
+	logger.Info("could not fetch user", slog.Group("req", slog.Any("error", err)))
+	errorAttrs := integrationtest.DecodeBuffer(t, buf, "req")["error"].(map[string]any)
+	is.Equal("iam_missing_permission", errorAttrs["code"])
+
+	// attached with logger.With
+	logger.With(slog.Any("error", err)).Info("could not fetch user")
+	errorAttrs = integrationtest.DecodeBuffer(t, buf, "error")
+	is.Equal("iam_missing_permission", errorAttrs["code"])
+}
+
+func TestHandlerOptions(t *testing.T) {
+	is := assert.New(t)
+
+	logger, buf := newTestLogger(slog.LevelDebug, &HandlerOptions{
+		StacktraceLevel: slog.LevelWarn,
+		Rename: map[string]string{
+			"code":  "error_code",
+			"trace": "",
+		},
+	})
+
+	err := oops.Code("iam_missing_permission").Trace("1234").Errorf("permission denied")
+
+	logger.Warn("could not fetch user", slog.Any("error", err))
+	errorAttrs := integrationtest.DecodeBuffer(t, buf, "error")
+	is.Equal("iam_missing_permission", errorAttrs["error_code"])
+	is.NotContains(errorAttrs, "code")
+	is.NotContains(errorAttrs, "trace")
+	is.Contains(errorAttrs, "stacktrace")
+}
+
+func TestHandlerLevelFromSeverity(t *testing.T) {
+	is := assert.New(t)
+
+	logger, buf := newTestLogger(slog.LevelInfo, &HandlerOptions{LevelFromSeverity: true})
+
+	err := oops.Severity(oops.SeverityWarning).Errorf("permission denied")
+
+	logger.Error("could not fetch user", slog.Any("error", err))
+	output := integrationtest.DecodeBuffer(t, buf, "")
+	is.Equal("WARN", output["level"])
+	is.NotContains(output["error"], "stacktrace")
+
+	// downgraded below the level of the inner handler
+	logger.Error("could not fetch user", slog.Any("error", oops.Severity(oops.SeverityDebug).Errorf("permission denied")))
+	is.Empty(buf.String())
+
+	// errors without severity keep the level of the record
+	logger.Error("could not fetch user", slog.Any("error", oops.Errorf("permission denied")))
+	is.Equal("ERROR", integrationtest.DecodeBuffer(t, buf, "")["level"])
+}
+
+func TestConformance(t *testing.T) {
+	integrationtest.Run(t, func(t testing.TB, err error, level slog.Level) map[string]any {
+		logger, buf := newTestLogger(slog.LevelDebug, nil)
+		logger.Log(context.Background(), level, "could not fetch user", slog.Any("error", err))
+		return integrationtest.Decode(t, buf.Bytes(), "error")
+	}, newError)
+}
+
+func newError(builder oops.OopsErrorBuilder) error {
+	return builder.Errorf("permission denied")
+}
@@ -0,0 +1,163 @@
+package oopszap
+
+import (
+	"sort"
+
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewCore wraps a zapcore.Core and expands oops errors logged with `zap.Error(err)`
+// or `zap.NamedError(key, err)` into structured objects. Stacktraces and source
+// fragments are stripped below the error level.
+//
+//	logger := zap.New(oopszap.NewCore(zapcore.NewCore(encoder, os.Stdout, zap.InfoLevel)))
+//	logger.Error("could not fetch user", zap.Error(err))
+func NewCore(inner zapcore.Core) zapcore.Core {
+	return NewCoreWithOptions(inner, CoreOptions{})
+}
+
+// CoreOptions configures the oops core.
+type CoreOptions struct {
+	// LevelFromSeverity sets the level of entries from the severity of the
+	// first oops error found in fields (eg: a `warning` error logged with
+	// `logger.Error` is emitted as warning). Errors without severity keep the
+	// level of the entry.
+	LevelFromSeverity bool
+}
+
+// NewCoreWithOptions is NewCore, with options.
+func NewCoreWithOptions(inner zapcore.Core, opts CoreOptions) zapcore.Core {
+	return &core{
+		Core:              inner,
+		levelFromSeverity: opts.LevelFromSeverity,
+	}
+}
+
+type core struct {
+	zapcore.Core
+	levelFromSeverity bool
+}
+
+var _ zapcore.Core = (*core)(nil)
+
+// With implements zapcore.Core. The level of the entries is unknown, so
+// stacktraces of attached errors are always kept.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		Core:              c.Core.With(transformFields(fields, true)),
+		levelFromSeverity: c.levelFromSeverity,
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+// Write implements zapcore.Core.
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if c.levelFromSeverity {
+		level := levelFromFields(fields, entry.Level)
+		if level != entry.Level && !c.Core.Enabled(level) {
+			return nil
+		}
+		entry.Level = level
+	}
+
+	return c.Core.Write(entry, transformFields(fields, entry.Level >= zapcore.ErrorLevel))
+}
+
+// levelFromFields returns the level matching the severity of the first oops
+// error found in fields.
+func levelFromFields(fields []zapcore.Field, fallback zapcore.Level) zapcore.Level {
+	for _, field := range fields {
+		if field.Type != zapcore.ErrorType {
+			continue
+		}
+
+		if err, ok := field.Interface.(error); ok {
+			if oopsError, ok := oops.AsOops(err); ok {
+				return severityToLevel(oopsError.Severity(), fallback)
+			}
+		}
+	}
+
+	return fallback
+}
+
+func severityToLevel(severity oops.SeverityLevel, fallback zapcore.Level) zapcore.Level {
+	switch severity {
+	case oops.SeverityDebug:
+		return zapcore.DebugLevel
+	case oops.SeverityInfo:
+		return zapcore.InfoLevel
+	case oops.SeverityWarning:
+		return zapcore.WarnLevel
+	case oops.SeverityError:
+		return zapcore.ErrorLevel
+	case oops.SeverityFatal:
+		return zapcore.FatalLevel
+	default:
+		return fallback
+	}
+}
+
+func transformFields(fields []zapcore.Field, withStacktrace bool) []zapcore.Field {
+	output := make([]zapcore.Field, 0, len(fields))
+
+	for _, field := range fields {
+		if field.Type == zapcore.ErrorType {
+			if err, ok := field.Interface.(error); ok {
+				if oopsError, ok := oops.AsOops(err); ok {
+					field = zap.Object(field.Key, oopsMarshaler{err: oopsError, withStacktrace: withStacktrace})
+				}
+			}
+		}
+
+		output = append(output, field)
+	}
+
+	return output
+}
+
+type oopsMarshaler struct {
+	err            oops.OopsError
+	withStacktrace bool
+}
+
+var _ zapcore.ObjectMarshaler = (*oopsMarshaler)(nil)
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (m oopsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	payload := m.err.ToMap()
+
+	if !m.withStacktrace {
+		delete(payload, "stacktrace")
+		delete(payload, "sources")
+	}
+
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		switch v := payload[k].(type) {
+		case string:
+			enc.AddString(k, v)
+		default:
+			if err := enc.AddReflected(k, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
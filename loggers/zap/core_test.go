@@ -0,0 +1,91 @@
+package oopszap
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/samber/oops/integrationtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestLogger(level zapcore.Level, opts CoreOptions) (*zap.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg", LevelKey: "level", EncodeLevel: zapcore.LowercaseLevelEncoder})
+	inner := zapcore.NewCore(encoder, zapcore.AddSync(&buf), level)
+	return zap.New(NewCoreWithOptions(inner, opts)), &buf
+}
+
+// attributes, stacktrace levels and regular errors are covered by TestConformance
+func TestCore(t *testing.T) {
+	is := assert.New(t)
+
+	logger, buf := newTestLogger(zapcore.DebugLevel, CoreOptions{})
+
+	err := oops.Code("iam_missing_permission").Errorf("permission denied")
+
+	logger.Error("could not fetch user", zap.Error(err))
+	is.NotContains(integrationtest.DecodeBuffer(t, buf, ""), "errorVerbose")
+
+	// custom field name, below the error level
+	logger.Warn("could not fetch user", zap.NamedError("cause", err))
+	errorAttrs := integrationtest.DecodeBuffer(t, buf, "cause")
+	is.Equal("iam_missing_permission", errorAttrs["code"])
+	is.NotContains(errorAttrs, "stacktrace")
+
+	// attached with logger.With
+	logger.With(zap.Error(err)).Info("could not fetch user")
+	errorAttrs = integrationtest.DecodeBuffer(t, buf, "error")
+	is.Equal("iam_missing_permission", errorAttrs["code"])
+	is.Contains(errorAttrs, "stacktrace")
+
+	// disabled levels
+	logger = logger.WithOptions(zap.IncreaseLevel(zapcore.ErrorLevel))
+	logger.Info("could not fetch user", zap.Error(err))
+	is.Empty(buf.String())
+}
+
+func TestCoreLevelFromSeverity(t *testing.T) {
+	is := assert.New(t)
+
+	logger, buf := newTestLogger(zapcore.InfoLevel, CoreOptions{LevelFromSeverity: true})
+
+	err := oops.Severity(oops.SeverityWarning).Errorf("permission denied")
+
+	logger.Error("could not fetch user", zap.Error(err))
+	output := integrationtest.DecodeBuffer(t, buf, "")
+	is.Equal("warn", output["level"])
+	is.NotContains(output["error"], "stacktrace")
+
+	// downgraded below the level of the inner core
+	logger.Error("could not fetch user", zap.Error(oops.Severity(oops.SeverityDebug).Errorf("permission denied")))
+	is.Empty(buf.String())
+
+	// errors without severity keep the level of the entry
+	logger.Error("could not fetch user", zap.Error(oops.Errorf("permission denied")))
+	is.Equal("error", integrationtest.DecodeBuffer(t, buf, "")["level"])
+}
+
+func TestConformance(t *testing.T) {
+	levels := map[slog.Level]zapcore.Level{
+		slog.LevelDebug: zapcore.DebugLevel,
+		slog.LevelInfo:  zapcore.InfoLevel,
+		slog.LevelWarn:  zapcore.WarnLevel,
+		slog.LevelError: zapcore.ErrorLevel,
+	}
+
+	integrationtest.Run(t, func(t testing.TB, err error, level slog.Level) map[string]any {
+		logger, buf := newTestLogger(zapcore.DebugLevel, CoreOptions{})
+		if entry := logger.Check(levels[level], "could not fetch user"); entry != nil {
+			entry.Write(zap.Error(err))
+		}
+		return integrationtest.Decode(t, buf.Bytes(), "error")
+	}, newError)
+}
+
+func newError(builder oops.OopsErrorBuilder) error {
+	return builder.Errorf("permission denied")
+}
@@ -0,0 +1,118 @@
+package oopszerolog
+
+import (
+	"context"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/oops"
+)
+
+type contextKey struct{}
+
+const defaultKey = "oops"
+
+// WithError returns a context carrying err. Events attached to this context
+// with `event.Ctx(ctx)` are enriched by `Hook`.
+func WithError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, contextKey{}, err)
+}
+
+func errorFromContext(ctx context.Context) (oops.OopsError, bool) {
+	if ctx == nil {
+		return oops.OopsError{}, false
+	}
+
+	err, ok := ctx.Value(contextKey{}).(error)
+	if !ok {
+		return oops.OopsError{}, false
+	}
+
+	return oops.AsOops(err)
+}
+
+// Hook is a zerolog.Hook enriching events carrying an oops error in their
+// context (see `WithError`), without overriding the global
+// `zerolog.ErrorMarshalFunc`. Stacktraces and source fragments are stripped
+// below the error level.
+//
+// The hook cannot read the fields of an event: an error passed to `.Err(err)`
+// is not enriched, and is kept under `zerolog.ErrorFieldName`, next to the
+// attributes of the error of the context.
+//
+//	logger := zerolog.New(os.Stderr).Hook(oopszerolog.Hook{})
+//	logger.Error().Ctx(oopszerolog.WithError(ctx, err)).Msg("could not fetch user")
+type Hook struct {
+	// Key of the error attributes. Defaults to "oops", so that the attributes
+	// don't collide with `zerolog.ErrorFieldName`, used by `.Err(err)`.
+	Key string
+}
+
+var _ zerolog.Hook = (*Hook)(nil)
+
+// Run implements zerolog.Hook.
+func (h Hook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	err, ok := errorFromContext(e.GetCtx())
+	if !ok {
+		return
+	}
+
+	key := h.Key
+	if key == "" {
+		key = defaultKey
+	}
+
+	payload := err.ToMap()
+
+	if level < zerolog.ErrorLevel {
+		delete(payload, "stacktrace")
+		delete(payload, "sources")
+	}
+
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	dict := zerolog.Dict()
+	for _, k := range keys {
+		switch v := payload[k].(type) {
+		case string:
+			dict = dict.Str(k, v)
+		default:
+			dict = dict.Interface(k, v)
+		}
+	}
+
+	e.Dict(key, dict)
+}
+
+// Level returns the level matching the severity of an oops error, or fallback.
+// A zerolog hook cannot change the level of an event, so the level is picked
+// when the event is created:
+//
+//	logger.WithLevel(oopszerolog.Level(err, zerolog.ErrorLevel)).
+//		Ctx(oopszerolog.WithError(ctx, err)).
+//		Msg("could not fetch user")
+func Level(err error, fallback zerolog.Level) zerolog.Level {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return fallback
+	}
+
+	switch oopsError.Severity() {
+	case oops.SeverityDebug:
+		return zerolog.DebugLevel
+	case oops.SeverityInfo:
+		return zerolog.InfoLevel
+	case oops.SeverityWarning:
+		return zerolog.WarnLevel
+	case oops.SeverityError:
+		return zerolog.ErrorLevel
+	case oops.SeverityFatal:
+		return zerolog.FatalLevel
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,107 @@
+package oopszerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/samber/oops"
+	"github.com/samber/oops/integrationtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// attributes, stacktrace levels and regular errors are covered by TestConformance
+func TestHook(t *testing.T) {
+	is := assert.New(t)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(Hook{})
+
+	ctx := WithError(context.Background(), oops.Code("iam_missing_permission").Errorf("permission denied"))
+
+	logger.Error().Ctx(ctx).Msg("could not fetch user")
+	output := integrationtest.DecodeBuffer(t, &buf, "")
+	is.Equal("could not fetch user", output["message"])
+	is.Contains(output, "oops")
+	is.NotContains(output, "error")
+
+	// context attached to the logger
+	child := logger.With().Ctx(ctx).Logger()
+	child.Info().Msg("could not fetch user")
+	is.Contains(integrationtest.DecodeBuffer(t, &buf, ""), "oops")
+
+	// .Err(err) is kept next to the attributes, without duplicate key
+	logger.Error().Err(errors.New("permission denied")).Ctx(ctx).Send()
+	is.ElementsMatch([]string{"level", "error", "oops"}, topLevelKeys(t, buf.Bytes()))
+	output = integrationtest.DecodeBuffer(t, &buf, "")
+	is.Equal("permission denied", output["error"])
+	is.Equal("iam_missing_permission", output["oops"].(map[string]any)["code"])
+
+	// custom key
+	custom := zerolog.New(&buf).Hook(Hook{Key: "failure"})
+	custom.Error().Ctx(ctx).Send()
+	is.Contains(integrationtest.DecodeBuffer(t, &buf, ""), "failure")
+
+	// no error
+	logger.Error().Msg("could not fetch user")
+	is.NotContains(integrationtest.DecodeBuffer(t, &buf, ""), "oops")
+}
+
+// topLevelKeys returns the keys of a JSON object, including duplicates.
+func topLevelKeys(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keys = append(keys, key.(string))
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return keys
+}
+
+func TestLevel(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(zerolog.WarnLevel, Level(oops.Severity(oops.SeverityWarning).Errorf("permission denied"), zerolog.ErrorLevel))
+	is.Equal(zerolog.ErrorLevel, Level(oops.Errorf("permission denied"), zerolog.ErrorLevel))
+	is.Equal(zerolog.ErrorLevel, Level(errors.New("secret"), zerolog.ErrorLevel))
+}
+
+func TestConformance(t *testing.T) {
+	levels := map[slog.Level]zerolog.Level{
+		slog.LevelDebug: zerolog.DebugLevel,
+		slog.LevelInfo:  zerolog.InfoLevel,
+		slog.LevelWarn:  zerolog.WarnLevel,
+		slog.LevelError: zerolog.ErrorLevel,
+	}
+
+	integrationtest.Run(t, func(t testing.TB, err error, level slog.Level) map[string]any {
+		var buf bytes.Buffer
+		logger := zerolog.New(&buf).Hook(Hook{})
+		logger.WithLevel(levels[level]).Ctx(WithError(context.Background(), err)).Msg("could not fetch user")
+		return integrationtest.Decode(t, buf.Bytes(), "oops")
+	}, newError)
+}
+
+func newError(builder oops.OopsErrorBuilder) error {
+	return builder.Errorf("permission denied")
+}
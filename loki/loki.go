@@ -0,0 +1,188 @@
+package oopsloki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// Client pushes errors to the Loki push API, as JSON log lines.
+type Client struct {
+	// URL of the push API (eg: "http://localhost:3100/loki/api/v1/push").
+	URL string
+	// Labels are added to every stream (eg: {"service": "api"}).
+	Labels map[string]string
+	// TenantID is sent in the `X-Scope-OrgID` header, for multi-tenant Loki.
+	TenantID string
+	// Header is added to the requests (eg: authorization).
+	Header http.Header
+	// LabelsFunc returns the labels of an error. Defaults to DefaultLabels.
+	LabelsFunc func(oops.OopsError) map[string]string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// DefaultClient is used by Push.
+var DefaultClient = &Client{}
+
+// Push sends errors to Loki with DefaultClient.
+//
+//	oopsloki.DefaultClient.URL = "http://localhost:3100/loki/api/v1/push"
+//	oopsloki.DefaultClient.Labels = map[string]string{"service": "api"}
+//
+//	_ = oopsloki.Push(err)
+func Push(errs ...error) error {
+	return DefaultClient.Push(context.Background(), errs...)
+}
+
+// DefaultLabels returns the `domain`, `code`, `severity` and `tags` (sorted,
+// comma separated) labels of an error. Empty labels are omitted. Other attributes
+// are kept in the log line, to keep the cardinality of streams low.
+func DefaultLabels(err oops.OopsError) map[string]string {
+	labels := map[string]string{}
+
+	if domain := err.Domain(); domain != "" {
+		labels["domain"] = domain
+	}
+	if code := err.Code(); code != "" {
+		labels["code"] = code
+	}
+	if severity := err.Severity(); severity != "" {
+		labels["severity"] = string(severity)
+	}
+	if tags := err.Tags(); len(tags) > 0 {
+		tags = append([]string{}, tags...)
+		sort.Strings(tags)
+		labels["tags"] = strings.Join(tags, ",")
+	}
+
+	return labels
+}
+
+// Push sends errors to Loki, grouped into streams by labels. Non-oops errors
+// are pushed with the static labels only.
+func (c *Client) Push(ctx context.Context, errs ...error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(c.streams(errs))
+	if err != nil {
+		return fmt.Errorf("oopsloki: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("oopsloki: %w", err)
+	}
+	for k, values := range c.Header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", c.TenantID)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("oopsloki: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("oopsloki: unexpected status %d: %s", res.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type pushRequest struct {
+	Streams []*stream `json:"streams"`
+}
+
+func (c *Client) streams(errs []error) pushRequest {
+	labelsFunc := c.LabelsFunc
+	if labelsFunc == nil {
+		labelsFunc = DefaultLabels
+	}
+
+	streams := []*stream{}
+	byKey := map[string]*stream{}
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		labels := map[string]string{}
+		ts := time.Now()
+		var line []byte
+
+		if oopsError, ok := oops.AsOops(err); ok {
+			for k, v := range labelsFunc(oopsError) {
+				labels[k] = v
+			}
+			ts = oopsError.Time()
+			line, _ = json.Marshal(oopsError)
+		} else {
+			line, _ = json.Marshal(map[string]any{"error": err.Error()})
+		}
+
+		// static labels win
+		for k, v := range c.Labels {
+			labels[k] = v
+		}
+
+		key := streamKey(labels)
+		s, ok := byKey[key]
+		if !ok {
+			s = &stream{Stream: labels, Values: [][2]string{}}
+			byKey[key] = s
+			streams = append(streams, s)
+		}
+
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(ts.UnixNano(), 10), string(line)})
+	}
+
+	return pushRequest{Streams: streams}
+}
+
+func streamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[k]))
+		b.WriteByte(',')
+	}
+
+	return b.String()
+}
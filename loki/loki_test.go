@@ -0,0 +1,82 @@
+package oopsloki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultLabels(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.In("payment").Code("card_declined").Tags("stripe", "card").Errorf("card declined")
+	is.Equal(map[string]string{"domain": "payment", "code": "card_declined", "tags": "card,stripe"}, DefaultLabels(err.(oops.OopsError)))
+
+	err = oops.Severity(oops.SeverityWarning).Errorf("card declined")
+	is.Equal(map[string]string{"severity": "warning"}, DefaultLabels(err.(oops.OopsError)))
+}
+
+func TestClientPush(t *testing.T) {
+	is := assert.New(t)
+
+	var received struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		is.Equal("tenant-1", r.Header.Get("X-Scope-OrgID"))
+		is.Equal("application/json", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		is.NoError(json.Unmarshal(body, &received))
+
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("no token"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		URL:      server.URL,
+		Labels:   map[string]string{"service": "api"},
+		TenantID: "tenant-1",
+		Header:   http.Header{"Authorization": []string{"Bearer token"}},
+	}
+
+	now := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+	err1 := oops.In("payment").Code("card_declined").Time(now).Trace("1234").Errorf("card declined")
+	err2 := oops.In("payment").Code("card_declined").Time(now).Trace("5678").Errorf("card declined again")
+	err3 := oops.In("auth").Time(now).Errorf("permission denied")
+
+	is.NoError(client.Push(context.Background(), err1, err2, err3, errors.New("boom"), nil))
+	is.Len(received.Streams, 3)
+
+	is.Equal(map[string]string{"service": "api", "domain": "payment", "code": "card_declined"}, received.Streams[0].Stream)
+	is.Len(received.Streams[0].Values, 2)
+	is.Equal("1682985600000000000", received.Streams[0].Values[0][0])
+
+	var line map[string]any
+	is.NoError(json.Unmarshal([]byte(received.Streams[0].Values[0][1]), &line))
+	is.Equal("card declined", line["error"])
+	is.Equal("1234", line["trace"])
+
+	is.Equal(map[string]string{"service": "api", "domain": "auth"}, received.Streams[1].Stream)
+	is.Equal(map[string]string{"service": "api"}, received.Streams[2].Stream)
+	is.Equal(`{"error":"boom"}`, received.Streams[2].Values[0][1])
+
+	client.Header = nil
+	is.EqualError(client.Push(context.Background(), err1), "oopsloki: unexpected status 401: no token")
+}
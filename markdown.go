@@ -0,0 +1,118 @@
+package oops
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ToMarkdown returns a report of the error, with a table of attributes, context
+// tables and a fenced stacktrace, to be pasted into issues and incident documents.
+// Values of redacted keys are not exported.
+func (o OopsError) ToMarkdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n", markdownEscape(o.Error()))
+
+	attributes := [][2]string{}
+	add := func(key string, value string) {
+		if value != "" {
+			attributes = append(attributes, [2]string{key, value})
+		}
+	}
+
+	add("Code", o.Code())
+	add("Severity", string(o.Severity()))
+	if t := o.Time(); !t.IsZero() {
+		add("Time", t.In(Local).Format(time.RFC3339Nano))
+	}
+	if duration := o.Duration(); duration != 0 {
+		add("Duration", duration.String())
+	}
+	add("Domain", o.Domain())
+	add("Tags", strings.Join(o.Tags(), ", "))
+	add("Trace", o.Trace())
+	add("Hint", o.Hint())
+	add("Public", o.Public())
+	add("Owner", o.Owner())
+	if status := o.HTTPStatus(); status != 0 {
+		add("HTTP status", fmt.Sprint(status))
+	}
+	if retryable := o.retryableAttribute(); retryable != nil {
+		add("Retryable", fmt.Sprint(*retryable))
+	}
+	add("Fingerprint", o.Fingerprint())
+
+	writeMarkdownTable(&b, "", "Attribute", attributes)
+
+	if actions := o.Actions(); len(actions) > 0 {
+		b.WriteString("\n### Actions\n\n")
+		for _, action := range actions {
+			if action.Params != nil {
+				fmt.Fprintf(&b, "- `%s`: %s\n", action.Name, markdownEscape(fmt.Sprint(action.Params)))
+			} else {
+				fmt.Fprintf(&b, "- `%s`\n", action.Name)
+			}
+		}
+	}
+
+	payload := o.ToMapWith(ToMapOptions{Include: []string{"context", "user", "tenant"}})
+	writeMarkdownTable(&b, "Context", "Key", markdownRows(payload["context"]))
+	writeMarkdownTable(&b, "User", "Key", markdownRows(payload["user"]))
+	writeMarkdownTable(&b, "Tenant", "Key", markdownRows(payload["tenant"]))
+
+	if stacktrace := o.Stacktrace(); stacktrace != "" {
+		fmt.Fprintf(&b, "\n### Stacktrace\n\n```\n%s\n```\n", stacktrace)
+	}
+
+	if sources := o.Sources(); sources != "" && !SourceFragmentsHidden {
+		fmt.Fprintf(&b, "\n### Sources\n\n```go\n%s\n```\n", sources)
+	}
+
+	return b.String()
+}
+
+func writeMarkdownTable(b *strings.Builder, title string, keyHeader string, rows [][2]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	if title != "" {
+		fmt.Fprintf(b, "\n### %s\n", title)
+	}
+
+	fmt.Fprintf(b, "\n| %s | Value |\n| --- | --- |\n", keyHeader)
+	for _, row := range rows {
+		fmt.Fprintf(b, "| %s | %s |\n", markdownEscape(row[0]), markdownEscape(row[1]))
+	}
+}
+
+func markdownRows(v any) [][2]string {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	rows := make([][2]string, 0, len(m))
+	for k, v := range m {
+		rows = append(rows, [2]string{k, fmt.Sprint(v)})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	return rows
+}
+
+var markdownReplacer = strings.NewReplacer(
+	"|", "\\|",
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// markdownEscape makes a string safe for a heading or a table cell.
+func markdownEscape(s string) string {
+	return markdownReplacer.Replace(s)
+}
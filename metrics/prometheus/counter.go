@@ -0,0 +1,90 @@
+package oopsprometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/oops"
+)
+
+const (
+	EventCreated = "created"
+	EventLogged  = "logged"
+)
+
+// CounterOpts configures the metric names of the collector.
+type CounterOpts struct {
+	Namespace string
+	Subsystem string
+}
+
+// NewCounter returns a prometheus collector counting oops errors:
+//   - `oops_errors_total{event, code, domain}`
+//   - `oops_errors_tags_total{event, tag}`
+//
+// Errors are counted on creation when `counter.Hook` is registered with `oops.OnError`,
+// and when logged by calling `counter.Observe`.
+//
+//	counter := oopsprometheus.NewCounter(oopsprometheus.CounterOpts{Namespace: "myapp"})
+//	prometheus.MustRegister(counter)
+//	oops.OnError(counter.Hook)
+func NewCounter(opts CounterOpts) *Counter {
+	return &Counter{
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "oops_errors_total",
+				Help:      "Number of oops errors, by code and domain.",
+			},
+			[]string{"event", "code", "domain"},
+		),
+		tags: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: opts.Namespace,
+				Subsystem: opts.Subsystem,
+				Name:      "oops_errors_tags_total",
+				Help:      "Number of oops errors, by tag.",
+			},
+			[]string{"event", "tag"},
+		),
+	}
+}
+
+var _ prometheus.Collector = (*Counter)(nil)
+
+type Counter struct {
+	errors *prometheus.CounterVec
+	tags   *prometheus.CounterVec
+}
+
+// Describe implements prometheus.Collector.
+func (c *Counter) Describe(ch chan<- *prometheus.Desc) {
+	c.errors.Describe(ch)
+	c.tags.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Counter) Collect(ch chan<- prometheus.Metric) {
+	c.errors.Collect(ch)
+	c.tags.Collect(ch)
+}
+
+// Hook counts errors on creation. It must be registered with `oops.OnError`.
+func (c *Counter) Hook(err oops.OopsError) oops.OopsError {
+	c.inc(EventCreated, err)
+	return err
+}
+
+// Observe counts logged errors. Non-oops errors are ignored.
+func (c *Counter) Observe(err error) {
+	if oopsErr, ok := oops.AsOops(err); ok {
+		c.inc(EventLogged, oopsErr)
+	}
+}
+
+func (c *Counter) inc(event string, err oops.OopsError) {
+	c.errors.WithLabelValues(event, err.Code(), err.Domain()).Inc()
+
+	for _, tag := range err.Tags() {
+		c.tags.WithLabelValues(event, tag).Inc()
+	}
+}
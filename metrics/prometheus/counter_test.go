@@ -0,0 +1,26 @@
+package oopsprometheus
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	is := assert.New(t)
+
+	counter := NewCounter(CounterOpts{Namespace: "test"})
+
+	err := oops.Code("iam_missing_permission").In("authz").Tags("iam", "authz").Errorf("permission denied")
+	counter.Hook(err.(oops.OopsError))
+	counter.Observe(err)
+	counter.Observe(assert.AnError)
+
+	is.Equal(1.0, testutil.ToFloat64(counter.errors.WithLabelValues(EventCreated, "iam_missing_permission", "authz")))
+	is.Equal(1.0, testutil.ToFloat64(counter.errors.WithLabelValues(EventLogged, "iam_missing_permission", "authz")))
+	is.Equal(1.0, testutil.ToFloat64(counter.tags.WithLabelValues(EventCreated, "iam")))
+	is.Equal(1.0, testutil.ToFloat64(counter.tags.WithLabelValues(EventLogged, "authz")))
+	is.Equal(6, testutil.CollectAndCount(counter))
+}
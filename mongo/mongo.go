@@ -0,0 +1,130 @@
+package oopsmongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/samber/oops"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Classify returns a builder tagged with `mongo`, with the code, the server
+// error code (`mongo_code`) and the retryability derived from a driver error:
+//
+//   - `mongo.ErrNoDocuments` has the `not_found` code and is not retryable
+//   - duplicate key errors have the `duplicate_key` code and are not retryable
+//   - write concern errors have the `write_concern_error` code
+//   - timeouts have the `timeout` code and are retryable
+//   - network errors have the `network_error` code and are retryable
+//
+// Errors labeled by the server as `RetryableWriteError` or `TransientTransactionError`
+// are retryable too.
+func Classify(err error) oops.OopsErrorBuilder {
+	return classify(oops.Tags("mongo"), err)
+}
+
+func classify(builder oops.OopsErrorBuilder, err error) oops.OopsErrorBuilder {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return builder.Code("not_found").Retryable(false)
+	}
+
+	if code, ok := serverCode(err); ok {
+		builder = builder.With("mongo_code", code)
+	}
+
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return builder.Code("duplicate_key").Retryable(false)
+	case mongo.IsTimeout(err):
+		return builder.Code("timeout").Retryable(true)
+	case mongo.IsNetworkError(err):
+		return builder.Code("network_error").Retryable(true)
+	case isWriteConcernError(err):
+		builder = builder.Code("write_concern_error")
+	}
+
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) &&
+		(labeled.HasErrorLabel("RetryableWriteError") || labeled.HasErrorLabel("TransientTransactionError")) {
+		builder = builder.Retryable(true)
+	}
+
+	return builder
+}
+
+// Wrap classifies and wraps a driver error (see Classify), with the collection
+// (`mongo_collection`) and the operation (`mongo_operation`). It returns nil when
+// err is nil.
+//
+//	_, err := invoices.InsertOne(ctx, invoice)
+//	return oopsmongo.Wrap(err, "invoices", "insert")
+func Wrap(err error, collection string, operation string) error {
+	if err == nil {
+		return nil
+	}
+
+	return Classify(err).
+		With("mongo_collection", collection, "mongo_operation", operation).
+		Wrap(err)
+}
+
+// Do runs fn and wraps its error (see Wrap), with the duration of the operation.
+// The builder transported in ctx is reused (see `oops.WithBuilder`).
+//
+//	err := oopsmongo.Do(ctx, "invoices", "find", func() error {
+//		return invoices.FindOne(ctx, bson.M{"_id": id}).Decode(&invoice)
+//	})
+func Do(ctx context.Context, collection string, operation string, fn func() error) error {
+	start := time.Now()
+
+	err := fn()
+	if err == nil {
+		return nil
+	}
+
+	return classify(oops.FromContext(ctx).Tags("mongo"), err).
+		With("mongo_collection", collection, "mongo_operation", operation).
+		Since(start).
+		Wrap(err)
+}
+
+// serverCode returns the code of the first command, write or write concern error.
+func serverCode(err error) (int, bool) {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return int(cmdErr.Code), true
+	}
+
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		if len(writeErr.WriteErrors) > 0 {
+			return writeErr.WriteErrors[0].Code, true
+		}
+		if writeErr.WriteConcernError != nil {
+			return writeErr.WriteConcernError.Code, true
+		}
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		if len(bulkErr.WriteErrors) > 0 {
+			return bulkErr.WriteErrors[0].Code, true
+		}
+		if bulkErr.WriteConcernError != nil {
+			return bulkErr.WriteConcernError.Code, true
+		}
+	}
+
+	return 0, false
+}
+
+func isWriteConcernError(err error) bool {
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) && writeErr.WriteConcernError != nil {
+		return true
+	}
+
+	var bulkErr mongo.BulkWriteException
+	return errors.As(err, &bulkErr) && bulkErr.WriteConcernError != nil
+}
@@ -0,0 +1,81 @@
+package oopsmongo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClassify(t *testing.T) {
+	is := assert.New(t)
+
+	err := Classify(mongo.ErrNoDocuments).Wrap(mongo.ErrNoDocuments).(oops.OopsError)
+	is.Equal("not_found", err.Code())
+	is.Equal([]string{"mongo"}, err.Tags())
+	is.False(err.Retryable())
+
+	duplicate := mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}},
+	}
+	err = Classify(duplicate).Wrap(duplicate).(oops.OopsError)
+	is.Equal("duplicate_key", err.Code())
+	is.Equal(11000, err.Context()["mongo_code"])
+	is.False(err.Retryable())
+
+	writeConcern := mongo.WriteException{
+		WriteConcernError: &mongo.WriteConcernError{Code: 64, Name: "WriteConcernFailed", Message: "waiting for replication timed out"},
+		Labels:            []string{"RetryableWriteError"},
+	}
+	err = Classify(writeConcern).Wrap(writeConcern).(oops.OopsError)
+	is.Equal("write_concern_error", err.Code())
+	is.Equal(64, err.Context()["mongo_code"])
+	is.True(err.Retryable())
+
+	err = Classify(fmt.Errorf("find: %w", context.DeadlineExceeded)).Errorf("could not find invoice").(oops.OopsError)
+	is.Equal("timeout", err.Code())
+	is.True(err.Retryable())
+
+	network := mongo.CommandError{Code: 6, Name: "HostUnreachable", Labels: []string{"NetworkError"}}
+	err = Classify(network).Wrap(network).(oops.OopsError)
+	is.Equal("network_error", err.Code())
+	is.Equal(6, err.Context()["mongo_code"])
+	is.True(err.Retryable())
+
+	err = Classify(mongo.CommandError{Code: 13, Name: "Unauthorized"}).Errorf("could not find invoice").(oops.OopsError)
+	is.Equal("", err.Code())
+	is.Equal(13, err.Context()["mongo_code"])
+}
+
+func TestWrap(t *testing.T) {
+	is := assert.New(t)
+
+	is.NoError(Wrap(nil, "invoices", "find"))
+
+	err := Wrap(mongo.ErrNoDocuments, "invoices", "find").(oops.OopsError)
+	is.EqualError(err, "mongo: no documents in result")
+	is.Equal("not_found", err.Code())
+	is.Equal("invoices", err.Context()["mongo_collection"])
+	is.Equal("find", err.Context()["mongo_operation"])
+}
+
+func TestDo(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := oops.WithBuilder(context.Background(), oops.In("billing"))
+
+	is.NoError(Do(ctx, "invoices", "find", func() error { return nil }))
+
+	err := Do(ctx, "invoices", "find", func() error {
+		time.Sleep(5 * time.Millisecond)
+		return mongo.ErrNoDocuments
+	}).(oops.OopsError)
+	is.Equal("not_found", err.Code())
+	is.Equal("billing", err.Domain())
+	is.Equal("invoices", err.Context()["mongo_collection"])
+	is.GreaterOrEqual(err.Duration(), 5*time.Millisecond)
+}
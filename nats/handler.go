@@ -0,0 +1,128 @@
+package oopsnats
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"github.com/samber/oops"
+)
+
+// TraceHeaders are the message headers of the trace id, by priority.
+// The W3C `traceparent` header is also supported.
+var TraceHeaders = []string{"X-Request-ID", "X-Trace-Id"}
+
+// Handler is a NATS message handler returning an error.
+type Handler func(msg *nats.Msg) error
+
+// Options configures the wrapper.
+type Options struct {
+	// OnError is called with the errors of the handler. Defaults to `slog.Default()`.
+	OnError func(msg *nats.Msg, err error)
+}
+
+// Wrap returns a `nats.MsgHandler` turning the errors and the panics of handler
+// into oops errors carrying the subject, the reply subject, the queue group and
+// the headers of the message (see FromMsg). Errors are logged with `slog.Default()`.
+//
+//	_, err := nc.QueueSubscribe("invoices.created", "billing", oopsnats.Wrap(func(msg *nats.Msg) error {
+//		return process(msg.Data)
+//	}))
+func Wrap(handler Handler) nats.MsgHandler {
+	return WrapWith(handler, Options{})
+}
+
+// WrapWith returns a `nats.MsgHandler` with custom options.
+//
+//	oopsnats.WrapWith(handler, oopsnats.Options{
+//		OnError: func(msg *nats.Msg, err error) {
+//			_ = msg.Nak()
+//			logger.Error(err.Error(), zap.Error(err))
+//		},
+//	})
+func WrapWith(handler Handler, opts Options) nats.MsgHandler {
+	onError := opts.OnError
+	if onError == nil {
+		onError = defaultOnError
+	}
+
+	return func(msg *nats.Msg) {
+		builder := FromMsg(msg)
+
+		var err error
+		recovered := builder.Recoverf(func() {
+			err = handler(msg)
+		}, "nats: panic recovered")
+
+		switch {
+		case recovered != nil:
+			onError(msg, recovered)
+		case err != nil:
+			onError(msg, builder.Wrap(err))
+		}
+	}
+}
+
+// FromMsg returns a builder with the subject (`nats_subject`), the reply subject
+// (`nats_reply`), the queue group (`nats_queue`) and the headers (`nats_headers`)
+// of a message. The trace id is extracted from the headers (see TraceHeaders).
+// Values of sensitive headers (see `oops.SensitiveHeaders`) are redacted.
+func FromMsg(msg *nats.Msg) oops.OopsErrorBuilder {
+	builder := oops.With("nats_subject", msg.Subject)
+
+	if msg.Reply != "" {
+		builder = builder.With("nats_reply", msg.Reply)
+	}
+
+	if msg.Sub != nil && msg.Sub.Queue != "" {
+		builder = builder.With("nats_queue", msg.Sub.Queue)
+	}
+
+	if len(msg.Header) > 0 {
+		builder = builder.With("nats_headers", headers(msg.Header))
+	}
+
+	if trace := traceFromHeader(msg.Header); trace != "" {
+		builder = builder.Trace(trace)
+	}
+
+	return builder
+}
+
+func headers(header nats.Header) map[string]any {
+	output := map[string]any{}
+
+	for k, values := range header {
+		value := strings.Join(values, ", ")
+
+		for _, sensitive := range oops.SensitiveHeaders {
+			if strings.EqualFold(k, sensitive) {
+				value = "[REDACTED]"
+				break
+			}
+		}
+
+		output[k] = value
+	}
+
+	return output
+}
+
+func traceFromHeader(header nats.Header) string {
+	for _, key := range TraceHeaders {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+
+	// version-traceid-parentid-flags
+	if parts := strings.Split(header.Get("traceparent"), "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+
+	return ""
+}
+
+func defaultOnError(msg *nats.Msg, err error) {
+	slog.Default().Error(err.Error(), slog.Any("error", err))
+}
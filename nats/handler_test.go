@@ -0,0 +1,73 @@
+package oopsnats
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMsg(t *testing.T) {
+	is := assert.New(t)
+
+	msg := &nats.Msg{
+		Subject: "invoices.created",
+		Reply:   "_INBOX.1234",
+		Sub:     &nats.Subscription{Queue: "billing"},
+		Header: nats.Header{
+			"X-Request-ID":  []string{"1234"},
+			"Authorization": []string{"Bearer secret"},
+		},
+	}
+
+	err := FromMsg(msg).Errorf("invoice not found").(oops.OopsError)
+	is.Equal("1234", err.Trace())
+	is.Equal(map[string]any{
+		"nats_subject": "invoices.created",
+		"nats_reply":   "_INBOX.1234",
+		"nats_queue":   "billing",
+		"nats_headers": map[string]any{"X-Request-ID": "1234", "Authorization": "[REDACTED]"},
+	}, err.Context())
+
+	msg = &nats.Msg{
+		Subject: "invoices.created",
+		Header:  nats.Header{"traceparent": []string{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+	}
+	err = FromMsg(msg).Errorf("invoice not found").(oops.OopsError)
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", err.Trace())
+	is.NotContains(err.Context(), "nats_reply")
+	is.NotContains(err.Context(), "nats_queue")
+}
+
+func TestWrap(t *testing.T) {
+	is := assert.New(t)
+
+	var handled error
+	opts := Options{
+		OnError: func(msg *nats.Msg, err error) {
+			handled = err
+		},
+	}
+	msg := &nats.Msg{Subject: "invoices.created", Header: nats.Header{"X-Request-ID": []string{"1234"}}}
+
+	WrapWith(func(msg *nats.Msg) error {
+		return errors.New("invalid payload")
+	}, opts)(msg)
+	is.EqualError(handled, "invalid payload")
+	is.Equal("1234", oops.GetTrace(handled))
+	is.Equal("invoices.created", handled.(oops.OopsError).Context()["nats_subject"])
+
+	WrapWith(func(msg *nats.Msg) error {
+		panic("boom")
+	}, opts)(msg)
+	is.EqualError(handled, "nats: panic recovered: boom")
+	is.Equal("1234", oops.GetTrace(handled))
+
+	handled = nil
+	WrapWith(func(msg *nats.Msg) error {
+		return nil
+	}, opts)(msg)
+	is.Nil(handled)
+}
@@ -30,13 +30,55 @@ func Errorf(format string, args ...any) error {
 	return new().Errorf(format, args...)
 }
 
+// WrapContext wraps an error into an `oops.OopsError` object that satisfies `error`.
+// The builder transported in the context is reused (see WithBuilder), and trace and
+// span are extracted from the OpenTelemetry span found in the context.
+func WrapContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return FromContext(ctx).WrapContext(ctx, err)
+}
+
+// WrapfContext wraps an error into an `oops.OopsError` object that satisfies `error` and formats an error message.
+// The builder transported in the context is reused (see WithBuilder), and trace and
+// span are extracted from the OpenTelemetry span found in the context.
+func WrapfContext(ctx context.Context, err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+
+	return FromContext(ctx).WrapfContext(ctx, err, format, args...)
+}
+
+// ErrorfContext formats an error and returns `oops.OopsError` object that satisfies `error`.
+// The builder transported in the context is reused (see WithBuilder), and trace and
+// span are extracted from the OpenTelemetry span found in the context.
+func ErrorfContext(ctx context.Context, format string, args ...any) error {
+	return FromContext(ctx).ErrorfContext(ctx, format, args...)
+}
+
+// FromContext returns the builder transported in the context (see WithBuilder),
+// or a new builder. Registered context extractors are applied, and the builder is
+// enriched with the OpenTelemetry trace and span ids, the pprof labels (see
+// CaptureGoroutineInfo) and the deadline (see EmitContextDeadline) of the context,
+// when it does not declare them.
 func FromContext(ctx context.Context) OopsErrorBuilder {
 	builder, ok := getBuilderFromContext(ctx)
 	if !ok {
-		new()
+		builder = new()
 	}
 
-	return builder
+	return applyContextExtractors(ctx, enrichFromContext(ctx, builder))
+}
+
+// FromCode returns a builder with the given code, pre-filled by the registered
+// code resolvers (see the `catalog` sub-package).
+//
+//	oops.FromCode("billing.invoice_not_found").Wrap(err)
+func FromCode(code string) OopsErrorBuilder {
+	return applyCodeResolvers(code, new().Code(code))
 }
 
 func Join(e ...error) error {
@@ -74,8 +116,13 @@ func Code(code string) OopsErrorBuilder {
 	return new().Code(code)
 }
 
+// Severity set how critical the error is.
+func Severity(severity SeverityLevel) OopsErrorBuilder {
+	return new().Severity(severity)
+}
+
 // Time set the error time.
-// Default: `time.Now()`
+// Default: `time.Now()`, see SetClock
 func Time(time time.Time) OopsErrorBuilder {
 	return new().Time(time)
 }
@@ -115,6 +162,12 @@ func With(kv ...any) OopsErrorBuilder {
 	return new().With(kv...)
 }
 
+// WithGroup supplies a list of attributes declared by pair of key+value, nested
+// under a group. Groups are rendered as nested objects in JSON and as nested slog groups.
+func WithGroup(name string, kv ...any) OopsErrorBuilder {
+	return new().WithGroup(name, kv...)
+}
+
 // With supplies a list of attributes declared by pair of key+value.
 func WithContext(ctx context.Context, keys ...any) OopsErrorBuilder {
 	return new().WithContext(ctx, keys...)
@@ -136,6 +189,34 @@ func Owner(owner string) OopsErrorBuilder {
 	return new().Owner(owner)
 }
 
+// HTTPStatus sets the http status code to respond with (eg: 404).
+func HTTPStatus(status int) OopsErrorBuilder {
+	return new().HTTPStatus(status)
+}
+
+// Retryable marks the error as retryable or not, for job queues and workflow
+// engines. Errors are retryable by default.
+func Retryable(retryable bool) OopsErrorBuilder {
+	return new().Retryable(retryable)
+}
+
+// Fingerprint overrides the key used for grouping similar errors.
+func Fingerprint(fingerprint string) OopsErrorBuilder {
+	return new().Fingerprint(fingerprint)
+}
+
+// RedactKeys hides the values of the given context, user and tenant keys (case insensitive)
+// in every output. Global keys are declared in `oops.RedactedKeys`.
+func RedactKeys(keys ...string) OopsErrorBuilder {
+	return new().RedactKeys(keys...)
+}
+
+// Action adds a machine-readable remediation action (eg: "rotate_credentials"),
+// for automated remediation systems.
+func Action(name string, params map[string]any) OopsErrorBuilder {
+	return new().Action(name, params)
+}
+
 // User supplies user id and a chain of key/value.
 func User(userID string, data map[string]any) OopsErrorBuilder {
 	return new().User(userID, data)
@@ -146,14 +227,66 @@ func Tenant(tenantID string, data map[string]any) OopsErrorBuilder {
 	return new().Tenant(tenantID, data)
 }
 
-// Request supplies a http.Request.
-func Request(req *http.Request, withBody bool) OopsErrorBuilder {
-	return new().Request(req, withBody)
+// Request supplies a http.Request. An optional max body size (in bytes) overrides
+// `oops.MaxBodySize` for this request.
+func Request(req *http.Request, withBody bool, maxBodySize ...int) OopsErrorBuilder {
+	return new().Request(req, withBody, maxBodySize...)
+}
+
+// Response supplies a http.Response. An optional max body size (in bytes) overrides
+// `oops.MaxBodySize` for this response. The body is buffered and restored, so that
+// it can still be read by the caller.
+func Response(res *http.Response, withBody bool, maxBodySize ...int) OopsErrorBuilder {
+	return new().Response(res, withBody, maxBodySize...)
+}
+
+// Resolution sets the strategy used to resolve attributes declared by many errors
+// of a chain. Default: `oops.AttributeResolution`.
+func Resolution(strategy ResolutionStrategy) OopsErrorBuilder {
+	return new().Resolution(strategy)
+}
+
+// WithStackDepth sets the max depth of the stack trace captured for this error.
+// Default: `oops.StackTraceMaxDepth`.
+func WithStackDepth(depth int) OopsErrorBuilder {
+	return new().WithStackDepth(depth)
+}
+
+// Caller captures only the immediate call site (file, line and function) instead
+// of a full stack trace, for high-volume errors.
+func Caller() OopsErrorBuilder {
+	return new().Caller()
+}
+
+// SkipFrames hides the n innermost frames of the captured stack trace, so that
+// helpers built on top of oops do not appear in it.
+func SkipFrames(n int) OopsErrorBuilder {
+	return new().SkipFrames(n)
+}
+
+// WithoutStacktrace disables stack trace capture, for hot paths.
+func WithoutStacktrace() OopsErrorBuilder {
+	return new().WithoutStacktrace()
 }
 
-// Response supplies a http.Response.
-func Response(res *http.Response, withBody bool) OopsErrorBuilder {
-	return new().Response(res, withBody)
+// WithAllGoroutines snapshots the stacks of all goroutines in addition to the
+// current stack trace, for deadlock or timeout errors.
+func WithAllGoroutines() OopsErrorBuilder {
+	return new().WithAllGoroutines()
+}
+
+// LinkStack captures the current stack on the builder. Errors built later from
+// this builder, possibly in another goroutine, print these frames beneath their
+// own stack trace.
+func LinkStack() OopsErrorBuilder {
+	return new().LinkStack()
+}
+
+// ForeignFrames appends frames captured outside of the Go runtime (cgo, FFI,
+// remote service...) beneath the Go frames of the stack trace. The origin is
+// printed next to each frame (eg: "sqlite", "payment-service").
+func ForeignFrames(origin string, frames ...ForeignFrame) OopsErrorBuilder {
+	return new().ForeignFrames(origin, frames...)
 }
 
 // GetPublic returns a message that is safe to show to an end user, or a default generic message.
@@ -169,3 +302,48 @@ func GetPublic(err error, defaultPublicMessage string) string {
 
 	return defaultPublicMessage
 }
+
+// GetCode returns the error code, or an empty string if err is not an `oops.OopsError`.
+func GetCode(err error) string {
+	if oopsError, ok := AsOops(err); ok {
+		return oopsError.Code()
+	}
+
+	return ""
+}
+
+// GetDomain returns the error domain, or an empty string if err is not an `oops.OopsError`.
+func GetDomain(err error) string {
+	if oopsError, ok := AsOops(err); ok {
+		return oopsError.Domain()
+	}
+
+	return ""
+}
+
+// GetTags returns the error tags, or nil if err is not an `oops.OopsError`.
+func GetTags(err error) []string {
+	if oopsError, ok := AsOops(err); ok {
+		return oopsError.Tags()
+	}
+
+	return nil
+}
+
+// GetOwner returns the error owner, or an empty string if err is not an `oops.OopsError`.
+func GetOwner(err error) string {
+	if oopsError, ok := AsOops(err); ok {
+		return oopsError.Owner()
+	}
+
+	return ""
+}
+
+// GetTrace returns the error trace id, or an empty string if err is not an `oops.OopsError`.
+func GetTrace(err error) string {
+	if oopsError, ok := AsOops(err); ok {
+		return oopsError.Trace()
+	}
+
+	return ""
+}
@@ -1,6 +1,7 @@
 package oops
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -176,6 +177,28 @@ func TestOopsWith(t *testing.T) {
 	is.Equal(map[string]any{"user_id": 1234, "foo": "bar"}, err.(OopsError).context)
 }
 
+func TestOopsBuilderCopyOnWrite(t *testing.T) {
+	is := assert.New(t)
+
+	parent := new().Tags("iam").With("user_id", 1234).User("user-123", "email", "foo@bar.com").Tenant("tenant-123", "plan", "free")
+
+	child1 := parent.Tags("authz").With("foo", "bar").User("user-456", "role", "admin").Tenant("tenant-456", "plan", "enterprise")
+	child2 := parent.Tags("authn").WithGroup("db", "query", "SELECT 1").WithContext(context.Background(), "request_id")
+
+	is.Equal([]string{"iam"}, parent.tags)
+	is.Equal(map[string]any{"user_id": 1234}, parent.context)
+	is.Equal(map[string]any{"email": "foo@bar.com"}, parent.userData)
+	is.Equal(map[string]any{"plan": "free"}, parent.tenantData)
+
+	is.Equal([]string{"iam", "authz"}, child1.tags)
+	is.Equal(map[string]any{"user_id": 1234, "foo": "bar"}, child1.context)
+	is.Equal(map[string]any{"email": "foo@bar.com", "role": "admin"}, child1.userData)
+	is.Equal(map[string]any{"plan": "enterprise"}, child1.tenantData)
+
+	is.Equal([]string{"iam", "authn"}, child2.tags)
+	is.Equal(map[string]any{"user_id": 1234, "db": map[string]any{"query": "SELECT 1"}, "request_id": nil}, child2.context)
+}
+
 func TestOopsWithContext(t *testing.T) {
 	is := assert.New(t)
 
@@ -375,7 +398,7 @@ func TestOopsMixed(t *testing.T) {
 	is.Equal(err.(OopsError).userData, map[string]any{"firstname": "john", "lastname": "doe"})
 	is.Equal(err.(OopsError).tenantID, "workspace-123")
 	is.Equal(err.(OopsError).tenantData, map[string]any{"name": "little project"})
-	is.Equal(err.(OopsError).req, lo.ToPtr(lo.T2(req, false)))
+	is.Equal(err.(OopsError).req, lo.ToPtr(lo.T3(req, false, 0)))
 	is.Equal(err.(OopsError).err, assert.AnError)
 	is.Equal(err.(OopsError).msg, "a message 42")
 }
@@ -448,7 +471,7 @@ func TestOopsMixedWithGetters(t *testing.T) {
 	is.Equal(err.(OopsError).userData, map[string]any{"email": "john@doe.org", "firstname": "john", "lastname": "doe"})
 	is.Equal(err.(OopsError).tenantID, "workspace-123")
 	is.Equal(err.(OopsError).tenantData, map[string]any{"deleted": false, "name": "little project"})
-	is.Equal(err.(OopsError).req, lo.ToPtr(lo.T2(req2, true)))
+	is.Equal(err.(OopsError).req, lo.ToPtr(lo.T3(req2, true, 0)))
 	is.Equal(err.(OopsError).err.Error(), "a message 42: assert.AnError general error for testing")
 	is.Equal(err.(OopsError).msg, "hello world")
 
@@ -466,7 +489,7 @@ func TestOopsMixedWithGetters(t *testing.T) {
 	is.Equal(err.(OopsError).Unwrap().(OopsError).userData, map[string]any{"firstname": "bob", "lastname": "martin"})
 	is.Equal(err.(OopsError).Unwrap().(OopsError).tenantID, "workspace-123")
 	is.Equal(err.(OopsError).Unwrap().(OopsError).tenantData, map[string]any{"name": "little project"})
-	is.Equal(err.(OopsError).Unwrap().(OopsError).req, lo.ToPtr(lo.T2(req1, true)))
+	is.Equal(err.(OopsError).Unwrap().(OopsError).req, lo.ToPtr(lo.T3(req1, true, 0)))
 	is.Equal(err.(OopsError).Unwrap().(OopsError).err.Error(), assert.AnError.Error())
 	is.Equal(err.(OopsError).Unwrap().(OopsError).msg, "a message 42")
 }
@@ -534,6 +557,44 @@ func TestOopsLogValuer(t *testing.T) {
 	}
 }
 
+func TestOopsLogValuerWith(t *testing.T) {
+	is := assert.New(t)
+
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader("hello world"))
+
+	err := new().
+		Code("iam_missing_permission").
+		Trace("1234").
+		Span("5678").
+		With("user_id", 1234).
+		Request(req, true).
+		WithoutStacktrace().
+		Errorf("permission denied").(OopsError)
+
+	keys := func(attrs []slog.Attr) []string {
+		return lo.Map(attrs, func(attr slog.Attr, _ int) string { return attr.Key })
+	}
+
+	is.Equal(
+		[]string{"message", "err", "code", "time", "trace", "context", "request"},
+		keys(err.LogValuerWith(LogValuerOptions{}).Group()),
+	)
+
+	got := err.LogValuerWith(LogValuerOptions{
+		Rename:      map[string]string{"code": "error.code", "message": ""},
+		FlatContext: true,
+		OmitDumps:   true,
+		EmitSpan:    true,
+	}).Group()
+	is.Equal(
+		[]string{"err", "error.code", "time", "trace", "span", "user_id"},
+		keys(got),
+	)
+	is.Equal("iam_missing_permission", got[1].Value.String())
+	is.Equal("5678", got[4].Value.String())
+	is.EqualValues(1234, got[5].Value.Any())
+}
+
 func TestOopsFormatSummary(t *testing.T) {
 	is := assert.New(t)
 
@@ -611,6 +672,67 @@ Request:
 	is.Equal(expected, got)
 }
 
+func TestOopsFormatWith(t *testing.T) {
+	is := assert.New(t)
+
+	now, _ := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", "2023-05-02 05:26:48.570837 +0000 UTC")
+
+	err := new().
+		Code("iam_missing_permission").
+		Time(now).
+		Trace("1234").
+		WithoutStacktrace().
+		Errorf("permission denied").(OopsError)
+
+	is.Equal(fmt.Sprintf("%+v", err), err.FormatWith(FormatterOptions{}))
+	is.Equal(
+		"\033[36mOops\033[0m: \033[1m\033[31mpermission denied\033[0m\n\033[36mCode\033[0m: \033[1m\033[33miam_missing_permission\033[0m\n\033[36mTime\033[0m: 2023-05-02 05:26:48.570837 +0000 UTC\n\033[36mTrace\033[0m: 1234\n",
+		err.FormatWith(FormatterOptions{Color: true}),
+	)
+
+	p := palette(true)
+	is.Equal(
+		"Oops: permission denied\n41\tfoo()\n\033[1m\033[31m42\treturn oops.Errorf(\"permission denied\")\033[0m\n\033[31m\t       ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\033[0m\n43\t}",
+		p.sources("Oops: permission denied\n41\tfoo()\n42\treturn oops.Errorf(\"permission denied\")\n\t       ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n43\t}"),
+	)
+}
+
+func TestOopsWriteTo(t *testing.T) {
+	is := assert.New(t)
+
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader("hello world"))
+	err := new().
+		Code("iam_missing_permission").
+		Trace("1234").
+		Request(req, true).
+		With("user_id", 1234).
+		Errorf("permission denied").(OopsError)
+
+	var buf bytes.Buffer
+	n, writeErr := err.WriteTo(&buf)
+	is.NoError(writeErr)
+	is.Equal(int64(buf.Len()), n)
+	is.Equal(fmt.Sprintf("%+v", err), buf.String())
+	is.Contains(buf.String(), "Request:\n  * POST /foobar HTTP/1.1\r\n")
+	is.Contains(buf.String(), "\n  * hello world\n")
+
+	buf.Reset()
+	_, writeErr = err.WriteWith(&buf, FormatterOptions{Color: true})
+	is.NoError(writeErr)
+	is.Equal(err.FormatWith(FormatterOptions{Color: true}), buf.String())
+
+	// the first write error stops the output
+	n, writeErr = err.WriteTo(errWriter{})
+	is.ErrorIs(writeErr, assert.AnError)
+	is.Zero(n)
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, assert.AnError
+}
+
 func TestOopsMarshalJSON(t *testing.T) {
 	is := assert.New(t)
 
@@ -638,6 +760,20 @@ func TestOopsMarshalJSON(t *testing.T) {
 	is.Equal(expected, string(got))
 }
 
+func TestOopsWriteJSON(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().Code("iam_missing_permission").Trace("1234").With("user_id", 1234).Errorf("permission denied").(OopsError)
+
+	var buf bytes.Buffer
+	is.NoError(err.WriteJSON(&buf))
+
+	expected, _ := json.Marshal(err)
+	is.Equal(string(expected)+"\n", buf.String())
+
+	is.ErrorIs(err.WriteJSON(errWriter{}), assert.AnError)
+}
+
 func TestOopsGetPublic(t *testing.T) {
 	is := assert.New(t)
 
@@ -647,3 +783,630 @@ func TestOopsGetPublic(t *testing.T) {
 	is.Equal("public facing message", GetPublic(err, "default message"))
 	is.Equal("default message", GetPublic(assert.AnError, "default message"))
 }
+
+func TestOopsGetters(t *testing.T) {
+	is := assert.New(t)
+
+	err := fmt.Errorf("wrapped: %w", new().Code("iam_missing_permission").In("authz").Tags("iam").Owner("authz-team@acme.org").Trace("1234").Wrap(assert.AnError))
+	is.Equal("iam_missing_permission", GetCode(err))
+	is.Equal("authz", GetDomain(err))
+	is.Equal([]string{"iam"}, GetTags(err))
+	is.Equal("authz-team@acme.org", GetOwner(err))
+	is.Equal("1234", GetTrace(err))
+
+	is.Empty(GetCode(assert.AnError))
+	is.Empty(GetDomain(assert.AnError))
+	is.Nil(GetTags(assert.AnError))
+	is.Empty(GetOwner(assert.AnError))
+	is.Empty(GetTrace(assert.AnError))
+	is.Empty(GetCode(nil))
+}
+
+func TestOopsShallowGetters(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Now()
+
+	err := new().
+		Code("iam_authz_missing_permission").
+		Time(now).
+		Duration(time.Second).
+		In("authz").
+		Hint("Runbook: https://doc.acme.org/doc/1234.md").
+		Public("public facing message").
+		Owner("authz-team@acme.org").
+		Wrapf(assert.AnError, "a message %d", 42)
+
+	err = new().
+		Time(now.Add(time.Hour)).
+		In("iam").
+		Hint("Runbook: https://doc.acme.org/doc/abcd.md").
+		Wrapf(err, "hello world")
+
+	is.Equal("iam_authz_missing_permission", err.(OopsError).ShallowCode())
+	is.Equal(now.Add(time.Hour), err.(OopsError).ShallowTime())
+	is.Equal(time.Second, err.(OopsError).ShallowDuration())
+	is.Equal("iam", err.(OopsError).ShallowDomain())
+	is.Equal("Runbook: https://doc.acme.org/doc/abcd.md", err.(OopsError).ShallowHint())
+	is.Equal("public facing message", err.(OopsError).ShallowPublic())
+	is.Equal("authz-team@acme.org", err.(OopsError).ShallowOwner())
+
+	// deepest getters are unchanged
+	is.Equal("authz", err.(OopsError).Domain())
+	is.Equal("Runbook: https://doc.acme.org/doc/1234.md", err.(OopsError).Hint())
+
+	is.Empty(new().Wrap(assert.AnError).(OopsError).ShallowCode())
+}
+
+func TestOopsResolution(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { AttributeResolution = ResolutionDeepestFirst }()
+
+	err := new().
+		Code("iam_authz_missing_permission").
+		In("authz").
+		With("foo", "bar", "user_id", 1234).
+		Wrap(assert.AnError)
+	err = new().
+		Code("iam_unknown_error").
+		With("foo", "baz").
+		Wrap(err)
+
+	is.Equal("iam_authz_missing_permission", err.(OopsError).Code())
+	is.Equal("authz", err.(OopsError).Domain())
+	is.Equal(map[string]any{"foo": "bar", "user_id": 1234}, err.(OopsError).Context())
+
+	AttributeResolution = ResolutionShallowFirst
+	is.Equal("iam_unknown_error", err.(OopsError).Code())
+	is.Equal("authz", err.(OopsError).Domain())
+	is.Equal(map[string]any{"foo": "baz", "user_id": 1234}, err.(OopsError).Context())
+
+	// per builder
+	AttributeResolution = ResolutionDeepestFirst
+	err = new().Resolution(ResolutionShallowFirst).Code("api_unknown_error").Wrap(err)
+	is.Equal("api_unknown_error", err.(OopsError).Code())
+	is.Equal(map[string]any{"foo": "baz", "user_id": 1234}, err.(OopsError).Context())
+}
+
+func TestOopsAction(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().
+		Action("rotate_credentials", map[string]any{"key_id": "key-123"}).
+		Wrap(assert.AnError)
+	err = new().
+		Action("page_oncall", nil).
+		Wrap(err)
+
+	is.Equal([]RemediationAction{{Name: "page_oncall"}}, err.(OopsError).actions)
+	is.Equal(
+		[]RemediationAction{
+			{Name: "page_oncall"},
+			{Name: "rotate_credentials", Params: map[string]any{"key_id": "key-123"}},
+		},
+		err.(OopsError).Actions(),
+	)
+
+	got, jsonErr := json.Marshal(err.(OopsError).ToMap()["actions"])
+	is.NoError(jsonErr)
+	is.Equal(`[{"name":"page_oncall"},{"name":"rotate_credentials","params":{"key_id":"key-123"}}]`, string(got))
+
+	is.Empty(new().Wrap(assert.AnError).(OopsError).Actions())
+}
+
+func TestOopsFingerprint(t *testing.T) {
+	is := assert.New(t)
+
+	errs := []error{}
+	for i := 0; i < 2; i++ {
+		errs = append(errs, new().Code("not_found").In("db").Errorf("user %d not found", i))
+	}
+
+	is.Len(errs[0].(OopsError).Fingerprint(), 32)
+	is.Equal(errs[0].(OopsError).Fingerprint(), errs[1].(OopsError).Fingerprint())
+	is.Equal(errs[0].(OopsError).Fingerprint(), new().Wrap(errs[0]).(OopsError).Fingerprint())
+	is.NotEqual(errs[0].(OopsError).Fingerprint(), new().Code("not_found").In("cache").Errorf("user %d not found", 0).(OopsError).Fingerprint())
+	is.NotEqual(errs[0].(OopsError).Fingerprint(), new().Code("not_found").In("db").Errorf("group %d not found", 0).(OopsError).Fingerprint())
+
+	err := new().Fingerprint("custom").Wrap(errs[0])
+	is.Equal("custom", err.(OopsError).Fingerprint())
+	is.Equal("custom", new().Wrap(err).(OopsError).Fingerprint())
+	is.NotContains(err.(OopsError).ToMap(), "fingerprint")
+
+	EmitFingerprint = true
+	defer func() { EmitFingerprint = false }()
+	is.Equal("custom", err.(OopsError).ToMap()["fingerprint"])
+}
+
+func TestOopsRetryable(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().Errorf("timeout")
+	is.True(err.(OopsError).Retryable())
+	is.NotContains(err.(OopsError).ToMap(), "retryable")
+
+	err = new().Retryable(false).Errorf("invalid payload")
+	is.False(err.(OopsError).Retryable())
+	is.False(new().Wrap(err).(OopsError).Retryable())
+	is.Equal(false, err.(OopsError).ToMap()["retryable"])
+
+	b, e := err.(OopsError).MarshalBinary()
+	is.NoError(e)
+	var decoded OopsError
+	is.NoError(decoded.UnmarshalBinary(b))
+	is.False(decoded.Retryable())
+}
+
+func TestOopsChild(t *testing.T) {
+	is := assert.New(t)
+
+	parent := new().In("batch").With("job_id", 42, "foo", "bar")
+
+	child1 := parent.Child("item_id", 1)
+	child2 := parent.Child("item_id", 2, "foo", "baz")
+	grandchild := child1.Child("attempt", 3).Code("item_failed")
+
+	err := child1.Wrap(assert.AnError)
+	is.Equal("batch", err.(OopsError).Domain())
+	is.Equal(map[string]any{"job_id": 42, "foo": "bar", "item_id": 1}, err.(OopsError).Context())
+
+	err = child2.Wrap(assert.AnError)
+	is.Equal(map[string]any{"job_id": 42, "foo": "baz", "item_id": 2}, err.(OopsError).Context())
+
+	err = grandchild.Wrap(assert.AnError)
+	is.Equal("item_failed", err.(OopsError).Code())
+	is.Equal(map[string]any{"job_id": 42, "foo": "bar", "item_id": 1, "attempt": 3}, err.(OopsError).Context())
+
+	// parent is not altered
+	err = parent.Wrap(assert.AnError)
+	is.Equal(map[string]any{"job_id": 42, "foo": "bar"}, err.(OopsError).Context())
+	is.Equal(map[string]any{"job_id": 42, "foo": "bar"}, parent.context)
+}
+
+func TestOopsSeverity(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().Severity(SeverityWarning).Wrap(assert.AnError)
+	is.Error(err)
+	is.Equal(SeverityWarning, err.(OopsError).severity)
+	is.Equal(SeverityWarning, err.(OopsError).Severity())
+	is.Equal("warning", err.(OopsError).ToMap()["severity"])
+
+	err = new().Wrap(err)
+	is.Equal(SeverityWarning, err.(OopsError).Severity())
+}
+
+func TestOopsWrapContext(t *testing.T) {
+	is := assert.New(t)
+
+	traceId, terr := trace.TraceIDFromHex("12345678901234567890123456789012")
+	is.NoError(terr)
+	spanId, serr := trace.SpanIDFromHex("1234567890123456")
+	is.NoError(serr)
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceId,
+		SpanID:  spanId,
+	}))
+	ctx = WithBuilder(ctx, new().In("authz"))
+
+	err := WrapContext(ctx, assert.AnError)
+	is.Error(err)
+	is.Equal(assert.AnError, err.(OopsError).err)
+	is.Equal("authz", err.(OopsError).domain)
+	is.Equal("12345678901234567890123456789012", err.(OopsError).trace)
+	is.Equal("1234567890123456", err.(OopsError).span)
+
+	err = WrapfContext(ctx, assert.AnError, "a message %d", 42)
+	is.Error(err)
+	is.Equal("a message 42", err.(OopsError).msg)
+	is.Equal("12345678901234567890123456789012", err.(OopsError).trace)
+
+	err = new().Code("iam_missing_permission").ErrorfContext(ctx, "a message %d", 42)
+	is.Error(err)
+	is.Equal("a message 42", err.Error())
+	is.Equal("iam_missing_permission", err.(OopsError).code)
+	is.Empty(err.(OopsError).domain)
+	is.Equal("12345678901234567890123456789012", err.(OopsError).trace)
+	is.Equal("1234567890123456", err.(OopsError).span)
+
+	is.Nil(WrapContext(ctx, nil))
+	is.Nil(WrapfContext(ctx, nil, "a message"))
+
+	// without builder nor span
+	err = ErrorfContext(context.Background(), "a message %d", 42)
+	is.Error(err)
+	is.Empty(err.(OopsError).trace)
+	is.NotEmpty(err.(OopsError).span)
+	is.False(err.(OopsError).time.IsZero())
+}
+
+func TestOopsEmitUnixTime(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { EmitUnixTime = false }()
+
+	now, _ := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", "2023-05-02 05:26:48.570837 +0000 UTC")
+	err := new().Time(now).Wrap(assert.AnError)
+
+	is.NotContains(err.(OopsError).ToMap(), "time_unix_ms")
+
+	EmitUnixTime = true
+	is.Equal(int64(1683005208570), err.(OopsError).ToMap()["time_unix_ms"])
+
+	attrs := err.(OopsError).LogValuer().Group()
+	attr, ok := lo.Find(attrs, func(attr slog.Attr) bool { return attr.Key == "time_unix_ms" })
+	is.True(ok)
+	is.Equal(int64(1683005208570), attr.Value.Int64())
+}
+
+func TestOopsWithGroup(t *testing.T) {
+	is := assert.New(t)
+
+	builder := new().
+		With("user_id", 1234).
+		WithGroup("db", "query", "SELECT 1", "duration_ms", 42)
+	err := builder.
+		WithGroup("db", "rows", 0).
+		WithGroup("http").
+		Wrap(assert.AnError)
+
+	is.Equal(map[string]any{
+		"user_id": 1234,
+		"db":      map[string]any{"query": "SELECT 1", "duration_ms": 42, "rows": 0},
+		"http":    map[string]any{},
+	}, err.(OopsError).Context())
+
+	// parent builder is not altered
+	is.Equal(map[string]any{"query": "SELECT 1", "duration_ms": 42}, builder.context["db"])
+
+	got, jsonErr := json.Marshal(err.(OopsError).ToMap()["context"])
+	is.NoError(jsonErr)
+	is.Equal(`{"db":{"duration_ms":42,"query":"SELECT 1","rows":0},"http":{},"user_id":1234}`, string(got))
+
+	attrs := err.(OopsError).LogValuer().Group()
+	context, ok := lo.Find(attrs, func(attr slog.Attr) bool { return attr.Key == "context" })
+	is.True(ok)
+	db, ok := lo.Find(context.Value.Group(), func(attr slog.Attr) bool { return attr.Key == "db" })
+	is.True(ok)
+	is.Equal(slog.KindGroup, db.Value.Kind())
+	is.Len(db.Value.Group(), 3)
+}
+
+func TestOopsToProblemDetails(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().
+		Code("billing.invoice_not_found").
+		Trace("1234").
+		HTTPStatus(404).
+		Public("Invoice not found.").
+		Errorf("invoice 42 not found").(OopsError)
+
+	is.Equal(404, err.HTTPStatus())
+	is.Equal(404, err.ToMap()["http_status"])
+	is.Equal(
+		ProblemDetails{Type: "billing.invoice_not_found", Title: "Not Found", Status: 404, Detail: "Invoice not found.", Trace: "1234"},
+		err.ToProblemDetails(),
+	)
+
+	problem := new().Errorf("a message").(OopsError).ToProblemDetails()
+	is.Equal("about:blank", problem.Type)
+	is.Equal(500, problem.Status)
+	is.Equal("Internal Server Error", problem.Title)
+	is.Empty(problem.Detail)
+}
+
+func TestOopsMarshalText(t *testing.T) {
+	is := assert.New(t)
+
+	got, err := new().Code("iam_missing_permission").Errorf("permission denied").(OopsError).MarshalText()
+	is.NoError(err)
+	is.Equal("[iam_missing_permission] permission denied", string(got))
+
+	got, err = new().Errorf("permission denied").(OopsError).MarshalText()
+	is.NoError(err)
+	is.Equal("permission denied", string(got))
+}
+
+func TestOopsMarshalBinary(t *testing.T) {
+	is := assert.New(t)
+
+	now, _ := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", "2023-05-02 05:26:48.570837 +0200 UTC")
+
+	original := new().
+		Code("iam_missing_permission").
+		Severity(SeverityWarning).
+		Time(now).
+		Duration(time.Second).
+		In("authz").
+		Tags("iam").
+		Trace("1234").
+		Hint("Runbook: https://doc.acme.org/doc/abcd.md").
+		Public("public facing message").
+		Owner("authz-team@acme.org").
+		HTTPStatus(403).
+		RedactKeys("token").
+		With("user_id", 1234, "token", "secret").
+		Action("page_oncall", nil).
+		User("user-123", "firstname", "john").
+		Tenant("workspace-123", "name", "little project").
+		Wrapf(assert.AnError, "a message %d", 42).(OopsError)
+
+	b, err := original.MarshalBinary()
+	is.NoError(err)
+
+	var decoded OopsError
+	is.NoError(decoded.UnmarshalBinary(b))
+
+	is.Equal(original.Error(), decoded.Error())
+	is.Equal("iam_missing_permission", decoded.Code())
+	is.Equal(SeverityWarning, decoded.Severity())
+	is.True(now.Equal(decoded.Time()))
+	is.Equal(time.Second, decoded.Duration())
+	is.Equal("authz", decoded.Domain())
+	is.Equal([]string{"iam"}, decoded.Tags())
+	is.Equal("1234", decoded.Trace())
+	is.Equal("Runbook: https://doc.acme.org/doc/abcd.md", decoded.Hint())
+	is.Equal("public facing message", decoded.Public())
+	is.Equal("authz-team@acme.org", decoded.Owner())
+	is.Equal(403, decoded.HTTPStatus())
+	is.Equal(original.Fingerprint(), decoded.Fingerprint())
+	is.Equal([]RemediationAction{{Name: "page_oncall"}}, decoded.Actions())
+	is.Equal(map[string]any{"user_id": float64(1234), "token": RedactedValue}, decoded.Context())
+	userID, userData := decoded.User()
+	is.Equal("user-123", userID)
+	is.Equal(map[string]any{"firstname": "john"}, userData)
+	tenantID, tenantData := decoded.Tenant()
+	is.Equal("workspace-123", tenantID)
+	is.Equal(map[string]any{"name": "little project"}, tenantData)
+	is.Empty(decoded.Stacktrace())
+
+	is.Error(decoded.UnmarshalBinary([]byte("garbage")))
+
+	// nested action params, with redaction
+	original = new().
+		RedactKeys("token").
+		Action("rotate_credentials", map[string]any{
+			"service": map[string]any{"name": "billing", "token": "secret"},
+			"scopes":  []string{"read", "write"},
+		}).
+		Errorf("credentials leaked").(OopsError)
+
+	b, err = original.MarshalBinary()
+	is.NoError(err)
+	is.NoError(decoded.UnmarshalBinary(b))
+	is.Equal(
+		[]RemediationAction{{
+			Name: "rotate_credentials",
+			Params: map[string]any{
+				"service": map[string]any{"name": "billing", "token": RedactedValue},
+				"scopes":  []any{"read", "write"},
+			},
+		}},
+		decoded.Actions(),
+	)
+	is.Equal("secret", original.Actions()[0].Params["service"].(map[string]any)["token"])
+
+	// unmarshalable values are dropped and reported
+	original = new().
+		With("foo", "bar", "chan", make(chan int)).
+		User("user-123", "callback", func() {}).
+		Errorf("a message").(OopsError)
+
+	b, err = original.MarshalBinary()
+	is.NoError(err)
+	is.NoError(decoded.UnmarshalBinary(b))
+	is.Equal("bar", decoded.Context()["foo"])
+	is.Nil(decoded.Context()["chan"])
+	is.Equal(
+		"user callback marshaling: json: unsupported type: func(); chan marshaling: json: unsupported type: chan int",
+		decoded.Context()[integrationErrorKey],
+	)
+	userID, userData = decoded.User()
+	is.Equal("user-123", userID)
+	is.Empty(userData)
+	is.Contains(original.Context(), "chan")
+}
+
+func TestOopsToMapWith(t *testing.T) {
+	is := assert.New(t)
+
+	req, _ := http.NewRequest("POST", "http://localhost:1337/foobar", strings.NewReader("hello world"))
+
+	err := new().
+		Code("iam_missing_permission").
+		In("authz").
+		Trace("1234").
+		With("user_id", 1234).
+		User("user-123", "firstname", "john").
+		Request(req, true).
+		Errorf("permission denied").(OopsError)
+
+	is.Equal(
+		map[string]any{"code": "iam_missing_permission", "domain": "authz", "context": map[string]any{"user_id": 1234}},
+		err.ToMapWith(ToMapOptions{Include: []string{"code", "domain", "context"}}),
+	)
+
+	payload := err.ToMapWith(ToMapOptions{Exclude: []string{"request", "user"}})
+	is.NotContains(payload, "request")
+	is.NotContains(payload, "user")
+	is.Contains(payload, "code")
+	is.Contains(payload, "context")
+	is.Contains(payload, "stacktrace")
+
+	is.Equal(err.ToMap(), err.ToMapWith(ToMapOptions{}))
+}
+
+func TestOopsToFlatMap(t *testing.T) {
+	is := assert.New(t)
+
+	now, _ := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", "2023-05-02 05:26:48.570837 +0200 UTC")
+
+	err := new().
+		Code("iam_missing_permission").
+		Time(now).
+		Tags("iam", "authz").
+		Trace("1234").
+		With("user_id", 1234).
+		WithGroup("db", "query", "SELECT 1").
+		User("user-123", "firstname", "john").
+		Action("page_oncall", nil).
+		Action("rotate_credentials", nil).
+		WithoutStacktrace().
+		Errorf("permission denied").(OopsError)
+
+	is.Equal(
+		map[string]string{
+			"error":            "permission denied",
+			"code":             "iam_missing_permission",
+			"time":             "2023-05-02T05:26:48.570837Z",
+			"tags":             "iam,authz",
+			"trace":            "1234",
+			"context.user_id":  "1234",
+			"context.db.query": "SELECT 1",
+			"user.id":          "user-123",
+			"user.firstname":   "john",
+			"actions":          "page_oncall,rotate_credentials",
+		},
+		err.ToFlatMap("."),
+	)
+
+	is.Equal("1234", err.ToFlatMap("_")["context_user_id"])
+}
+
+func TestOopsToGCPErrorReport(t *testing.T) {
+	is := assert.New(t)
+
+	now, _ := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", "2023-05-02 05:26:48.570837 +0200 UTC")
+
+	err := new().
+		Code("iam_missing_permission").
+		Severity(SeverityWarning).
+		Time(now).
+		Trace("1234").
+		User("user-123").
+		Wrap(new().Time(now).Errorf("permission denied")).(OopsError)
+
+	report := err.ToGCPErrorReport("api", "1.2.3")
+	is.Equal(GCPErrorReportType, report.Type)
+	is.Equal("WARNING", report.Severity)
+	is.Equal("2023-05-02T05:26:48.570837Z", report.EventTime)
+	is.Equal(GCPServiceContext{Service: "api", Version: "1.2.3"}, report.ServiceContext)
+	is.Equal("user-123", report.Context.User)
+	is.Equal("iam_missing_permission", report.Oops["code"])
+	is.NotContains(report.Oops, "stacktrace")
+
+	is.True(strings.HasPrefix(report.Message, "permission denied\n\ngoroutine 1 [running]:\n"))
+	is.Contains(report.Message, "github.com/samber/oops.TestOopsToGCPErrorReport(...)\n\t")
+	is.NotNil(report.Context.ReportLocation)
+	is.True(strings.HasPrefix(report.Context.ReportLocation.FunctionName, "github.com/samber/oops."))
+	is.NotZero(report.Context.ReportLocation.LineNumber)
+
+	report = new().WithoutStacktrace().Errorf("permission denied").(OopsError).ToGCPErrorReport("api", "")
+	is.Equal("ERROR", report.Severity)
+	is.Equal("permission denied", report.Message)
+	is.Nil(report.Context.ReportLocation)
+
+	b, _ := json.Marshal(report)
+	is.Contains(string(b), `"serviceContext":{"service":"api"}`)
+}
+
+func TestOopsToDatadogAttributes(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().
+		Code("iam_missing_permission").
+		Trace("4bf92f3577b34da6a3ce929d0e0e4736").
+		Span("00f067aa0ba902b7").
+		User("user-123", "email", "john@example.com").
+		With("user_id", 1234).
+		Errorf("permission denied").(OopsError)
+
+	attrs := err.ToDatadogAttributes()
+	is.Equal("iam_missing_permission", attrs["code"])
+	is.Equal(map[string]any{"user_id": 1234}, attrs["context"])
+	is.Equal(map[string]any{"id": "user-123", "email": "john@example.com"}, attrs["usr"])
+	is.NotContains(attrs, "user")
+	is.NotContains(attrs, "stacktrace")
+	is.Equal(map[string]any{"trace_id": "11803532876627986230", "span_id": "67667974448284343"}, attrs["dd"])
+
+	errorAttrs, ok := attrs["error"].(map[string]any)
+	is.True(ok)
+	is.Equal("iam_missing_permission", errorAttrs["kind"])
+	is.Equal("permission denied", errorAttrs["message"])
+	is.Equal(err.Stacktrace(), errorAttrs["stack"])
+
+	// non-otel trace id, kind of the root cause
+	err = new().Trace("1234").WithoutStacktrace().Wrap(fmt.Errorf("%w", context.DeadlineExceeded)).(OopsError)
+	attrs = err.ToDatadogAttributes()
+	is.Equal(map[string]any{"trace_id": "1234"}, attrs["dd"])
+	is.Equal(map[string]any{"kind": "context.deadlineExceededError", "message": "context deadline exceeded"}, attrs["error"])
+
+	err = new().Trace("not-a-trace").WithoutStacktrace().Errorf("oops").(OopsError)
+	attrs = err.ToDatadogAttributes()
+	is.NotContains(attrs, "dd")
+	is.Equal("oops.OopsError", attrs["error"].(map[string]any)["kind"])
+}
+
+func TestOopsStackFrames(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().Errorf("permission denied").(OopsError)
+	frames := err.StackFrames()
+	is.NotEmpty(frames)
+	is.NotZero(frames[0].Line)
+	is.NotEmpty(frames[0].Source())
+
+	is.Empty(new().WithoutStacktrace().Errorf("permission denied").(OopsError).StackFrames())
+	is.Empty(StackFrame{File: "missing.go", Line: 1}.Source())
+}
+
+func TestOopsToMarkdown(t *testing.T) {
+	is := assert.New(t)
+
+	now, _ := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", "2023-05-02 05:26:48.570837 +0000 UTC")
+
+	err := new().
+		Code("iam_missing_permission").
+		Time(now).
+		Trace("1234").
+		Fingerprint("abcd").
+		With("query", "a|b").
+		User("user-123", "firstname", "john").
+		Action("page_oncall", nil).
+		WithoutStacktrace().
+		Errorf("permission denied").(OopsError)
+
+	expected := `## permission denied
+
+| Attribute | Value |
+| --- | --- |
+| Code | iam_missing_permission |
+| Time | 2023-05-02T05:26:48.570837Z |
+| Trace | 1234 |
+| Fingerprint | abcd |
+
+### Actions
+
+- ` + "`page_oncall`" + `
+
+### Context
+
+| Key | Value |
+| --- | --- |
+| query | a\|b |
+
+### User
+
+| Key | Value |
+| --- | --- |
+| firstname | john |
+| id | user-123 |
+`
+	is.Equal(expected, err.ToMarkdown())
+
+	err = new().Errorf("permission denied").(OopsError)
+	is.Contains(err.ToMarkdown(), "\n### Stacktrace\n\n```\nOops: permission denied\n")
+}
@@ -0,0 +1,29 @@
+package oopstest
+
+import (
+	"math/rand"
+
+	"github.com/samber/oops"
+)
+
+// Faulty returns a decorator that injects an `oops.OopsError` into a ratio of calls.
+// The injected error is built from `builder`, so handlers can be tested against
+// realistic error shapes (code, domain, context...).
+//
+// A ratio <= 0 never injects a fault. A ratio >= 1 always injects a fault.
+//
+//	fault := oopstest.Faulty(0.1, oops.Code("db_timeout").In("repository"))
+//	fetchUser = fault(fetchUser)
+func Faulty(ratio float64, builder oops.OopsErrorBuilder) func(cb func() error) func() error {
+	return func(cb func() error) func() error {
+		return func() error {
+			if ratio > 0 && (ratio >= 1 || rand.Float64() < ratio) {
+				return builder.
+					Tags("fault_injection").
+					Errorf("oopstest: injected fault")
+			}
+
+			return cb()
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package oopstest
+
+import (
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaulty(t *testing.T) {
+	is := assert.New(t)
+
+	calls := 0
+	cb := func() error {
+		calls++
+		return nil
+	}
+
+	err := Faulty(0, oops.Code("db_timeout"))(cb)()
+	is.NoError(err)
+	is.Equal(1, calls)
+
+	err = Faulty(1, oops.Code("db_timeout").In("repository"))(cb)()
+	is.Error(err)
+	is.Equal(1, calls)
+	is.Equal("oopstest: injected fault", err.Error())
+
+	oopsErr, ok := oops.AsOops(err)
+	is.True(ok)
+	is.Equal("db_timeout", oopsErr.Code())
+	is.Equal("repository", oopsErr.Domain())
+	is.Equal([]string{"fault_injection"}, oopsErr.Tags())
+}
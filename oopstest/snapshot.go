@@ -0,0 +1,113 @@
+package oopstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/samber/oops"
+)
+
+var update = flag.Bool("oopstest.update", false, "update the golden files of oopstest.Snapshot")
+
+var (
+	// eg: 2023-05-02T05:26:48.570837Z, 2023-05-02 05:26:48.570837 +0000 UTC
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}| [+-]\d{4} [A-Z]+)?`)
+
+	// eg: 1683005208570
+	unixTimestampPattern = regexp.MustCompile(`("time_unix_ms":\s*)\d+`)
+
+	// default trace and span ids (ULID)
+	idPattern = regexp.MustCompile(`\b[0-9A-HJKMNP-TV-Z]{26}\b`)
+
+	// eg: /home/john/app/users/repository.go:42 -> repository.go:<line>
+	absolutePathPattern = regexp.MustCompile(`(^|[\s"'(=])/[^\s"'():]*/([^/\s"'():]+\.go)`)
+	lineNumberPattern   = regexp.MustCompile(`(\.go):\d+`)
+)
+
+// Normalize strips the parts of an error output that change between runs or
+// machines: timestamps, default trace and span ids, absolute paths of files
+// and line numbers of stack frames.
+func Normalize(output string) string {
+	output = timestampPattern.ReplaceAllString(output, "<time>")
+	output = unixTimestampPattern.ReplaceAllString(output, "${1}0")
+	output = idPattern.ReplaceAllString(output, "<id>")
+	output = absolutePathPattern.ReplaceAllString(output, "${1}${2}")
+	output = lineNumberPattern.ReplaceAllString(output, "${1}:<line>")
+
+	return output
+}
+
+// Verbose returns the normalized `fmt.Sprintf("%+v", err)` output of an error.
+func Verbose(err error) string {
+	return Normalize(fmt.Sprintf("%+v", err))
+}
+
+// JSON returns the normalized and indented JSON output of an error.
+func JSON(err error) string {
+	b, e := json.MarshalIndent(err, "", "  ")
+	if e != nil {
+		return Normalize(fmt.Sprintf("json marshaling: %s", e.Error()))
+	}
+
+	return Normalize(string(b))
+}
+
+// Slog returns the normalized JSON line of an error logged with slog, under
+// the "error" key.
+func Slog(err error) string {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	attr := slog.String("error", err.Error())
+	if oopsErr, ok := oops.AsOops(err); ok {
+		attr = slog.Any("error", oopsErr.LogValuer())
+	}
+
+	logger.Error(err.Error(), attr)
+
+	return Normalize(buf.String())
+}
+
+// Snapshot compares an output with the golden file `testdata/<name>.golden`.
+// Run the tests with `-oopstest.update` to write the golden files.
+//
+//	oopstest.Snapshot(t, "invoice_not_found.json", oopstest.JSON(err))
+func Snapshot(t testing.TB, name string, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("oopstest: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("oopstest: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("oopstest: %s (run the tests with -oopstest.update to create it)", err)
+	}
+
+	if string(want) != got {
+		t.Errorf("oopstest: %s does not match the output (run the tests with -oopstest.update to update it)\n--- want\n%s\n--- got\n%s", path, want, got)
+	}
+}
@@ -0,0 +1,34 @@
+package oopstest
+
+import (
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("Time: <time>", Normalize("Time: 2023-05-02 05:26:48.570837 +0000 UTC"))
+	is.Equal(`{"time":"<time>","time_unix_ms":0}`, Normalize(`{"time":"2023-05-02T05:26:48.570837Z","time_unix_ms":1683005208570}`))
+	is.Equal("Trace: <id>", Normalize("Trace: 01H0Z5C2Q3K8V9W6X7Y8Z9A0BC"))
+	is.Equal("  --- at repository.go:<line> GetUser()", Normalize("  --- at /home/john/app/users/repository.go:42 GetUser()"))
+	is.Equal("  --- at github.com/acme/api/users/repository.go:<line> GetUser()", Normalize("  --- at github.com/acme/api/users/repository.go:42 GetUser()"))
+}
+
+func snapshotError() error {
+	return oops.
+		Code("iam_missing_permission").
+		In("authz").
+		With("user_id", 1234, "action", "read", "resource", "invoice").
+		Wrapf(oops.Errorf("permission denied"), "could not fetch user")
+}
+
+func TestSnapshot(t *testing.T) {
+	err := snapshotError()
+
+	Snapshot(t, "verbose", Verbose(err))
+	Snapshot(t, "json", JSON(err))
+	Snapshot(t, "slog", Slog(err))
+}
@@ -0,0 +1,68 @@
+package oopsotel
+
+import (
+	"context"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	EventCreated = "created"
+	EventLogged  = "logged"
+)
+
+// Meter counts oops errors with an OpenTelemetry counter:
+// `oops.errors_total{event, code, domain, severity}`.
+type Meter struct {
+	errors metric.Int64Counter
+}
+
+// NewMeter returns a Meter creating its counter with meter.
+//
+// Errors are counted on creation when `m.Hook` is registered with `oops.OnError`,
+// and when logged by calling `m.Observe`.
+//
+//	m, err := oopsotel.NewMeter(otel.GetMeterProvider().Meter("myapp"))
+//	oops.OnError(m.Hook)
+func NewMeter(meter metric.Meter) (*Meter, error) {
+	errors, err := meter.Int64Counter(
+		"oops.errors_total",
+		metric.WithDescription("Number of oops errors, by code, domain and severity."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Meter{
+		errors: errors,
+	}, nil
+}
+
+// Hook counts errors on creation. It must be registered with `oops.OnError`.
+func (m *Meter) Hook(err oops.OopsError) oops.OopsError {
+	m.add(context.Background(), EventCreated, err)
+	return err
+}
+
+// Observe counts logged errors. Non-oops errors are ignored.
+func (m *Meter) Observe(ctx context.Context, err error) {
+	if oopsErr, ok := oops.AsOops(err); ok {
+		m.add(ctx, EventLogged, oopsErr)
+	}
+}
+
+func (m *Meter) add(ctx context.Context, event string, err oops.OopsError) {
+	m.errors.Add(
+		ctx,
+		1,
+		metric.WithAttributes(
+			attribute.String("event", event),
+			attribute.String("code", err.Code()),
+			attribute.String("domain", err.Domain()),
+			attribute.String("severity", string(err.Severity())),
+		),
+	)
+}
@@ -0,0 +1,53 @@
+package oopsotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMeter(t *testing.T) {
+	is := assert.New(t)
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	m, err := NewMeter(provider.Meter("test"))
+	is.NoError(err)
+
+	e := oops.
+		Code("iam_missing_permission").
+		In("authz").
+		Severity(oops.SeverityWarning).
+		Errorf("permission denied").(oops.OopsError)
+
+	m.Hook(e)
+	m.Observe(context.Background(), e)
+	m.Observe(context.Background(), e)
+	m.Observe(context.Background(), errors.New("ignored"))
+
+	var rm metricdata.ResourceMetrics
+	is.NoError(reader.Collect(context.Background(), &rm))
+	is.Len(rm.ScopeMetrics, 1)
+	is.Len(rm.ScopeMetrics[0].Metrics, 1)
+
+	metric := rm.ScopeMetrics[0].Metrics[0]
+	is.Equal("oops.errors_total", metric.Name)
+
+	counts := map[string]int64{}
+	for _, point := range metric.Data.(metricdata.Sum[int64]).DataPoints {
+		event, _ := point.Attributes.Value(attribute.Key("event"))
+		code, _ := point.Attributes.Value(attribute.Key("code"))
+		severity, _ := point.Attributes.Value(attribute.Key("severity"))
+		is.Equal("iam_missing_permission", code.AsString())
+		is.Equal("warning", severity.AsString())
+		counts[event.AsString()] = point.Value
+	}
+	is.Equal(map[string]int64{EventCreated: 1, EventLogged: 2}, counts)
+}
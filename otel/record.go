@@ -0,0 +1,137 @@
+package oopsotel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/oops"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ToRecord converts an error into an OpenTelemetry log record: the message is
+// used as body, and the attributes of `err.ToMap()` as attributes. Non-oops
+// errors only have a body.
+func ToRecord(err error) log.Record {
+	var record log.Record
+	record.SetObservedTimestamp(time.Now())
+	record.SetBody(log.StringValue(err.Error()))
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		record.SetSeverity(log.SeverityError)
+		record.SetSeverityText(string(oops.SeverityError))
+		return record
+	}
+
+	severity := oopsError.Severity()
+	if severity == oops.SeverityUnknown {
+		severity = oops.SeverityError
+	}
+
+	record.SetTimestamp(oopsError.Time())
+	record.SetSeverity(toSeverity(severity))
+	record.SetSeverityText(string(severity))
+
+	payload := oopsError.ToMap()
+	delete(payload, "error")
+	delete(payload, "time")
+	delete(payload, "severity")
+
+	for k, v := range payload {
+		record.AddAttributes(log.KeyValue{Key: k, Value: toValue(v)})
+	}
+
+	return record
+}
+
+// Emit emits the error through an OpenTelemetry logger. When the context has no
+// span, the trace and span ids of the error are attached to the record, if they
+// are valid OpenTelemetry ids.
+//
+//	logger := global.GetLoggerProvider().Logger("myapp")
+//	oopsotel.Emit(ctx, logger, err)
+func Emit(ctx context.Context, logger log.Logger, err error) {
+	logger.Emit(ContextWithSpan(ctx, err), ToRecord(err))
+}
+
+// ContextWithSpan returns a context carrying the trace and span ids of the error,
+// when the context has no span and the ids are valid OpenTelemetry ids.
+func ContextWithSpan(ctx context.Context, err error) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return ctx
+	}
+
+	traceID, traceErr := trace.TraceIDFromHex(oopsError.Trace())
+	spanID, spanErr := trace.SpanIDFromHex(oopsError.Span())
+	if traceErr != nil || spanErr != nil {
+		return ctx
+	}
+
+	return trace.ContextWithSpanContext(
+		ctx,
+		trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+			Remote:  true,
+		}),
+	)
+}
+
+func toSeverity(severity oops.SeverityLevel) log.Severity {
+	switch severity {
+	case oops.SeverityDebug:
+		return log.SeverityDebug
+	case oops.SeverityInfo:
+		return log.SeverityInfo
+	case oops.SeverityWarning:
+		return log.SeverityWarn
+	case oops.SeverityFatal:
+		return log.SeverityFatal
+	default:
+		return log.SeverityError
+	}
+}
+
+func toValue(v any) log.Value {
+	switch value := v.(type) {
+	case string:
+		return log.StringValue(value)
+	case bool:
+		return log.BoolValue(value)
+	case int:
+		return log.IntValue(value)
+	case int64:
+		return log.Int64Value(value)
+	case float64:
+		return log.Float64Value(value)
+	case []byte:
+		return log.BytesValue(value)
+	case time.Time:
+		return log.StringValue(value.Format(time.RFC3339Nano))
+	case time.Duration:
+		return log.StringValue(value.String())
+	case []string:
+		values := make([]log.Value, 0, len(value))
+		for _, item := range value {
+			values = append(values, log.StringValue(item))
+		}
+		return log.SliceValue(values...)
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(value))
+		for k, item := range value {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: toValue(item)})
+		}
+		return log.MapValue(kvs...)
+	case nil:
+		return log.Value{}
+	default:
+		return log.StringValue(fmt.Sprint(value))
+	}
+}
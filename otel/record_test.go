@@ -0,0 +1,68 @@
+package oopsotel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestToRecord(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Date(2023, 5, 2, 5, 26, 48, 570837000, time.UTC)
+
+	err := oops.
+		Code("iam_missing_permission").
+		Severity(oops.SeverityWarning).
+		Time(now).
+		In("authz").
+		Tags("iam").
+		With("user_id", 1234).
+		Errorf("permission denied")
+
+	record := ToRecord(err)
+	is.Equal("permission denied", record.Body().AsString())
+	is.Equal(now, record.Timestamp())
+	is.Equal(log.SeverityWarn, record.Severity())
+	is.Equal("warning", record.SeverityText())
+
+	attrs := map[string]log.Value{}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	is.Equal("iam_missing_permission", attrs["code"].AsString())
+	is.Equal("authz", attrs["domain"].AsString())
+	is.Equal([]log.Value{log.StringValue("iam")}, attrs["tags"].AsSlice())
+	is.Equal([]log.KeyValue{log.Int("user_id", 1234)}, attrs["context"].AsMap())
+	is.NotContains(attrs, "error")
+	is.NotContains(attrs, "time")
+
+	record = ToRecord(assert.AnError)
+	is.Equal(assert.AnError.Error(), record.Body().AsString())
+	is.Equal(log.SeverityError, record.Severity())
+	is.Equal(0, record.AttributesLen())
+}
+
+func TestContextWithSpan(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Trace("4bf92f3577b34da6a3ce929d0e0e4736").
+		Span("00f067aa0ba902b7").
+		Errorf("permission denied")
+
+	sc := trace.SpanContextFromContext(ContextWithSpan(context.Background(), err))
+	is.True(sc.IsValid())
+	is.Equal("4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+	is.Equal("00f067aa0ba902b7", sc.SpanID().String())
+
+	// not an otel trace id
+	err = oops.Trace("1234").Errorf("permission denied")
+	is.False(trace.SpanContextFromContext(ContextWithSpan(context.Background(), err)).IsValid())
+}
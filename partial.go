@@ -0,0 +1,108 @@
+package oops
+
+import (
+	"encoding/json"
+
+	"github.com/samber/lo"
+)
+
+// Partial reports the outcome of a bulk operation where some items may
+// succeed while others fail. It is not safe for concurrent use.
+//
+//	result := oops.Partial[User]{}
+//	for _, user := range users {
+//		result.Add(user, repository.Save(user))
+//	}
+//
+//	return result, result.Err()
+type Partial[T any] struct {
+	Succeeded []T
+	Failed    []PartialFailure[T]
+}
+
+// PartialFailure is an item that failed during a bulk operation, along with its error.
+type PartialFailure[T any] struct {
+	Item T
+	Err  OopsError
+}
+
+// Add records the outcome of an item: a nil error marks the item as succeeded.
+// Errors are wrapped into an `oops.OopsError` when needed.
+func (p *Partial[T]) Add(item T, err error) {
+	if err == nil {
+		p.Succeed(item)
+	} else {
+		p.Fail(item, err)
+	}
+}
+
+// Succeed marks an item as succeeded.
+func (p *Partial[T]) Succeed(item T) {
+	p.Succeeded = append(p.Succeeded, item)
+}
+
+// Fail marks an item as failed. The error is wrapped into an `oops.OopsError`
+// when it is not one, so that the messages of outer errors are kept (eg:
+// `fmt.Errorf("item 3: %w", err)`).
+func (p *Partial[T]) Fail(item T, err error) {
+	oopsErr, ok := err.(OopsError)
+	if !ok {
+		oopsErr = new().Wrap(err).(OopsError)
+	}
+
+	p.Failed = append(p.Failed, PartialFailure[T]{Item: item, Err: oopsErr})
+}
+
+// HasFailures returns true if at least one item failed.
+func (p Partial[T]) HasFailures() bool {
+	return len(p.Failed) > 0
+}
+
+// Err returns the errors of failed items, joined in a single `oops.OopsError`,
+// or nil if all items succeeded.
+func (p Partial[T]) Err() error {
+	if !p.HasFailures() {
+		return nil
+	}
+
+	return Join(
+		lo.Map(p.Failed, func(f PartialFailure[T], _ int) error {
+			return f.Err
+		})...,
+	)
+}
+
+// MarshalJSON implements json.Marshaler. Errors of failed items are serialized
+// with their code, public message and trace only, since the payload is meant
+// for end users.
+func (p Partial[T]) MarshalJSON() ([]byte, error) {
+	payload := map[string]any{
+		"succeeded": lo.Ternary(p.Succeeded != nil, p.Succeeded, []T{}),
+		"failed": lo.Map(p.Failed, func(f PartialFailure[T], _ int) map[string]any {
+			return map[string]any{
+				"item":  f.Item,
+				"error": partialErrorToMap(f.Err),
+			}
+		}),
+	}
+
+	return json.Marshal(payload)
+}
+
+func partialErrorToMap(err OopsError) map[string]any {
+	payload := map[string]any{}
+
+	if code := err.Code(); code != "" {
+		payload["code"] = code
+	}
+
+	if public := err.Public(); public != "" {
+		payload["public"] = public
+	}
+
+	if trace := err.Trace(); trace != "" {
+		payload["trace"] = trace
+	}
+
+	return payload
+}
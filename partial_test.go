@@ -0,0 +1,64 @@
+package oops
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartial(t *testing.T) {
+	is := assert.New(t)
+
+	result := Partial[int]{}
+	is.False(result.HasFailures())
+	is.Nil(result.Err())
+
+	result.Add(1, nil)
+	result.Add(2, assert.AnError)
+	result.Add(3, new().Code("not_found").Errorf("item not found"))
+	result.Add(5, fmt.Errorf("item 5: %w", new().Code("not_found").Errorf("item not found")))
+	result.Succeed(4)
+
+	is.True(result.HasFailures())
+	is.Equal([]int{1, 4}, result.Succeeded)
+	is.Len(result.Failed, 3)
+	is.Equal(2, result.Failed[0].Item)
+	is.True(errors.Is(result.Failed[0].Err, assert.AnError))
+	is.Equal(3, result.Failed[1].Item)
+	is.Equal("not_found", result.Failed[1].Err.Code())
+	is.Equal(5, result.Failed[2].Item)
+	is.Equal("item 5: item not found", result.Failed[2].Err.Error())
+	is.Equal("not_found", result.Failed[2].Err.Code())
+
+	err := result.Err()
+	is.Error(err)
+	is.True(errors.Is(err, assert.AnError))
+	is.Equal("assert.AnError general error for testing\nitem not found\nitem 5: item not found", err.Error())
+}
+
+func TestPartialMarshalJSON(t *testing.T) {
+	is := assert.New(t)
+
+	result := Partial[string]{}
+	got, err := json.Marshal(result)
+	is.NoError(err)
+	is.Equal(`{"failed":[],"succeeded":[]}`, string(got))
+
+	result.Add("foo", nil)
+	result.Add("bar", new().Code("not_found").Public("Item not found.").Trace("1234").With("sql", "SELECT 1").Errorf("item not found"))
+
+	got, err = json.Marshal(result)
+	is.NoError(err)
+
+	var payload map[string]any
+	is.NoError(json.Unmarshal(got, &payload))
+	is.Equal([]any{"foo"}, payload["succeeded"])
+	is.Nil(payload["error"])
+	is.Len(payload["failed"], 1)
+	failed := payload["failed"].([]any)[0].(map[string]any)
+	is.Equal("bar", failed["item"])
+	is.Equal(map[string]any{"code": "not_found", "public": "Item not found.", "trace": "1234"}, failed["error"])
+}
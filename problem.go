@@ -0,0 +1,42 @@
+package oops
+
+import (
+	"net/http"
+)
+
+// ProblemDetails is the RFC 7807 representation of an error.
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// extension members
+	Domain string `json:"domain,omitempty"`
+	Trace  string `json:"trace,omitempty"`
+}
+
+// ToProblemDetails returns the RFC 7807 representation of the error: the code
+// is used as type, the public message as detail. Errors without http status
+// are reported as 500 Internal Server Error.
+func (o OopsError) ToProblemDetails() ProblemDetails {
+	status := o.HTTPStatus()
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	problemType := o.Code()
+	if problemType == "" {
+		problemType = "about:blank"
+	}
+
+	return ProblemDetails{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: o.Public(),
+		Domain: o.Domain(),
+		Trace:  o.Trace(),
+	}
+}
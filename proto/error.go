@@ -0,0 +1,212 @@
+package oopsproto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative oops.proto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/oops"
+	"google.golang.org/protobuf/proto"
+)
+
+// FromError returns the `oops.v1.Error` protobuf message of an error. Values of
+// redacted keys are not exported (see `oops.RedactKeys`). Non-oops errors only
+// have a message.
+func FromError(err error) (*Error, error) {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return &Error{Message: err.Error()}, nil
+	}
+
+	payload := oopsError.ToMap()
+
+	userID, _ := oopsError.User()
+	tenantID, _ := oopsError.Tenant()
+
+	e := &Error{
+		Message:     oopsError.Error(),
+		Code:        oopsError.Code(),
+		Severity:    string(oopsError.Severity()),
+		Domain:      oopsError.Domain(),
+		Tags:        oopsError.Tags(),
+		Trace:       oopsError.Trace(),
+		Span:        oopsError.Span(),
+		Hint:        oopsError.Hint(),
+		Public:      oopsError.Public(),
+		Owner:       oopsError.Owner(),
+		HttpStatus:  int32(oopsError.HTTPStatus()),
+		Fingerprint: oopsError.Fingerprint(),
+		UserId:      userID,
+		TenantId:    tenantID,
+		Stacktrace:  oopsError.Stacktrace(),
+	}
+
+	if t := oopsError.Time(); !t.IsZero() {
+		e.TimeUnixNano = t.UnixNano()
+	}
+	e.DurationNanos = int64(oopsError.Duration())
+
+	if e.Context, err = encodeMap(payload["context"], ""); err != nil {
+		return nil, fmt.Errorf("context: %w", err)
+	}
+	if e.UserData, err = encodeMap(payload["user"], "id"); err != nil {
+		return nil, fmt.Errorf("user: %w", err)
+	}
+	if e.TenantData, err = encodeMap(payload["tenant"], "id"); err != nil {
+		return nil, fmt.Errorf("tenant: %w", err)
+	}
+
+	for _, action := range oopsError.Actions() {
+		var params []byte
+		if action.Params != nil {
+			if params, err = json.Marshal(action.Params); err != nil {
+				return nil, fmt.Errorf("action %s: %w", action.Name, err)
+			}
+		}
+
+		e.Actions = append(e.Actions, &Action{Name: action.Name, Params: params})
+	}
+
+	return e, nil
+}
+
+// ToOopsError rebuilds an `oops.OopsError` from the protobuf message. Map values
+// are decoded from JSON, so numbers become float64. The stacktrace of the
+// original error is kept in e.Stacktrace only: the rebuilt error has no stacktrace.
+func (e *Error) ToOopsError() (oops.OopsError, error) {
+	context, err := decodeMap(e.GetContext())
+	if err != nil {
+		return oops.OopsError{}, fmt.Errorf("context: %w", err)
+	}
+	userData, err := decodeMap(e.GetUserData())
+	if err != nil {
+		return oops.OopsError{}, fmt.Errorf("user: %w", err)
+	}
+	tenantData, err := decodeMap(e.GetTenantData())
+	if err != nil {
+		return oops.OopsError{}, fmt.Errorf("tenant: %w", err)
+	}
+
+	builder := oops.
+		WithoutStacktrace().
+		Code(e.GetCode()).
+		Severity(oops.SeverityLevel(e.GetSeverity())).
+		Duration(time.Duration(e.GetDurationNanos())).
+		In(e.GetDomain()).
+		Tags(e.GetTags()...).
+		Trace(e.GetTrace()).
+		Span(e.GetSpan()).
+		Hint(e.GetHint()).
+		Public(e.GetPublic()).
+		Owner(e.GetOwner()).
+		HTTPStatus(int(e.GetHttpStatus())).
+		Fingerprint(e.GetFingerprint()).
+		With(mapToKV(context)...).
+		User(e.GetUserId(), mapToKV(userData)...).
+		Tenant(e.GetTenantId(), mapToKV(tenantData)...)
+
+	if e.GetTimeUnixNano() != 0 {
+		builder = builder.Time(time.Unix(0, e.GetTimeUnixNano()))
+	}
+
+	for _, action := range e.GetActions() {
+		var params map[string]any
+		if len(action.GetParams()) > 0 {
+			if err := json.Unmarshal(action.GetParams(), &params); err != nil {
+				return oops.OopsError{}, fmt.Errorf("action %s: %w", action.GetName(), err)
+			}
+		}
+
+		builder = builder.Action(action.GetName(), params)
+	}
+
+	return builder.Wrap(errors.New(e.GetMessage())).(oops.OopsError), nil
+}
+
+// Marshal encodes an error into the `oops.v1.Error` protobuf message. Map
+// entries are sorted by key.
+func Marshal(err error) ([]byte, error) {
+	e, err := FromError(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(e)
+}
+
+// Unmarshal decodes an `oops.v1.Error` protobuf message into an `oops.OopsError`.
+func Unmarshal(b []byte) (oops.OopsError, error) {
+	e, err := UnmarshalError(b)
+	if err != nil {
+		return oops.OopsError{}, err
+	}
+
+	return e.ToOopsError()
+}
+
+// UnmarshalError decodes an `oops.v1.Error` protobuf message. Unknown fields are ignored.
+func UnmarshalError(b []byte) (*Error, error) {
+	e := &Error{}
+	if err := proto.Unmarshal(b, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// encodeMap encodes the values of a map<string, bytes> field as JSON.
+func encodeMap(v any, exclude string) (map[string][]byte, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+
+	output := map[string][]byte{}
+	for k, v := range m {
+		if k == exclude {
+			continue
+		}
+
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+
+		output[k] = raw
+	}
+
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	return output, nil
+}
+
+// decodeMap decodes the JSON values of a map<string, bytes> field.
+func decodeMap(m map[string][]byte) (map[string]any, error) {
+	output := make(map[string]any, len(m))
+	for k, raw := range m {
+		var v any
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+		}
+
+		output[k] = v
+	}
+
+	return output, nil
+}
+
+func mapToKV(m map[string]any) []any {
+	kv := make([]any, 0, len(m)*2)
+	for k, v := range m {
+		kv = append(kv, k, v)
+	}
+
+	return kv
+}
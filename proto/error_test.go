@@ -0,0 +1,92 @@
+package oopsproto
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Date(2023, 5, 2, 5, 26, 48, 570837000, time.UTC)
+
+	err := oops.
+		Code("billing.invoice_not_found").
+		Severity(oops.SeverityWarning).
+		Time(now).
+		Duration(time.Second).
+		In("billing").
+		Tags("invoice", "db").
+		Trace("1234").
+		Span("5678").
+		Hint("Check the invoice id").
+		Public("Invoice not found.").
+		Owner("billing-team@acme.org").
+		HTTPStatus(404).
+		RedactKeys("token").
+		With("invoice_id", 42, "token", "secret").
+		Action("page_oncall", map[string]any{"team": "billing"}).
+		User("user-123", "firstname", "john").
+		Tenant("workspace-123", "name", "little project").
+		Errorf("invoice %d not found", 42)
+
+	b, marshalErr := Marshal(err)
+	is.NoError(marshalErr)
+
+	rebuilt, unmarshalErr := Unmarshal(b)
+	is.NoError(unmarshalErr)
+
+	original := err.(oops.OopsError)
+	is.Equal("invoice 42 not found", rebuilt.Error())
+	is.Equal("billing.invoice_not_found", rebuilt.Code())
+	is.Equal(oops.SeverityWarning, rebuilt.Severity())
+	is.True(now.Equal(rebuilt.Time()))
+	is.Equal(time.Second, rebuilt.Duration())
+	is.Equal("billing", rebuilt.Domain())
+	is.Equal([]string{"invoice", "db"}, rebuilt.Tags())
+	is.Equal("1234", rebuilt.Trace())
+	is.Equal("5678", rebuilt.Span())
+	is.Equal("Check the invoice id", rebuilt.Hint())
+	is.Equal("Invoice not found.", rebuilt.Public())
+	is.Equal("billing-team@acme.org", rebuilt.Owner())
+	is.Equal(404, rebuilt.HTTPStatus())
+	is.Equal(original.Fingerprint(), rebuilt.Fingerprint())
+	is.Equal(map[string]any{"invoice_id": float64(42), "token": oops.RedactedValue}, rebuilt.Context())
+	is.Equal([]oops.RemediationAction{{Name: "page_oncall", Params: map[string]any{"team": "billing"}}}, rebuilt.Actions())
+
+	userID, userData := rebuilt.User()
+	is.Equal("user-123", userID)
+	is.Equal(map[string]any{"firstname": "john"}, userData)
+
+	tenantID, tenantData := rebuilt.Tenant()
+	is.Equal("workspace-123", tenantID)
+	is.Equal(map[string]any{"name": "little project"}, tenantData)
+
+	decoded, decodeErr := UnmarshalError(b)
+	is.NoError(decodeErr)
+	is.Equal(original.Stacktrace(), decoded.GetStacktrace())
+	is.Equal("billing.invoice_not_found", decoded.GetCode())
+	is.Equal(now.UnixNano(), decoded.GetTimeUnixNano())
+	is.Equal([]byte("42"), decoded.GetContext()["invoice_id"])
+}
+
+func TestMarshalErrors(t *testing.T) {
+	is := assert.New(t)
+
+	b, err := Marshal(errors.New("a message"))
+	is.NoError(err)
+
+	got, err := Unmarshal(b)
+	is.NoError(err)
+	is.Equal("a message", got.Error())
+
+	_, err = Marshal(oops.With("chan", make(chan int)).Errorf("a message"))
+	is.Error(err)
+
+	_, err = Unmarshal([]byte{0xff})
+	is.Error(err)
+}
@@ -0,0 +1,82 @@
+package oopsrecoveryecho
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/oops"
+)
+
+// TraceHeader is the response header carrying the trace id of oops errors.
+const TraceHeader = "X-Trace-Id"
+
+// EchoOopsRecovery recovers panics into oops errors, with the request attached,
+// and returns them to the error handler of echo.
+func EchoOopsRecovery() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err error
+
+			recovered := oops.
+				FromContext(c.Request().Context()).
+				Request(c.Request(), false).
+				Recoverf(func() {
+					err = next(c)
+				}, "echo: panic recovered")
+			if recovered != nil {
+				return recovered
+			}
+
+			return err
+		}
+	}
+}
+
+// Response is the JSON body written by HTTPErrorHandler.
+type Response struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+	Trace string `json:"trace,omitempty"`
+}
+
+// HTTPErrorHandler translates oops errors into JSON responses, with the public
+// message of the error, its code and its trace id (also sent in the `X-Trace-Id`
+// header). The status is the http status of the error (default: 500). Other
+// errors are handled by the default error handler of echo.
+//
+//	e := echo.New()
+//	e.Use(oopsrecoveryecho.EchoOopsRecovery())
+//	e.HTTPErrorHandler = oopsrecoveryecho.HTTPErrorHandler
+func HTTPErrorHandler(err error, c echo.Context) {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		c.Echo().DefaultHTTPErrorHandler(err, c)
+		return
+	}
+
+	if c.Response().Committed {
+		return
+	}
+
+	status := oopsError.HTTPStatus()
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	trace := oopsError.Trace()
+	c.Response().Header().Set(TraceHeader, trace)
+
+	var e error
+	if c.Request().Method == http.MethodHead {
+		e = c.NoContent(status)
+	} else {
+		e = c.JSON(status, Response{
+			Error: oops.GetPublic(oopsError, http.StatusText(status)),
+			Code:  oopsError.Code(),
+			Trace: trace,
+		})
+	}
+	if e != nil {
+		c.Logger().Error(e)
+	}
+}
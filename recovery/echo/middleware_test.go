@@ -0,0 +1,51 @@
+package oopsrecoveryecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEchoOopsRecovery(t *testing.T) {
+	is := assert.New(t)
+
+	e := echo.New()
+	e.Use(EchoOopsRecovery())
+	e.HTTPErrorHandler = HTTPErrorHandler
+
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+	e.GET("/oops", func(c echo.Context) error {
+		return oops.
+			Code("invoice_not_found").
+			HTTPStatus(http.StatusNotFound).
+			Public("Invoice not found.").
+			Trace("1234").
+			Errorf("sql: no rows")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oops", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("1234", rec.Header().Get(TraceHeader))
+	is.JSONEq(`{"error":"Invoice not found.","code":"invoice_not_found","trace":"1234"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.NotEmpty(rec.Header().Get(TraceHeader))
+	is.Contains(rec.Body.String(), `"error":"Internal Server Error"`)
+	is.NotContains(rec.Body.String(), "boom")
+
+	// other errors are handled by echo
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Empty(rec.Header().Get(TraceHeader))
+	is.JSONEq(`{"message":"Not Found"}`, rec.Body.String())
+}
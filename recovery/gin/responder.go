@@ -0,0 +1,99 @@
+package oopsrecoverygin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+)
+
+// TraceHeader is the response header carrying the trace id of oops errors.
+const TraceHeader = "X-Trace-Id"
+
+// Response is the default JSON body written by GinOopsErrorResponder.
+type Response struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+	Trace string `json:"trace,omitempty"`
+}
+
+// ErrorResponderOptions configures the error responder.
+type ErrorResponderOptions struct {
+	// Envelope returns the JSON body of the response. Defaults to Response.
+	Envelope func(err oops.OopsError, status int) any
+}
+
+// GinOopsErrorResponder renders the last oops error found in `c.Errors` as a
+// JSON body, with the public message, the code and the trace id of the error
+// (also sent in the `X-Trace-Id` header). The status is the http status of the
+// error, or the status of the aborted request (default: 500). Responses with
+// a body are left untouched.
+//
+//	router.Use(oopsrecoverygin.GinOopsErrorResponder())
+//	router.Use(oopsrecoverygin.GinOopsRecovery())
+func GinOopsErrorResponder() gin.HandlerFunc {
+	return GinOopsErrorResponderWith(ErrorResponderOptions{})
+}
+
+// GinOopsErrorResponderWith returns an error responder with a custom envelope.
+//
+//	router.Use(oopsrecoverygin.GinOopsErrorResponderWith(oopsrecoverygin.ErrorResponderOptions{
+//		Envelope: func(err oops.OopsError, status int) any {
+//			return gin.H{"errors": []gin.H{{"message": err.Public(), "code": err.Code()}}}
+//		},
+//	}))
+func GinOopsErrorResponderWith(opts ErrorResponderOptions) gin.HandlerFunc {
+	envelope := opts.Envelope
+	if envelope == nil {
+		envelope = defaultEnvelope
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		// a body has been written by the handler
+		if c.Writer.Size() > 0 {
+			return
+		}
+
+		oopsError, ok := lastOopsError(c.Errors)
+		if !ok {
+			return
+		}
+
+		status := oopsError.HTTPStatus()
+		switch {
+		case c.Writer.Written():
+			// headers sent by c.AbortWithStatus()
+			status = c.Writer.Status()
+		case status == 0 && c.Writer.Status() >= 400:
+			status = c.Writer.Status()
+		case status == 0:
+			status = http.StatusInternalServerError
+		}
+
+		if !c.Writer.Written() {
+			c.Header(TraceHeader, oopsError.Trace())
+		}
+
+		c.JSON(status, envelope(oopsError, status))
+	}
+}
+
+func lastOopsError(errs []*gin.Error) (oops.OopsError, bool) {
+	for i := len(errs) - 1; i >= 0; i-- {
+		if oopsError, ok := oops.AsOops(errs[i].Err); ok {
+			return oopsError, true
+		}
+	}
+
+	return oops.OopsError{}, false
+}
+
+func defaultEnvelope(err oops.OopsError, status int) any {
+	return Response{
+		Error: oops.GetPublic(err, http.StatusText(status)),
+		Code:  err.Code(),
+		Trace: err.Trace(),
+	}
+}
@@ -0,0 +1,69 @@
+package oopsrecoverygin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGinOopsErrorResponder(t *testing.T) {
+	is := assert.New(t)
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(GinOopsErrorResponder())
+	router.Use(GinOopsRecovery())
+
+	router.GET("/oops", func(c *gin.Context) {
+		_ = c.Error(oops.
+			Code("invoice_not_found").
+			HTTPStatus(http.StatusNotFound).
+			Public("Invoice not found.").
+			Trace("1234").
+			Errorf("sql: no rows"))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	router.GET("/written", func(c *gin.Context) {
+		_ = c.Error(oops.Errorf("partial failure"))
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oops", nil))
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("1234", rec.Header().Get(TraceHeader))
+	is.JSONEq(`{"error":"Invoice not found.","code":"invoice_not_found","trace":"1234"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.Contains(rec.Body.String(), `"error":"Internal Server Error"`)
+	is.NotContains(rec.Body.String(), "boom")
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/written", nil))
+	is.Equal(http.StatusOK, rec.Code)
+	is.JSONEq(`{"ok":true}`, rec.Body.String())
+
+	// custom envelope
+	router = gin.New()
+	router.Use(GinOopsErrorResponderWith(ErrorResponderOptions{
+		Envelope: func(err oops.OopsError, status int) any {
+			return gin.H{"errors": []gin.H{{"message": err.Public(), "status": status}}}
+		},
+	}))
+	router.GET("/oops", func(c *gin.Context) {
+		_ = c.Error(oops.HTTPStatus(http.StatusConflict).Public("Already paid.").Errorf("invoice paid"))
+	})
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oops", nil))
+	is.Equal(http.StatusConflict, rec.Code)
+	is.JSONEq(`{"errors":[{"message":"Already paid.","status":409}]}`, rec.Body.String())
+}
@@ -0,0 +1,78 @@
+package oopsrecoveryhttp
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/samber/oops"
+)
+
+// Options configures the middleware.
+type Options struct {
+	// Logger is called with recovered errors. Defaults to `slog.Default()`.
+	Logger func(r *http.Request, err error)
+	// PublicMessage is written when the error has no public message.
+	// Defaults to the text of the http status.
+	PublicMessage string
+}
+
+// Middleware recovers panics into oops errors, with the request attached, logs
+// them with `slog.Default()` and writes the public message of the error with its
+// http status (default: 500).
+//
+//	http.ListenAndServe(":8080", oopsrecoveryhttp.Middleware(mux))
+func Middleware(next http.Handler) http.Handler {
+	return MiddlewareWith(Options{})(next)
+}
+
+// MiddlewareWith returns a recovery middleware with custom options.
+//
+//	recovery := oopsrecoveryhttp.MiddlewareWith(oopsrecoveryhttp.Options{
+//		Logger: func(r *http.Request, err error) {
+//			logger.Error(err.Error(), zap.Error(err))
+//		},
+//	})
+func MiddlewareWith(opts Options) func(http.Handler) http.Handler {
+	logger := opts.Logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := oops.
+				FromContext(r.Context()).
+				Request(r, false).
+				Recoverf(func() {
+					next.ServeHTTP(w, r)
+				}, "http: panic recovered")
+			if err == nil {
+				return
+			}
+
+			// let net/http abort the response silently
+			if errors.Is(err, http.ErrAbortHandler) {
+				panic(http.ErrAbortHandler)
+			}
+
+			logger(r, err)
+
+			status := http.StatusInternalServerError
+			if oopsError, ok := oops.AsOops(err); ok && oopsError.HTTPStatus() != 0 {
+				status = oopsError.HTTPStatus()
+			}
+
+			public := opts.PublicMessage
+			if public == "" {
+				public = http.StatusText(status)
+			}
+
+			http.Error(w, oops.GetPublic(err, public), status)
+		})
+	}
+}
+
+func defaultLogger(r *http.Request, err error) {
+	slog.Default().ErrorContext(r.Context(), err.Error(), slog.Any("error", err))
+}
@@ -0,0 +1,56 @@
+package oopsrecoveryhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	is := assert.New(t)
+
+	var logged error
+	handler := MiddlewareWith(Options{
+		Logger: func(r *http.Request, err error) {
+			logged = err
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/value":
+			panic("boom")
+		case "/oops":
+			panic(oops.HTTPStatus(http.StatusServiceUnavailable).Public("Please retry later.").Errorf("database down"))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	is.Equal(http.StatusNoContent, rec.Code)
+	is.Nil(logged)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/value", nil))
+	is.Equal(http.StatusInternalServerError, rec.Code)
+	is.Equal("Internal Server Error\n", rec.Body.String())
+	is.EqualError(logged, "http: panic recovered: boom")
+
+	oopsError, ok := oops.AsOops(logged)
+	is.True(ok)
+	is.Equal("/value", oopsError.Request().URL.Path)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oops", nil))
+	is.Equal(http.StatusServiceUnavailable, rec.Code)
+	is.Equal("Please retry later.\n", rec.Body.String())
+
+	is.PanicsWithValue(http.ErrAbortHandler, func() {
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,48 @@
+package oops
+
+import (
+	"strings"
+)
+
+// RedactedValue replaces the value of redacted keys in outputs.
+const RedactedValue = "[REDACTED]"
+
+// redactedKeys returns the lowercased keys to redact: the global ones and
+// the ones declared by any error of the chain.
+func (o OopsError) redactedKeys() map[string]struct{} {
+	keys := map[string]struct{}{}
+
+	for _, key := range RedactedKeys {
+		keys[strings.ToLower(key)] = struct{}{}
+	}
+
+	recursive(o, func(e OopsError) {
+		for _, key := range e.redactKeys {
+			keys[strings.ToLower(key)] = struct{}{}
+		}
+	})
+
+	return keys
+}
+
+// redactMap returns a copy of data, with the values of matching keys replaced
+// by RedactedValue. Nested maps are redacted too. Keys are case insensitive.
+func redactMap(data map[string]any, keys map[string]struct{}) map[string]any {
+	if len(keys) == 0 {
+		return data
+	}
+
+	output := make(map[string]any, len(data))
+
+	for k, v := range data {
+		if _, ok := keys[strings.ToLower(k)]; ok {
+			output[k] = RedactedValue
+		} else if nested, ok := v.(map[string]any); ok {
+			output[k] = redactMap(nested, keys)
+		} else {
+			output[k] = v
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,59 @@
+package oops
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactKeys(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().
+		RedactKeys("Password").
+		With("password", "hunter2", "user_id", 42).
+		WithGroup("db", "token", "abcd").
+		User("user-123", "email", "john@doe.org", "password", "hunter2").
+		Tenant("workspace-123", "token", "abcd").
+		Errorf("a message").(OopsError)
+
+	// getters are not redacted
+	is.Equal("hunter2", err.Context()["password"])
+
+	payload := err.ToMap()
+	is.Equal(map[string]any{"password": RedactedValue, "user_id": 42, "db": map[string]any{"token": "abcd"}}, payload["context"])
+	is.Equal(map[string]any{"id": "user-123", "email": "john@doe.org", "password": RedactedValue}, payload["user"])
+	is.Equal(map[string]any{"id": "workspace-123", "token": "abcd"}, payload["tenant"])
+
+	// global keys, merged with the keys of the wrapped errors
+	RedactedKeys = []string{"token"}
+	defer func() { RedactedKeys = []string{} }()
+
+	err = new().Wrap(err).(OopsError)
+
+	payload = err.ToMap()
+	is.Equal(map[string]any{"password": RedactedValue, "user_id": 42, "db": map[string]any{"token": RedactedValue}}, payload["context"])
+	is.Equal(map[string]any{"id": "workspace-123", "token": RedactedValue}, payload["tenant"])
+
+	got, jsonErr := json.Marshal(err)
+	is.NoError(jsonErr)
+	is.NotContains(string(got), "hunter2")
+	is.NotContains(string(got), "abcd")
+
+	attrs := lo.Associate(err.LogValuer().Group(), func(attr slog.Attr) (string, slog.Value) {
+		return attr.Key, attr.Value
+	})
+	is.NotContains(attrs["context"].String(), "hunter2")
+	is.NotContains(attrs["context"].String(), "abcd")
+	is.NotContains(attrs["user"].String(), "hunter2")
+	is.NotContains(attrs["tenant"].String(), "abcd")
+
+	verbose := fmt.Sprintf("%+v", err)
+	is.NotContains(verbose, "hunter2")
+	is.NotContains(verbose, "abcd")
+	is.Contains(verbose, "  * password: [REDACTED]\n")
+}
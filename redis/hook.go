@@ -0,0 +1,198 @@
+package oopsredis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/oops"
+)
+
+// Options configures the hook.
+type Options struct {
+	// KeyPattern returns the pattern of a key, reported in the `redis_key`
+	// attribute. Defaults to DefaultKeyPattern.
+	KeyPattern func(key string) string
+}
+
+// NewHook returns a go-redis hook wrapping command failures into oops errors,
+// tagged with `redis` and carrying the command name (`redis_command`), the key
+// pattern (`redis_key`), the key hash (`redis_key_hash`) and the latency of
+// the command (see `oops.OopsError.Duration()`).
+//
+//   - `redis.Nil` has the `not_found` code and is not retryable
+//   - connectivity errors (network, timeout, closed client, pool timeout) are
+//     tagged with `retryable` and are retryable
+//
+// The error of the command is replaced, so `redis.Nil` must be checked with
+// `errors.Is(err, redis.Nil)` instead of `err == redis.Nil`.
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	rdb.AddHook(oopsredis.NewHook())
+func NewHook() redis.Hook {
+	return NewHookWith(Options{})
+}
+
+// NewHookWith returns a go-redis hook with custom options.
+//
+//	rdb.AddHook(oopsredis.NewHookWith(oopsredis.Options{
+//		KeyPattern: func(key string) string {
+//			prefix, _, _ := strings.Cut(key, ":")
+//			return prefix + ":*"
+//		},
+//	}))
+func NewHookWith(opts Options) redis.Hook {
+	if opts.KeyPattern == nil {
+		opts.KeyPattern = DefaultKeyPattern
+	}
+
+	return &hook{opts: opts}
+}
+
+type hook struct {
+	opts Options
+}
+
+var _ redis.Hook = (*hook)(nil)
+
+func (h *hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		start := time.Now()
+
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			return nil, classify(oops.FromContext(ctx), err).
+				With("redis_addr", addr).
+				Since(start).
+				Wrap(err)
+		}
+
+		return conn, nil
+	}
+}
+
+func (h *hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+
+		if err := next(ctx, cmd); err != nil {
+			err = h.wrap(ctx, cmd, err, time.Since(start))
+			cmd.SetErr(err)
+			return err
+		}
+
+		return nil
+	}
+}
+
+func (h *hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+
+		err := next(ctx, cmds)
+		duration := time.Since(start)
+
+		for _, cmd := range cmds {
+			if cmdErr := cmd.Err(); cmdErr != nil {
+				cmd.SetErr(h.wrap(ctx, cmd, cmdErr, duration))
+			}
+		}
+
+		if err != nil {
+			return classify(oops.FromContext(ctx), err).
+				With("redis_command", "pipeline").
+				Duration(duration).
+				Wrap(err)
+		}
+
+		return nil
+	}
+}
+
+func (h *hook) wrap(ctx context.Context, cmd redis.Cmder, err error, duration time.Duration) error {
+	// errors of the dial hook are already wrapped
+	if _, ok := oops.AsOops(err); ok {
+		return oops.
+			FromContext(ctx).
+			With("redis_command", cmd.Name()).
+			Duration(duration).
+			Wrap(err)
+	}
+
+	builder := classify(oops.FromContext(ctx), err).
+		With("redis_command", cmd.Name()).
+		Duration(duration)
+
+	if key, ok := firstKey(cmd); ok {
+		builder = builder.
+			With("redis_key", h.opts.KeyPattern(key)).
+			With("redis_key_hash", hashKey(key))
+	}
+
+	return builder.Wrap(err)
+}
+
+func classify(builder oops.OopsErrorBuilder, err error) oops.OopsErrorBuilder {
+	builder = builder.Tags("redis")
+
+	switch {
+	case errors.Is(err, redis.Nil):
+		return builder.Code("not_found").Retryable(false)
+	case isConnectivityError(err):
+		return builder.Tags("retryable").Retryable(true)
+	}
+
+	return builder
+}
+
+func isConnectivityError(err error) bool {
+	var netErr net.Error
+
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, redis.ErrClosed) ||
+		// pool errors are not exported by go-redis
+		err.Error() == "redis: connection pool timeout"
+}
+
+// firstKey returns the first argument of the command, when it looks like a key.
+func firstKey(cmd redis.Cmder) (string, bool) {
+	args := cmd.Args()
+	if len(args) < 2 {
+		return "", false
+	}
+
+	key, ok := args[1].(string)
+	if !ok || key == "" || strings.ContainsFunc(key, unicode.IsSpace) {
+		return "", false
+	}
+
+	return key, true
+}
+
+// DefaultKeyPattern replaces the segments of a key (separated by `:`) that
+// contain a digit by `*` (eg: "user:1234:session" becomes "user:*:session").
+func DefaultKeyPattern(key string) string {
+	segments := strings.Split(key, ":")
+	for i, segment := range segments {
+		if strings.ContainsFunc(segment, unicode.IsDigit) {
+			segments[i] = "*"
+		}
+	}
+
+	return strings.Join(segments, ":")
+}
+
+// hashKey returns a short hash of the key, to correlate errors without leaking it.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,95 @@
+package oopsredis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultKeyPattern(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("user:*:session", DefaultKeyPattern("user:1234:session"))
+	is.Equal("config", DefaultKeyPattern("config"))
+	is.Equal("invoice:*", DefaultKeyPattern("invoice:01HZX3JQ9"))
+}
+
+func TestProcessHook(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := context.Background()
+	process := NewHook().ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		cmd.SetErr(redis.Nil)
+		return redis.Nil
+	})
+
+	cmd := redis.NewStringCmd(ctx, "get", "user:1234:session")
+	err := process(ctx, cmd)
+	is.Equal(err, cmd.Err())
+	is.True(errors.Is(cmd.Err(), redis.Nil))
+
+	oopsErr, ok := oops.AsOops(cmd.Err())
+	is.True(ok)
+	is.Equal("not_found", oopsErr.Code())
+	is.False(oopsErr.Retryable())
+	is.Equal([]string{"redis"}, oopsErr.Tags())
+	is.Equal("get", oopsErr.Context()["redis_command"])
+	is.Equal("user:*:session", oopsErr.Context()["redis_key"])
+	is.Equal(hashKey("user:1234:session"), oopsErr.Context()["redis_key_hash"])
+	is.NotContains(oopsErr.Error(), "1234")
+
+	process = NewHook().ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		return nil
+	})
+	cmd = redis.NewStringCmd(ctx, "get", "user:1234:session")
+	is.NoError(process(ctx, cmd))
+	is.NoError(cmd.Err())
+}
+
+func TestConnectivityError(t *testing.T) {
+	is := assert.New(t)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	defer rdb.Close()
+	rdb.AddHook(NewHook())
+
+	err := rdb.Get(context.Background(), "user:1234:session").Err()
+	is.Error(err)
+
+	oopsErr, ok := oops.AsOops(err)
+	is.True(ok)
+	is.True(oopsErr.Retryable())
+	is.Contains(oopsErr.Tags(), "redis")
+	is.Contains(oopsErr.Tags(), "retryable")
+	is.Equal("get", oopsErr.Context()["redis_command"])
+	is.Equal("127.0.0.1:1", oopsErr.Context()["redis_addr"])
+}
+
+func TestProcessPipelineHook(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := context.Background()
+	process := NewHook().ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		cmds[1].SetErr(redis.Nil)
+		return redis.Nil
+	})
+
+	cmds := []redis.Cmder{
+		redis.NewStatusCmd(ctx, "set", "user:1234:session", "abcd"),
+		redis.NewStringCmd(ctx, "get", "user:5678:session"),
+	}
+	err := process(ctx, cmds)
+	is.True(errors.Is(err, redis.Nil))
+	is.NoError(cmds[0].Err())
+	is.True(errors.Is(cmds[1].Err(), redis.Nil))
+	is.Equal("user:*:session", cmds[1].Err().(oops.OopsError).Context()["redis_key"])
+}
@@ -0,0 +1,282 @@
+package oops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+)
+
+// chainLayers returns the message and the attributes declared by each error of
+// the chain, the outermost first. See EmitChain.
+func (o OopsError) chainLayers() []map[string]any {
+	redactedKeys := o.redactedKeys()
+	chain := o.chain()
+
+	layers := make([]map[string]any, 0, len(chain))
+	for i, e := range chain {
+		layer := map[string]any{}
+
+		if e.msg != "" {
+			layer["message"] = truncateMessage(e.msg)
+		}
+
+		// the innermost oops error may wrap a regular error
+		if i == len(chain)-1 && e.err != nil {
+			layer["cause"] = truncateMessage(e.err.Error())
+		}
+
+		for key, value := range map[string]string{
+			"code":   e.code,
+			"domain": e.domain,
+			"trace":  e.trace,
+			"hint":   e.hint,
+			"public": e.public,
+			"owner":  e.owner,
+		} {
+			if value != "" {
+				layer[key] = value
+			}
+		}
+
+		if len(e.tags) > 0 {
+			layer["tags"] = e.tags
+		}
+
+		if e.time != (time.Time{}) {
+			layer["time"] = e.time.In(Local)
+		}
+
+		if own := e.ownContext(); len(own) > 0 {
+			context := dereferencePointers(lazyMapEvaluation(lo.Assign(map[string]any{}, own)))
+			layer["context"] = redactMap(context, redactedKeys)
+		}
+
+		layers = append(layers, layer)
+	}
+
+	return layers
+}
+
+// Replay rebuilds an error from its JSON representation, as produced by
+// `err.MarshalJSON()` or by the slog and zap integrations, for post-mortem
+// tooling. The error can be the log entry itself, or its "error" or "err"
+// attribute.
+//
+// When the entry was logged with `oops.EmitChain`, the wrapped errors are
+// rebuilt with their own message and attributes. Otherwise, the error has a
+// single layer carrying all attributes. Stacktraces, source fragments, http
+// dumps and goroutines are not rebuilt: they are returned as logged. Numbers
+// of context values are json.Number.
+//
+//	err, _ := oops.Replay(line)
+//	fmt.Println(err.Code(), err.Context())
+func Replay(data []byte) (OopsError, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var entry map[string]any
+	if err := decoder.Decode(&entry); err != nil {
+		return OopsError{}, fmt.Errorf("oops: replay: %w", err)
+	}
+
+	if entry == nil {
+		return OopsError{}, errors.New("oops: replay: not a JSON object")
+	}
+
+	payload := entry
+	for _, key := range []string{"error", "err"} {
+		if nested, ok := entry[key].(map[string]any); ok {
+			payload = nested
+			break
+		}
+	}
+
+	return replayPayload(payload), nil
+}
+
+func replayPayload(payload map[string]any) OopsError {
+	layers := lo.FilterMap(anySlice(payload["chain"]), func(item any, _ int) (map[string]any, bool) {
+		layer, ok := item.(map[string]any)
+		return layer, ok
+	})
+
+	if len(layers) == 0 {
+		layers = []map[string]any{replayFlatLayer(payload)}
+	}
+
+	// the chain is rebuilt from the innermost error
+	var err error
+	if cause := replayString(layers[len(layers)-1], "cause"); cause != "" {
+		err = errors.New(cause)
+	}
+
+	var replayed OopsError
+	for i := len(layers) - 1; i >= 0; i-- {
+		replayed = replayLayer(layers[i], err)
+		err = replayed
+	}
+
+	// attributes that are not declared per layer are set on the outermost error
+	replayed.severity = SeverityLevel(replayString(payload, "severity"))
+	replayed.span = replayString(payload, "span")
+	replayed.fingerprint = replayString(payload, "fingerprint")
+
+	switch d := payload["duration"].(type) {
+	case string:
+		replayed.duration, _ = time.ParseDuration(d)
+	case json.Number:
+		// nanoseconds, as logged by slog
+		n, _ := d.Int64()
+		replayed.duration = time.Duration(n)
+	}
+
+	if status, ok := payload["http_status"].(json.Number); ok {
+		n, _ := status.Int64()
+		replayed.httpStatus = int(n)
+	}
+
+	if retryable, ok := payload["retryable"].(bool); ok {
+		replayed.retryable = &retryable
+	}
+
+	if actions, ok := payload["actions"]; ok {
+		if b, err := json.Marshal(actions); err == nil {
+			_ = json.Unmarshal(b, &replayed.actions)
+		}
+	}
+
+	if id, ok := payload["goroutine_id"].(json.Number); ok {
+		n, _ := strconv.ParseUint(id.String(), 10, 64)
+		replayed.goroutineID = n
+	}
+
+	if labels, ok := payload["pprof_labels"].(map[string]any); ok {
+		replayed.pprofLabels = lo.MapValues(labels, func(v any, _ string) string {
+			return fmt.Sprint(v)
+		})
+	}
+
+	replayed.userID, replayed.userData = replayIdentity(payload["user"])
+	replayed.tenantID, replayed.tenantData = replayIdentity(payload["tenant"])
+
+	replayed.replayed = &replayedOutputs{
+		stacktrace: replayString(payload, "stacktrace"),
+		sources:    replayString(payload, "sources"),
+		request:    replayString(payload, "request"),
+		response:   replayString(payload, "response"),
+		goroutines: replayString(payload, "goroutines"),
+	}
+
+	if integrationErrors := replayString(payload, integrationErrorKey); integrationErrors != "" {
+		replayed.replayed.integrationErrors = strings.Split(integrationErrors, "; ")
+	}
+
+	return replayed
+}
+
+// replayedOutputs holds the outputs of a replayed error that cannot be rebuilt
+// from their text.
+type replayedOutputs struct {
+	stacktrace        string
+	sources           string
+	request           string
+	response          string
+	goroutines        string
+	integrationErrors []string
+}
+
+// replayedOutputs returns the logged outputs of a replayed error, or nil.
+func (o OopsError) replayedOutputs() *replayedOutputs {
+	return getErrorAttribute(
+		o,
+		func(e OopsError) *replayedOutputs {
+			return e.replayed
+		},
+	)
+}
+
+// replayFlatLayer extracts the layer of an error logged without its chain. The
+// wrapped error is split from the message when both were logged (slog).
+func replayFlatLayer(payload map[string]any) map[string]any {
+	layer := lo.PickByKeys(payload, []string{"code", "domain", "trace", "hint", "public", "owner", "tags", "time", "context"})
+
+	full := replayString(payload, "error")
+	if full == "" {
+		full = replayString(payload, "err")
+	}
+
+	message := replayString(payload, "message")
+	switch {
+	case message == "":
+		layer["message"] = full
+	case strings.HasPrefix(full, message+": "):
+		layer["message"] = message
+		layer["cause"] = strings.TrimPrefix(full, message+": ")
+	default:
+		layer["message"] = message
+	}
+
+	return layer
+}
+
+func replayLayer(layer map[string]any, err error) OopsError {
+	replayed := OopsError{
+		err:     err,
+		msg:     replayString(layer, "message"),
+		code:    replayString(layer, "code"),
+		domain:  replayString(layer, "domain"),
+		trace:   replayString(layer, "trace"),
+		hint:    replayString(layer, "hint"),
+		public:  replayString(layer, "public"),
+		owner:   replayString(layer, "owner"),
+		context: map[string]any{},
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, replayString(layer, "time")); err == nil {
+		replayed.time = t
+	}
+
+	for _, tag := range anySlice(layer["tags"]) {
+		if s, ok := tag.(string); ok {
+			replayed.tags = append(replayed.tags, s)
+		}
+	}
+
+	if context, ok := layer["context"].(map[string]any); ok {
+		replayed.context = context
+	}
+
+	return replayed
+}
+
+func replayIdentity(v any) (string, map[string]any) {
+	data, ok := v.(map[string]any)
+	if !ok {
+		return "", map[string]any{}
+	}
+
+	data = lo.Assign(map[string]any{}, data)
+	id := replayString(data, "id")
+	delete(data, "id")
+
+	return id, data
+}
+
+func replayString(payload map[string]any, key string) string {
+	if s, ok := payload[key].(string); ok {
+		return s
+	}
+
+	return ""
+}
+
+func anySlice(v any) []any {
+	items, _ := v.([]any)
+	return items
+}
@@ -0,0 +1,143 @@
+package oops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayChain(t *testing.T) {
+	is := assert.New(t)
+
+	EmitChain = true
+	EmitFingerprint = true
+	defer func() {
+		EmitChain = false
+		EmitFingerprint = false
+	}()
+
+	inner := new().
+		In("database").
+		Tags("sql").
+		With("query", "users.find").
+		Wrapf(errors.New("connection refused"), "could not fetch user")
+	outer := new().
+		Code("iam_unavailable").
+		Trace("1234").
+		Hint("retry later").
+		HTTPStatus(503).
+		With("user_id", 42).
+		User("user-123", "email", "john@example.com").
+		Wrapf(inner, "request failed").(OopsError)
+
+	data, err := outer.MarshalJSON()
+	is.NoError(err)
+
+	var payload map[string]any
+	is.NoError(json.Unmarshal(data, &payload))
+	is.Len(payload["chain"], 2)
+
+	replayed, err := Replay(data)
+	is.NoError(err)
+	is.Equal("request failed: could not fetch user: connection refused", replayed.Error())
+	is.Equal("iam_unavailable", replayed.Code())
+	is.Equal("database", replayed.Domain())
+	is.Equal([]string{"sql"}, replayed.Tags())
+	is.Equal("1234", replayed.Trace())
+	is.Equal("retry later", replayed.Hint())
+	is.Equal(503, replayed.HTTPStatus())
+	is.Equal(outer.Fingerprint(), replayed.Fingerprint())
+	is.Equal(map[string]any{"user_id": json.Number("42"), "query": "users.find"}, replayed.Context())
+	is.True(outer.Time().Equal(replayed.Time()))
+
+	userID, userData := replayed.User()
+	is.Equal("user-123", userID)
+	is.Equal(map[string]any{"email": "john@example.com"}, userData)
+
+	// per-level message and attributes
+	layer, ok := AsOops(replayed.Unwrap())
+	is.True(ok)
+	is.Equal("could not fetch user", layer.msg)
+	is.Equal(map[string]any{"query": "users.find"}, layer.context)
+	is.Equal("", layer.code)
+	is.EqualError(layer.Unwrap(), "connection refused")
+	_, ok = AsOops(layer.Unwrap())
+	is.False(ok)
+
+	// slog
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error(outer.Error(), slog.Any("error", outer.LogValuer()))
+
+	replayed, err = Replay(buf.Bytes())
+	is.NoError(err)
+	is.Equal(outer.Error(), replayed.Error())
+	layer, ok = AsOops(replayed.Unwrap())
+	is.True(ok)
+	is.Equal("could not fetch user", layer.msg)
+}
+
+func TestReplaySlog(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().
+		Code("iam_missing_permission").
+		Trace("1234").
+		With("user_id", 42).
+		Wrapf(errors.New("permission denied"), "could not fetch user")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error(err.Error(), slog.Any("error", err.(OopsError).LogValuer()))
+
+	replayed, e := Replay(buf.Bytes())
+	is.NoError(e)
+	is.Equal("could not fetch user: permission denied", replayed.Error())
+	is.Equal("could not fetch user", replayed.msg)
+	is.EqualError(replayed.Unwrap(), "permission denied")
+	is.Equal("iam_missing_permission", replayed.Code())
+	is.Equal("1234", replayed.Trace())
+	is.Equal(map[string]any{"user_id": json.Number("42")}, replayed.Context())
+
+	_, e = Replay([]byte(`not json`))
+	is.Error(e)
+	_, e = Replay([]byte(`null`))
+	is.EqualError(e, "oops: replay: not a JSON object")
+}
+
+func TestReplayLoggedOutputs(t *testing.T) {
+	is := assert.New(t)
+
+	data := []byte(`{
+		"error": "permission denied",
+		"stacktrace": "Oops: permission denied\n  --- at main.go:42 main()",
+		"sources": "main.go:42 main()\n42\treturn oops.Errorf(\"permission denied\")\n\t^^^^^^^^^^^",
+		"request": "GET /invoices HTTP/1.1\r\nHost: localhost",
+		"goroutine_id": 18,
+		"pprof_labels": {"route": "/invoices"},
+		"oops_integration_error": "response dump: EOF; context marshaling: unsupported type"
+	}`)
+
+	replayed, err := Replay(data)
+	is.NoError(err)
+	is.Equal("Oops: permission denied\n  --- at main.go:42 main()", replayed.Stacktrace())
+	is.Contains(replayed.Sources(), "^^^^^^^^^^^")
+	is.Equal(uint64(18), replayed.GoroutineID())
+	is.Equal(map[string]string{"route": "/invoices"}, replayed.PprofLabels())
+
+	// no trace is generated for errors logged without trace
+	is.Empty(replayed.Trace())
+
+	payload := replayed.ToMap()
+	is.Equal("GET /invoices HTTP/1.1\r\nHost: localhost", payload["request"])
+	is.NotContains(payload, "response")
+	is.Equal("response dump: EOF; context marshaling: unsupported type", payload["oops_integration_error"])
+
+	output := replayed.FormatWith(FormatterOptions{})
+	is.Contains(output, "Integration errors:\n  * response dump: EOF\n  * context marshaling: unsupported type\n")
+	is.Contains(output, "Request:\n  * GET /invoices HTTP/1.1\r\n")
+}
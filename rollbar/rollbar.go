@@ -0,0 +1,204 @@
+package oopsrollbar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/samber/oops"
+)
+
+// DefaultEndpoint is the Rollbar item API.
+const DefaultEndpoint = "https://api.rollbar.com/api/1/item/"
+
+// Client reports errors to Rollbar.
+type Client struct {
+	// Token is a Rollbar access token with the `post_server_item` scope.
+	Token string
+	// Environment of the items (eg: "production").
+	Environment string
+	// CodeVersion is the version of the application (eg: a git sha).
+	CodeVersion string
+	// Endpoint defaults to DefaultEndpoint.
+	Endpoint string
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// DefaultClient is used by Report.
+var DefaultClient = &Client{}
+
+// Report sends err to Rollbar with DefaultClient.
+//
+//	oopsrollbar.DefaultClient.Token = os.Getenv("ROLLBAR_TOKEN")
+//	oopsrollbar.DefaultClient.Environment = "production"
+//
+//	_ = oopsrollbar.Report(err)
+func Report(err error) error {
+	return DefaultClient.Report(context.Background(), err)
+}
+
+// Report sends err to Rollbar.
+func (c *Client) Report(ctx context.Context, err error) error {
+	data := ToItem(err)
+	if c.Environment != "" {
+		data["environment"] = c.Environment
+	}
+	if c.CodeVersion != "" {
+		data["code_version"] = c.CodeVersion
+	}
+
+	body, e := json.Marshal(map[string]any{
+		"access_token": c.Token,
+		"data":         data,
+	})
+	if e != nil {
+		return fmt.Errorf("oopsrollbar: %w", e)
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if e != nil {
+		return fmt.Errorf("oopsrollbar: %w", e)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Rollbar-Access-Token", c.Token)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, e := httpClient.Do(req)
+	if e != nil {
+		return fmt.Errorf("oopsrollbar: %w", e)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("oopsrollbar: unexpected status %d: %s", res.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// ToItem returns the `data` object of a Rollbar item:
+//   - body: exception and stack frames of the deepest error
+//   - level: severity of the error
+//   - person: user id, and `username` and `email` user attributes
+//   - custom: other attributes of the error
+//   - fingerprint: `err.Fingerprint()`, overridable with `oops.Fingerprint()`
+//
+// Values of redacted keys are not exported (see `oops.RedactKeys`).
+func ToItem(err error) map[string]any {
+	data := map[string]any{
+		"language": "go",
+		"platform": "go",
+		"notifier": map[string]any{"name": "oopsrollbar"},
+	}
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		data["level"] = "error"
+		data["body"] = map[string]any{
+			"trace": map[string]any{
+				"frames":    []any{},
+				"exception": map[string]any{"class": fmt.Sprintf("%T", err), "message": err.Error()},
+			},
+		}
+		return data
+	}
+
+	class := oopsError.Code()
+	if class == "" {
+		class = "oops.OopsError"
+	}
+
+	data["level"] = level(oopsError.Severity())
+	data["timestamp"] = oopsError.Time().Unix()
+	data["fingerprint"] = oopsError.Fingerprint()
+	data["title"] = oopsError.Error()
+	data["body"] = map[string]any{
+		"trace": map[string]any{
+			"frames":    frames(oopsError),
+			"exception": map[string]any{"class": class, "message": oopsError.Error()},
+		},
+	}
+
+	if domain := oopsError.Domain(); domain != "" {
+		data["context"] = domain
+	}
+
+	payload := oopsError.ToMapWith(oops.ToMapOptions{
+		Exclude: []string{"error", "time", "severity", "fingerprint", "stacktrace", "sources"},
+	})
+
+	if user, ok := payload["user"].(map[string]any); ok {
+		person := map[string]any{}
+		for _, key := range []string{"id", "username", "email"} {
+			if v, ok := user[key]; ok {
+				person[key] = v
+			}
+		}
+		data["person"] = person
+		delete(payload, "user")
+	}
+
+	if len(payload) > 0 {
+		data["custom"] = payload
+	}
+
+	return data
+}
+
+// frames returns the frames of the deepest error, most recent call last.
+func frames(err oops.OopsError) []any {
+	output := []any{}
+
+	for current := error(err); current != nil; current = errors.Unwrap(current) {
+		e, ok := current.(oops.OopsError)
+		if !ok {
+			break
+		}
+
+		stackFrames := e.StackFrames()
+		if len(stackFrames) == 0 {
+			continue
+		}
+
+		output = make([]any, 0, len(stackFrames))
+		for i := len(stackFrames) - 1; i >= 0; i-- {
+			output = append(output, map[string]any{
+				"filename": stackFrames[i].File,
+				"lineno":   stackFrames[i].Line,
+				"method":   stackFrames[i].Function,
+			})
+		}
+	}
+
+	return output
+}
+
+func level(severity oops.SeverityLevel) string {
+	switch severity {
+	case oops.SeverityDebug:
+		return "debug"
+	case oops.SeverityInfo:
+		return "info"
+	case oops.SeverityWarning:
+		return "warning"
+	case oops.SeverityFatal:
+		return "critical"
+	default:
+		return "error"
+	}
+}
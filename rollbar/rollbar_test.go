@@ -0,0 +1,78 @@
+package oopsrollbar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToItem(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("iam_missing_permission").
+		In("authz").
+		Severity(oops.SeverityWarning).
+		Fingerprint("iam-permission").
+		With("permission", "post.create").
+		User("user-123", "email", "john@example.com", "firstname", "john").
+		Errorf("permission denied")
+
+	item := ToItem(err)
+	is.Equal("warning", item["level"])
+	is.Equal("iam-permission", item["fingerprint"])
+	is.Equal("authz", item["context"])
+	is.Equal(map[string]any{"id": "user-123", "email": "john@example.com"}, item["person"])
+
+	custom := item["custom"].(map[string]any)
+	is.Equal("iam_missing_permission", custom["code"])
+	is.Equal(map[string]any{"permission": "post.create"}, custom["context"])
+	is.NotContains(custom, "user")
+	is.NotContains(custom, "stacktrace")
+
+	trace := item["body"].(map[string]any)["trace"].(map[string]any)
+	is.Equal(map[string]any{"class": "iam_missing_permission", "message": "permission denied"}, trace["exception"])
+	is.NotEmpty(trace["frames"])
+
+	item = ToItem(errors.New("secret"))
+	is.Equal("error", item["level"])
+	is.NotContains(item, "person")
+}
+
+func TestClientReport(t *testing.T) {
+	is := assert.New(t)
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		is.Equal("token", r.Header.Get("X-Rollbar-Access-Token"))
+		body, _ := io.ReadAll(r.Body)
+		is.NoError(json.Unmarshal(body, &received))
+
+		if received["data"].(map[string]any)["environment"] == "staging" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"err":1}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{Token: "token", Environment: "production", CodeVersion: "abcd", Endpoint: server.URL}
+
+	err := oops.Code("iam_missing_permission").Errorf("permission denied")
+	is.NoError(client.Report(context.Background(), err))
+	is.Equal("token", received["access_token"])
+
+	data := received["data"].(map[string]any)
+	is.Equal("production", data["environment"])
+	is.Equal("abcd", data["code_version"])
+	is.Equal("permission denied", data["title"])
+
+	client.Environment = "staging"
+	is.EqualError(client.Report(context.Background(), err), `oopsrollbar: unexpected status 422: {"err":1}`)
+}
@@ -0,0 +1,13 @@
+package oops
+
+// SeverityLevel describes how critical an error is.
+type SeverityLevel string
+
+const (
+	SeverityUnknown SeverityLevel = ""
+	SeverityDebug   SeverityLevel = "debug"
+	SeverityInfo    SeverityLevel = "info"
+	SeverityWarning SeverityLevel = "warning"
+	SeverityError   SeverityLevel = "error"
+	SeverityFatal   SeverityLevel = "fatal"
+)
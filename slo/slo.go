@@ -0,0 +1,243 @@
+package oopsslo
+
+import (
+	"expvar"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+// buckets is the resolution of the rolling window of an SLO.
+const buckets = 720
+
+// SLO is an error budget: at most Budget errors of a domain and/or a code
+// over a rolling Window.
+type SLO struct {
+	// Name identifies the SLO in the statuses.
+	Name string
+	// Domain and Code select the errors counted by the SLO. Empty means any.
+	Domain string
+	Code   string
+	// Budget is the number of errors allowed over Window.
+	Budget int64
+	// Window defaults to 30 days.
+	Window time.Duration
+	// BurnRateWindow is the period of the burn rate. Defaults to 1 hour.
+	BurnRateWindow time.Duration
+}
+
+// Status is the error budget consumption of an SLO.
+type Status struct {
+	Name string `json:"name"`
+	// Errors is the number of errors over the window.
+	Errors int64 `json:"errors"`
+	// Total is the number of errors since the registration of the SLO.
+	Total int64 `json:"total"`
+	// Remaining is the number of errors left in the budget (negative when exhausted).
+	Remaining int64 `json:"remaining"`
+	// Consumed is the ratio of the budget consumed over the window (1 = exhausted).
+	Consumed float64 `json:"consumed"`
+	// BurnRate is the rate of errors over the burn rate window, relative to the
+	// rate allowed by the budget. At 1, the budget is exhausted at the end of
+	// the window. At 14.4 over 1 hour, 2% of a 30 days budget is burnt in an hour.
+	BurnRate float64 `json:"burn_rate"`
+}
+
+type tracked struct {
+	slo     SLO
+	step    time.Duration
+	counts  [buckets]int64
+	current int64 // index of the current bucket, since epoch
+	total   int64
+}
+
+// Tracker counts oops errors against SLOs.
+type Tracker struct {
+	mutex     sync.Mutex
+	slos      []*tracked
+	callbacks []func(Status)
+	now       func() time.Time
+}
+
+// NewTracker returns an empty tracker.
+//
+// Errors are counted on creation when `tracker.Hook` is registered with
+// `oops.OnError`, or by calling `tracker.Observe`.
+//
+//	tracker := oopsslo.NewTracker()
+//	tracker.Register(oopsslo.SLO{Name: "payment", Domain: "payment", Budget: 1000})
+//	tracker.Publish("oops_slo")
+//	oops.OnError(tracker.Hook)
+func NewTracker() *Tracker {
+	return &Tracker{
+		now: time.Now,
+	}
+}
+
+// Register adds an SLO. It panics if the name is already registered.
+func (t *Tracker) Register(slo SLO) {
+	if slo.Window <= 0 {
+		slo.Window = 30 * 24 * time.Hour
+	}
+	if slo.BurnRateWindow <= 0 {
+		slo.BurnRateWindow = time.Hour
+	}
+
+	step := slo.Window / buckets
+	if step <= 0 {
+		step = 1
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, s := range t.slos {
+		if s.slo.Name == slo.Name {
+			panic(fmt.Sprintf("oopsslo: SLO %q registered twice", slo.Name))
+		}
+	}
+
+	t.slos = append(t.slos, &tracked{
+		slo:     slo,
+		step:    step,
+		current: t.now().UnixNano() / int64(step),
+	})
+}
+
+// OnUpdate registers a callback, invoked with the status of an SLO each time
+// an error is counted against it, to export burn rates to a metrics backend.
+//
+//	tracker.OnUpdate(func(status oopsslo.Status) {
+//		burnRate.WithLabelValues(status.Name).Set(status.BurnRate)
+//	})
+func (t *Tracker) OnUpdate(callback func(Status)) {
+	t.mutex.Lock()
+	t.callbacks = append(t.callbacks, callback)
+	t.mutex.Unlock()
+}
+
+// Hook counts errors on creation. It must be registered with `oops.OnError`.
+// Errors wrapping another oops error are not counted twice.
+func (t *Tracker) Hook(err oops.OopsError) oops.OopsError {
+	if _, wrapped := oops.AsOops(err.Unwrap()); !wrapped {
+		t.Observe(err)
+	}
+
+	return err
+}
+
+// Observe counts an error against the matching SLOs. Non-oops errors are ignored.
+func (t *Tracker) Observe(err error) {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return
+	}
+
+	domain := oopsError.Domain()
+	code := oopsError.Code()
+
+	t.mutex.Lock()
+	now := t.now()
+	statuses := []Status{}
+	for _, s := range t.slos {
+		if (s.slo.Domain != "" && s.slo.Domain != domain) || (s.slo.Code != "" && s.slo.Code != code) {
+			continue
+		}
+
+		s.advance(now)
+		s.counts[s.current%buckets]++
+		s.total++
+		statuses = append(statuses, s.status())
+	}
+	callbacks := t.callbacks
+	t.mutex.Unlock()
+
+	for _, status := range statuses {
+		for _, callback := range callbacks {
+			callback(status)
+		}
+	}
+}
+
+// Statuses returns the status of the SLOs, sorted by name.
+func (t *Tracker) Statuses() []Status {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	statuses := make([]Status, 0, len(t.slos))
+	for _, s := range t.slos {
+		s.advance(now)
+		statuses = append(statuses, s.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses
+}
+
+// Publish exposes the statuses as an expvar variable (served on /debug/vars by
+// the expvar package). It panics if the name is already published.
+func (t *Tracker) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		output := map[string]Status{}
+		for _, status := range t.Statuses() {
+			output[status.Name] = status
+		}
+		return output
+	}))
+}
+
+// advance moves the window to now, resetting the expired buckets.
+func (s *tracked) advance(now time.Time) {
+	current := now.UnixNano() / int64(s.step)
+	if current <= s.current {
+		return
+	}
+
+	for i := s.current + 1; i <= current && i <= s.current+buckets; i++ {
+		s.counts[i%buckets] = 0
+	}
+
+	s.current = current
+}
+
+func (s *tracked) status() Status {
+	var errors int64
+	for _, count := range s.counts {
+		errors += count
+	}
+
+	// burn rate window, rounded to buckets
+	n := int64(s.slo.BurnRateWindow / s.step)
+	if n < 1 {
+		n = 1
+	}
+	if n > buckets {
+		n = buckets
+	}
+
+	var recent int64
+	for i := int64(0); i < n; i++ {
+		recent += s.counts[(s.current-i)%buckets]
+	}
+
+	status := Status{
+		Name:      s.slo.Name,
+		Errors:    errors,
+		Total:     s.total,
+		Remaining: s.slo.Budget - errors,
+	}
+
+	if s.slo.Budget > 0 {
+		status.Consumed = float64(errors) / float64(s.slo.Budget)
+		status.BurnRate = float64(recent) / float64(n) * buckets / float64(s.slo.Budget)
+	}
+
+	return status
+}
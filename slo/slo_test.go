@@ -0,0 +1,79 @@
+package oopsslo
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	is := assert.New(t)
+
+	now := time.Date(2023, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	tracker := NewTracker()
+	tracker.now = func() time.Time { return now }
+	tracker.Register(SLO{Name: "payment", Domain: "payment", Budget: 720, Window: 720 * time.Hour})
+	tracker.Register(SLO{Name: "card_declined", Domain: "payment", Code: "card_declined", Budget: 10, Window: 720 * time.Hour})
+	is.Panics(func() {
+		tracker.Register(SLO{Name: "payment"})
+	})
+
+	updates := []Status{}
+	tracker.OnUpdate(func(status Status) {
+		updates = append(updates, status)
+	})
+
+	for i := 0; i < 2; i++ {
+		tracker.Observe(oops.In("payment").Code("card_declined").Errorf("card declined"))
+	}
+	tracker.Observe(oops.In("payment").Errorf("timeout"))
+	tracker.Observe(oops.In("auth").Errorf("permission denied"))
+	tracker.Observe(assert.AnError)
+
+	is.Len(updates, 5)
+	is.Equal([]Status{
+		{Name: "card_declined", Errors: 2, Total: 2, Remaining: 8, Consumed: 0.2, BurnRate: 144},
+		{Name: "payment", Errors: 3, Total: 3, Remaining: 717, Consumed: 3.0 / 720, BurnRate: 3},
+	}, tracker.Statuses())
+
+	// the burn rate window has passed
+	now = now.Add(2 * time.Hour)
+	is.Equal(Status{Name: "payment", Errors: 3, Total: 3, Remaining: 717, Consumed: 3.0 / 720}, tracker.Statuses()[1])
+
+	// the window has passed
+	now = now.Add(720 * time.Hour)
+	is.Equal(Status{Name: "payment", Errors: 0, Total: 3, Remaining: 720}, tracker.Statuses()[1])
+}
+
+func TestTrackerHook(t *testing.T) {
+	is := assert.New(t)
+
+	tracker := NewTracker()
+	tracker.Register(SLO{Name: "payment", Domain: "payment", Budget: 100})
+
+	err := oops.In("payment").Errorf("timeout")
+	tracker.Hook(err.(oops.OopsError))
+
+	// wrapping does not count the error twice
+	tracker.Hook(oops.Wrapf(err, "charge").(oops.OopsError))
+	is.Equal(int64(1), tracker.Statuses()[0].Errors)
+}
+
+func TestTrackerPublish(t *testing.T) {
+	is := assert.New(t)
+
+	tracker := NewTracker()
+	tracker.Register(SLO{Name: "payment", Domain: "payment", Budget: 100})
+	tracker.Publish("oops_slo_test")
+	tracker.Observe(oops.In("payment").Errorf("timeout"))
+
+	var output map[string]Status
+	is.NoError(json.Unmarshal([]byte(expvar.Get("oops_slo_test").String()), &output))
+	is.Equal(int64(1), output["payment"].Errors)
+	is.Equal(int64(99), output["payment"].Remaining)
+}
@@ -1,6 +1,7 @@
 package oops
 
 import (
+	"container/list"
 	"fmt"
 	"os"
 	"strings"
@@ -9,75 +10,131 @@ import (
 	"github.com/samber/lo"
 )
 
-var mutex sync.RWMutex
-var cache = map[string][]string{}
+var (
+	// SourceCacheMaxFiles limits the number of source files kept in memory for
+	// source fragments. Least recently used files are evicted first.
+	SourceCacheMaxFiles = 100
+	// SourceCacheMaxBytes limits the size of the source files kept in memory for
+	// source fragments. Least recently used files are evicted first.
+	SourceCacheMaxBytes = 8 << 20
+)
+
+type sourceCacheEntry struct {
+	path  string
+	lines []string
+	size  int
+}
+
+var mutex sync.Mutex
+var cache = map[string]*list.Element{} // path -> *sourceCacheEntry
+var cacheLRU = list.New()              // most recently used first
+var cacheSize = 0
 
 const nbrLinesBefore = 5
 const nbrLinesAfter = 5
 
-func readFile(path string) ([]string, bool) {
-	mutex.RLock()
-	lines, ok := cache[path]
-	mutex.RUnlock()
+// ClearSourceCache empties the cache of the source files read for source fragments.
+func ClearSourceCache() {
+	mutex.Lock()
+	defer mutex.Unlock()
 
-	if ok {
+	cache = map[string]*list.Element{}
+	cacheLRU.Init()
+	cacheSize = 0
+}
+
+func readFile(path string) ([]string, bool) {
+	mutex.Lock()
+	if elem, ok := cache[path]; ok {
+		cacheLRU.MoveToFront(elem)
+		lines := elem.Value.(*sourceCacheEntry).lines
+		mutex.Unlock()
 		return lines, true
 	}
+	mutex.Unlock()
 
 	if !strings.HasSuffix(path, ".go") {
 		return nil, false
 	}
 
 	// bearer:disable go_gosec_filesystem_filereadtaint
-	b, err := os.ReadFile(path)
+	b, err := os.ReadFile(sourcePath(path))
 	if err != nil {
 		return nil, false
 	}
 
-	lines = strings.Split(string(b), "\n")
+	lines := strings.Split(string(b), "\n")
 
 	mutex.Lock()
-	cache[path] = lines
-	mutex.Unlock()
+	defer mutex.Unlock()
+
+	if _, ok := cache[path]; !ok {
+		cache[path] = cacheLRU.PushFront(&sourceCacheEntry{path: path, lines: lines, size: len(b)})
+		cacheSize += len(b)
+		evictSourceCache()
+	}
 
 	return lines, true
 }
 
+// evictSourceCache removes the least recently used files, until the cache fits
+// the limits. The mutex must be held.
+func evictSourceCache() {
+	for cacheLRU.Len() > 0 && (cacheLRU.Len() > SourceCacheMaxFiles || cacheSize > SourceCacheMaxBytes) {
+		entry := cacheLRU.Remove(cacheLRU.Back()).(*sourceCacheEntry)
+		delete(cache, entry.path)
+		cacheSize -= entry.size
+	}
+}
+
 func getSourceFromFrame(frame oopsStacktraceFrame) []string {
-	lines, ok := readFile(frame.file)
+	output := []string{}
+
+	for _, line := range getSourceLines(frame.file, frame.line) {
+		message := fmt.Sprintf("%d\t%s", line.Number, line.Code)
+		output = append(output, message)
+
+		if line.Current {
+			lenWithoutLeadingSpaces := len(strings.TrimLeft(line.Code, " \t"))
+			lenLeadingSpaces := len(line.Code) - lenWithoutLeadingSpaces
+			nbrTabs := strings.Count(line.Code[0:lenLeadingSpaces], "\t")
+			firstCharIndex := lenLeadingSpaces + (8-1)*nbrTabs // 8 chars per tab
+
+			sublinePrefix := string(lo.RepeatBy(firstCharIndex, func(_ int) byte { return ' ' }))
+			subline := string(lo.RepeatBy(lenWithoutLeadingSpaces, func(_ int) byte { return '^' }))
+			output = append(output, "\t"+sublinePrefix+subline)
+		}
+	}
+
+	return output
+}
+
+func getSourceLines(file string, lineNumber int) []SourceLine {
+	lines, ok := readFile(file)
 	if !ok {
-		return []string{}
+		return []SourceLine{}
 	}
 
-	if len(lines) < frame.line {
-		return []string{}
+	if len(lines) < lineNumber {
+		return []SourceLine{}
 	}
 
-	current := frame.line - 1
+	current := lineNumber - 1
 	start := lo.Max([]int{0, current - nbrLinesBefore})
 	end := lo.Min([]int{len(lines) - 1, current + nbrLinesAfter})
 
-	output := []string{}
+	output := []SourceLine{}
 
 	for i := start; i <= end; i++ {
 		if i < 0 || i >= len(lines) {
 			continue
 		}
 
-		line := lines[i]
-		message := fmt.Sprintf("%d\t%s", i+1, line)
-		output = append(output, message)
-
-		if i == current {
-			lenWithoutLeadingSpaces := len(strings.TrimLeft(line, " \t"))
-			lenLeadingSpaces := len(line) - lenWithoutLeadingSpaces
-			nbrTabs := strings.Count(line[0:lenLeadingSpaces], "\t")
-			firstCharIndex := lenLeadingSpaces + (8-1)*nbrTabs // 8 chars per tab
-
-			sublinePrefix := string(lo.RepeatBy(firstCharIndex, func(_ int) byte { return ' ' }))
-			subline := string(lo.RepeatBy(lenWithoutLeadingSpaces, func(_ int) byte { return '^' }))
-			output = append(output, "\t"+sublinePrefix+subline)
-		}
+		output = append(output, SourceLine{
+			Number:  i + 1,
+			Code:    lines[i],
+			Current: i == current,
+		})
 	}
 
 	return output
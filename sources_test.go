@@ -0,0 +1,59 @@
+package oops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceCache(t *testing.T) {
+	is := assert.New(t)
+
+	dir := t.TempDir()
+	paths := []string{}
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		path := filepath.Join(dir, name)
+		is.NoError(os.WriteFile(path, []byte("package main\n\nfunc main() {}\n"), 0o600))
+		paths = append(paths, path)
+	}
+
+	previousFiles, previousBytes := SourceCacheMaxFiles, SourceCacheMaxBytes
+	defer func() {
+		SourceCacheMaxFiles, SourceCacheMaxBytes = previousFiles, previousBytes
+		ClearSourceCache()
+	}()
+
+	ClearSourceCache()
+	SourceCacheMaxFiles = 2
+
+	for _, path := range paths[:2] {
+		lines, ok := readFile(path)
+		is.True(ok)
+		is.Equal("func main() {}", lines[2])
+	}
+
+	// a.go becomes the most recently used file: b.go is evicted
+	_, _ = readFile(paths[0])
+	_, _ = readFile(paths[2])
+	is.Len(cache, 2)
+	is.Contains(cache, paths[0])
+	is.NotContains(cache, paths[1])
+	is.Contains(cache, paths[2])
+	is.Equal(2*29, cacheSize)
+
+	SourceCacheMaxBytes = 30
+	_, _ = readFile(paths[1])
+	is.Len(cache, 1)
+	is.Contains(cache, paths[1])
+	is.Equal(29, cacheSize)
+
+	ClearSourceCache()
+	is.Empty(cache)
+	is.Zero(cacheSize)
+
+	_, ok := readFile(filepath.Join(dir, "missing.go"))
+	is.False(ok)
+	is.Empty(cache)
+}
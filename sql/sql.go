@@ -0,0 +1,111 @@
+package oopssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/samber/oops"
+)
+
+var (
+	// NotFoundErrors are classified as `not_found` (eg: append `pgx.ErrNoRows`).
+	NotFoundErrors = []error{sql.ErrNoRows}
+
+	// Codes maps SQLSTATE codes to error codes. Unknown SQLSTATE codes are
+	// reported as is, in the `sql_state` attribute.
+	Codes = map[string]string{
+		"23502": "not_null_violation",
+		"23503": "foreign_key_violation",
+		"23505": "unique_violation",
+		"23514": "check_violation",
+		"40001": "serialization_failure",
+		"40P01": "deadlock_detected",
+		"53300": "too_many_connections",
+		"57014": "query_canceled",
+	}
+)
+
+// sqlStateError is implemented by `pgconn.PgError` (pgx) and `pq.Error` (lib/pq).
+type sqlStateError interface {
+	SQLState() string
+}
+
+// Classify returns a builder tagged with `sql`, with the code, the SQLSTATE
+// (`sql_state`) and the retryability derived from a database error:
+//
+//   - NotFoundErrors (eg: `sql.ErrNoRows`) have the `not_found` code and are not retryable
+//   - integrity constraint violations (SQLSTATE class 23) are not retryable
+//   - serialization failures and deadlocks (class 40), connection exceptions (class 08)
+//     and too many connections (53300) are retryable
+//
+// Driver errors are detected with the `SQLState() string` method of pgx and lib/pq errors.
+func Classify(err error) oops.OopsErrorBuilder {
+	return classify(oops.Tags("sql"), err)
+}
+
+func classify(builder oops.OopsErrorBuilder, err error) oops.OopsErrorBuilder {
+	for _, notFound := range NotFoundErrors {
+		if errors.Is(err, notFound) {
+			return builder.Code("not_found").Retryable(false)
+		}
+	}
+
+	var stateErr sqlStateError
+	if !errors.As(err, &stateErr) {
+		return builder
+	}
+
+	state := stateErr.SQLState()
+	builder = builder.With("sql_state", state)
+
+	if code, ok := Codes[state]; ok {
+		builder = builder.Code(code)
+	}
+
+	switch {
+	case strings.HasPrefix(state, "23"):
+		builder = builder.Retryable(false)
+	case strings.HasPrefix(state, "40"), strings.HasPrefix(state, "08"), state == "53300":
+		builder = builder.Retryable(true)
+	}
+
+	return builder
+}
+
+// Wrap classifies and wraps a database error (see Classify), with the name of
+// the query (`sql_query`). It returns nil when err is nil.
+//
+//	err := db.QueryRowContext(ctx, query, id).Scan(&invoice.ID, &invoice.Amount)
+//	return oopssql.Wrap(err, "invoices.get")
+func Wrap(err error, query string) error {
+	if err == nil {
+		return nil
+	}
+
+	return Classify(err).
+		With("sql_query", query).
+		Wrap(err)
+}
+
+// Do runs fn and wraps its error (see Wrap), with the duration of the query.
+// The builder transported in ctx is reused (see `oops.WithBuilder`).
+//
+//	err := oopssql.Do(ctx, "invoices.get", func() error {
+//		return db.QueryRowContext(ctx, query, id).Scan(&invoice.ID, &invoice.Amount)
+//	})
+func Do(ctx context.Context, query string, fn func() error) error {
+	start := time.Now()
+
+	err := fn()
+	if err == nil {
+		return nil
+	}
+
+	return classify(oops.FromContext(ctx).Tags("sql"), err).
+		With("sql_query", query).
+		Since(start).
+		Wrap(err)
+}
@@ -0,0 +1,77 @@
+package oopssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+type pgError struct {
+	Code string
+}
+
+func (e *pgError) Error() string    { return "ERROR (SQLSTATE " + e.Code + ")" }
+func (e *pgError) SQLState() string { return e.Code }
+
+func TestClassify(t *testing.T) {
+	is := assert.New(t)
+
+	err := Classify(sql.ErrNoRows).Wrap(sql.ErrNoRows).(oops.OopsError)
+	is.Equal("not_found", err.Code())
+	is.Equal([]string{"sql"}, err.Tags())
+	is.False(err.Retryable())
+
+	err = Classify(fmt.Errorf("scan: %w", sql.ErrNoRows)).Errorf("invoice not found").(oops.OopsError)
+	is.Equal("not_found", err.Code())
+
+	err = Classify(&pgError{Code: "23505"}).Errorf("duplicate invoice").(oops.OopsError)
+	is.Equal("unique_violation", err.Code())
+	is.Equal("23505", err.Context()["sql_state"])
+	is.False(err.Retryable())
+
+	err = Classify(&pgError{Code: "40001"}).Errorf("could not serialize").(oops.OopsError)
+	is.Equal("serialization_failure", err.Code())
+	is.True(err.Retryable())
+
+	err = Classify(&pgError{Code: "42P01"}).Errorf("undefined table").(oops.OopsError)
+	is.Equal("", err.Code())
+	is.Equal("42P01", err.Context()["sql_state"])
+
+	err = Classify(errors.New("driver: bad connection")).Errorf("query failed").(oops.OopsError)
+	is.Equal("", err.Code())
+	is.NotContains(err.Context(), "sql_state")
+}
+
+func TestWrap(t *testing.T) {
+	is := assert.New(t)
+
+	is.NoError(Wrap(nil, "invoices.get"))
+
+	err := Wrap(&pgError{Code: "23503"}, "invoices.insert")
+	is.EqualError(err, "ERROR (SQLSTATE 23503)")
+	is.Equal("foreign_key_violation", oops.GetCode(err))
+	is.Equal("invoices.insert", err.(oops.OopsError).Context()["sql_query"])
+}
+
+func TestDo(t *testing.T) {
+	is := assert.New(t)
+
+	ctx := oops.WithBuilder(context.Background(), oops.In("billing"))
+
+	is.NoError(Do(ctx, "invoices.get", func() error { return nil }))
+
+	err := Do(ctx, "invoices.get", func() error {
+		time.Sleep(5 * time.Millisecond)
+		return sql.ErrNoRows
+	}).(oops.OopsError)
+	is.Equal("not_found", err.Code())
+	is.Equal("billing", err.Domain())
+	is.Equal("invoices.get", err.Context()["sql_query"])
+	is.GreaterOrEqual(err.Duration(), 5*time.Millisecond)
+}
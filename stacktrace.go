@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 ///
@@ -18,7 +19,13 @@ type fake struct{}
 var (
 	StackTraceMaxDepth int = 10
 
-	packageName = reflect.TypeOf(fake{}).PkgPath()
+	// StackTraceLazy records only the program counters of the stack when an
+	// error is created. Files, lines and functions are resolved on first
+	// formatting, so that errors that are never printed are cheaper.
+	StackTraceLazy = false
+
+	packageName         = reflect.TypeOf(fake{}).PkgPath()
+	packageNameExamples = packageName + "/examples/"
 )
 
 type oopsStacktraceFrame struct {
@@ -40,6 +47,29 @@ func (frame *oopsStacktraceFrame) String() string {
 type oopsStacktrace struct {
 	span   string
 	frames []oopsStacktraceFrame
+
+	// captured with StackTraceSampling: only some errors of a call site have
+	// frames, so they are not hashed by Fingerprint
+	sampled bool
+
+	// lazy mode: program counters resolved into frames on first use
+	pcs      []uintptr
+	maxDepth int
+	skip     int
+	resolve  sync.Once
+}
+
+// getFrames returns the frames of the stacktrace, resolving the program
+// counters recorded in lazy mode.
+func (st *oopsStacktrace) getFrames() []oopsStacktraceFrame {
+	st.resolve.Do(func() {
+		if st.pcs != nil {
+			st.frames = resolveFrames(st.pcs, st.maxDepth, st.skip)
+			st.pcs = nil
+		}
+	})
+
+	return st.frames
 }
 
 func (st *oopsStacktrace) Error() string {
@@ -55,7 +85,7 @@ func (st *oopsStacktrace) String(deepestFrame string) string {
 		}
 	}
 
-	for _, frame := range st.frames {
+	for _, frame := range st.getFrames() {
 		if frame.file != "" {
 			currentFrame := frame.String()
 			if currentFrame == deepestFrame {
@@ -71,11 +101,11 @@ func (st *oopsStacktrace) String(deepestFrame string) string {
 }
 
 func (st *oopsStacktrace) Source() (string, []string) {
-	if len(st.frames) == 0 {
+	if len(st.getFrames()) == 0 {
 		return "", []string{}
 	}
 
-	firstFrame := st.frames[0]
+	firstFrame := st.getFrames()[0]
 
 	header := firstFrame.String()
 	body := getSourceFromFrame(firstFrame)
@@ -84,52 +114,110 @@ func (st *oopsStacktrace) Source() (string, []string) {
 }
 
 func newStacktrace(span string) *oopsStacktrace {
-	frames := []oopsStacktraceFrame{}
+	return newStacktraceWithDepth(span, StackTraceMaxDepth)
+}
 
-	// We loop until we have StackTraceMaxDepth frames or we run out of frames.
-	// Frames from this package are skipped.
-	for i := 0; len(frames) < StackTraceMaxDepth; i++ {
-		pc, file, line, ok := runtime.Caller(i)
-		if !ok {
-			break
+func newStacktraceWithDepth(span string, maxDepth int) *oopsStacktrace {
+	return newStacktraceWithSkip(span, maxDepth, 0)
+}
+
+func newStacktraceWithSkip(span string, maxDepth int, skip int) *oopsStacktrace {
+	st := newLazyStacktrace(span, maxDepth, skip)
+
+	if !StackTraceLazy {
+		// resolved now: the frames of this package are skipped by resolveFrames
+		st.getFrames()
+	}
+
+	return st
+}
+
+// lazy stacktraces record extra program counters, for the frames of this
+// package and of GOROOT that are filtered out on resolution
+const lazyStacktraceExtraFrames = 16
+
+func newLazyStacktrace(span string, maxDepth int, skip int) *oopsStacktrace {
+	pcs := make([]uintptr, maxDepth+skip+lazyStacktraceExtraFrames)
+	n := runtime.Callers(1, pcs)
+
+	return &oopsStacktrace{
+		span:     span,
+		pcs:      pcs[:n],
+		maxDepth: maxDepth,
+		skip:     skip,
+	}
+}
+
+func resolveFrames(pcs []uintptr, maxDepth int, skip int) []oopsStacktraceFrame {
+	frames := make([]oopsStacktraceFrame, 0, min(len(pcs), maxDepth))
+
+	callers := runtime.CallersFrames(pcs)
+	for len(frames) < maxDepth {
+		frame, more := callers.Next()
+		canonical, display := framePath(frame.File, frame.Function)
+
+		if frame.Function != "" && isCallerFrame(frame.File, canonical) {
+			if skip > 0 {
+				skip--
+			} else {
+				frames = append(frames, oopsStacktraceFrame{
+					pc:       frame.PC,
+					file:     display,
+					function: shortFunctionName(frame.Function),
+					line:     frame.Line,
+				})
+			}
 		}
-		file = removeGoPath(file)
 
-		f := runtime.FuncForPC(pc)
-		if f == nil {
+		if !more {
 			break
 		}
-		function := shortFuncName(f)
-
-		packageNameExamples := packageName + "/examples/"
-
-		isGoPkg := len(runtime.GOROOT()) > 0 && strings.Contains(file, runtime.GOROOT()) // skip frames in GOROOT if it's set
-		isOopsPkg := strings.Contains(file, packageName)                                 // skip frames in this package
-		isExamplePkg := strings.Contains(file, packageNameExamples)                      // do not skip frames in this package examples
-		isTestPkg := strings.Contains(file, "_test.go")                                  // do not skip frames in tests
-
-		if !isGoPkg && (!isOopsPkg || isExamplePkg || isTestPkg) {
-			frames = append(frames, oopsStacktraceFrame{
-				pc:       pc,
-				file:     file,
-				function: function,
-				line:     line,
-			})
-		}
 	}
 
-	return &oopsStacktrace{
-		span:   span,
-		frames: frames,
+	return frames
+}
+
+// isCallerFrame reports whether a frame belongs to the callers of this package.
+// The canonical path of the file starts with the module path (see framePath).
+func isCallerFrame(file string, canonical string) bool {
+	isGoPkg := len(runtime.GOROOT()) > 0 && strings.Contains(file, runtime.GOROOT()) // skip frames in GOROOT if it's set
+	isOopsPkg := strings.Contains(canonical, packageName)                            // skip frames in this package
+	isExamplePkg := strings.Contains(canonical, packageNameExamples)                 // do not skip frames in this package examples
+	isTestPkg := strings.Contains(canonical, "_test.go")                             // do not skip frames in tests
+
+	return !isGoPkg && (!isOopsPkg || isExamplePkg || isTestPkg)
+}
+
+var (
+	shortFunctionNamesMutex sync.RWMutex
+	shortFunctionNames      = map[string]string{}
+)
+
+// shortFunctionName memoizes the short names of functions: the set of functions
+// of a program is bounded.
+func shortFunctionName(longName string) string {
+	shortFunctionNamesMutex.RLock()
+	shortName, ok := shortFunctionNames[longName]
+	shortFunctionNamesMutex.RUnlock()
+
+	if ok {
+		return shortName
 	}
+
+	shortName = computeShortFunctionName(longName)
+
+	shortFunctionNamesMutex.Lock()
+	shortFunctionNames[longName] = shortName
+	shortFunctionNamesMutex.Unlock()
+
+	return shortName
 }
 
-func shortFuncName(f *runtime.Func) string {
-	// f.Name() is like one of these:
+func computeShortFunctionName(longName string) string {
+	// longName is like one of these:
 	// - "github.com/palantir/shield/package.FuncName"
 	// - "github.com/palantir/shield/package.Receiver.MethodName"
 	// - "github.com/palantir/shield/package.(*PtrReceiver).MethodName"
-	longName := f.Name()
 
 	withoutPath := longName[strings.LastIndex(longName, "/")+1:]
 	withoutPackage := withoutPath[strings.Index(withoutPath, ".")+1:]
@@ -0,0 +1,65 @@
+package oops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ForeignFrame is a stack frame captured outside of the Go runtime (cgo, FFI,
+// remote service...).
+type ForeignFrame struct {
+	File     string
+	Function string
+	Line     int
+}
+
+func (frame ForeignFrame) String() string {
+	currentFrame := fmt.Sprintf("%v:%v", frame.File, frame.Line)
+	if frame.Function != "" {
+		currentFrame = fmt.Sprintf("%v:%v %v()", frame.File, frame.Line, frame.Function)
+	}
+
+	return currentFrame
+}
+
+// ForeignStacktracer is implemented by errors carrying frames captured outside
+// of the Go runtime. When wrapped, these frames are printed beneath the Go frames
+// by `Stacktrace()`.
+type ForeignStacktracer interface {
+	ForeignStacktrace() (origin string, frames []ForeignFrame)
+}
+
+type foreignStacktrace struct {
+	origin string
+	frames []ForeignFrame
+}
+
+func (st foreignStacktrace) String() string {
+	lines := make([]string, 0, len(st.frames))
+	for _, frame := range st.frames {
+		lines = append(lines, fmt.Sprintf("  --- [%s] at %s", st.origin, frame.String()))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// foreignStacktraces returns the foreign frames declared on this error, or
+// carried by the wrapped error.
+func (o OopsError) foreignStacktraces() []foreignStacktrace {
+	stacktraces := o.foreign
+
+	if o.err != nil {
+		if _, ok := AsOops(o.err); !ok {
+			var fs ForeignStacktracer
+			if errors.As(o.err, &fs) {
+				origin, frames := fs.ForeignStacktrace()
+				if len(frames) > 0 {
+					stacktraces = append(stacktraces[:len(stacktraces):len(stacktraces)], foreignStacktrace{origin: origin, frames: frames})
+				}
+			}
+		}
+	}
+
+	return stacktraces
+}
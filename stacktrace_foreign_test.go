@@ -0,0 +1,42 @@
+package oops
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sqliteError struct{}
+
+func (e sqliteError) Error() string {
+	return "database is locked"
+}
+
+func (e sqliteError) ForeignStacktrace() (string, []ForeignFrame) {
+	return "sqlite", []ForeignFrame{
+		{File: "sqlite3.c", Function: "sqlite3_step", Line: 1234},
+		{File: "sqlite3.c", Line: 42},
+	}
+}
+
+func TestStacktraceForeignFrames(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().
+		ForeignFrames("payment-service", ForeignFrame{File: "app/payment.py", Function: "charge", Line: 12}).
+		Wrap(fmt.Errorf("query failed: %w", sqliteError{}))
+
+	stacktrace := err.(OopsError).Stacktrace()
+	is.True(strings.HasPrefix(stacktrace, "Oops: query failed: database is locked\n  --- at "))
+	is.True(strings.HasSuffix(stacktrace, "\n  --- [payment-service] at app/payment.py:12 charge()\n  --- [sqlite] at sqlite3.c:1234 sqlite3_step()\n  --- [sqlite] at sqlite3.c:42"))
+
+	// foreign frames are printed once when wrapped again
+	err = new().Wrapf(err, "payment failed")
+	is.Equal(1, strings.Count(err.(OopsError).Stacktrace(), "sqlite3_step"))
+
+	// without go frames
+	err = new().WithoutStacktrace().Wrap(sqliteError{})
+	is.Equal("Oops: database is locked\n  --- [sqlite] at sqlite3.c:1234 sqlite3_step()\n  --- [sqlite] at sqlite3.c:42", err.(OopsError).Stacktrace())
+}
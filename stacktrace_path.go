@@ -0,0 +1,164 @@
+package oops
+
+import (
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// StackTracePathTrimmer rewrites the file paths of stack frames (eg: to remove
+// a build directory). When nil, paths of the main module start with the module
+// path instead of the root directory of the module (as with `go build -trimpath`),
+// paths of the module cache start with the module path and version, and paths
+// of the GOPATH are relative to it.
+//
+//	oops.StackTracePathTrimmer = func(path string) string {
+//		return strings.TrimPrefix(path, "/home/ci/build/")
+//	}
+var StackTracePathTrimmer func(path string) string
+
+type moduleRoot struct {
+	dir  string
+	path string
+}
+
+var (
+	mainModuleOnce        sync.Once
+	mainModulePath        string // eg: github.com/acme/api
+	mainModulePackagePath string // eg: github.com/acme/api/cmd/server
+
+	mainModuleRoot atomic.Pointer[moduleRoot]
+
+	// trimmed paths -> paths of the files on disk, for source fragments
+	sourcePaths sync.Map
+
+	// paths of the files on disk -> canonical paths, for the detected root
+	canonicalPathsMutex sync.RWMutex
+	canonicalPathsRoot  *moduleRoot
+	canonicalPaths      = map[string]string{}
+)
+
+// framePath returns the canonical path of a file (see StackTracePathTrimmer),
+// used to filter frames, and the path to display.
+func framePath(file string, function string) (canonical string, display string) {
+	canonical = canonicalPath(file, function)
+
+	display = canonical
+	if StackTracePathTrimmer != nil {
+		display = StackTracePathTrimmer(file)
+
+		if display != file {
+			sourcePaths.Store(display, file)
+		}
+	}
+
+	return canonical, display
+}
+
+// canonicalPath memoizes cleanPath. The paths are computed again once the root
+// of the main module is detected.
+func canonicalPath(file string, function string) string {
+	root := mainModuleRoot.Load()
+
+	canonicalPathsMutex.RLock()
+	canonical, ok := canonicalPaths[file]
+	ok = ok && canonicalPathsRoot == root
+	canonicalPathsMutex.RUnlock()
+
+	if ok {
+		return canonical
+	}
+
+	canonical = cleanPath(file, function)
+	if canonical != file {
+		sourcePaths.Store(canonical, file)
+	}
+
+	canonicalPathsMutex.Lock()
+	if root := mainModuleRoot.Load(); canonicalPathsRoot != root {
+		canonicalPathsRoot = root
+		canonicalPaths = map[string]string{}
+	}
+	canonicalPaths[file] = canonical
+	canonicalPathsMutex.Unlock()
+
+	return canonical
+}
+
+// sourcePath returns the path on disk of a file of a stack frame.
+func sourcePath(file string) string {
+	if original, ok := sourcePaths.Load(file); ok {
+		return original.(string)
+	}
+
+	return file
+}
+
+func cleanPath(file string, function string) string {
+	detectModuleRoot(file, function)
+
+	if root := mainModuleRoot.Load(); root != nil {
+		if rel, ok := strings.CutPrefix(file, root.dir+"/"); ok {
+			return root.path + "/" + rel
+		}
+	}
+
+	// eg: /home/john/go/pkg/mod/github.com/samber/lo@v1.47.0/errors.go
+	if i := strings.LastIndex(file, "/pkg/mod/"); i >= 0 {
+		return file[i+len("/pkg/mod/"):]
+	}
+
+	return removeGoPath(file)
+}
+
+// detectModuleRoot deduces the root directory of the main module from a frame
+// of one of its packages: the directory of the file, minus the path of the
+// package relative to the module.
+func detectModuleRoot(file string, function string) {
+	if mainModuleRoot.Load() != nil {
+		return
+	}
+
+	mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModulePath = info.Main.Path
+			mainModulePackagePath = info.Path
+		}
+	})
+
+	if mainModulePath == "" || function == "" {
+		return
+	}
+
+	pkg := functionPackage(function)
+	if pkg == "main" {
+		pkg = mainModulePackagePath
+	}
+
+	// package path relative to the module: "" or "/pkg/users"
+	rel, ok := strings.CutPrefix(pkg, mainModulePath)
+	if !ok || (rel != "" && rel[0] != '/') {
+		return
+	}
+
+	dir, ok := strings.CutSuffix(path.Dir(file), rel)
+	if !ok || dir == "" {
+		return
+	}
+
+	mainModuleRoot.CompareAndSwap(nil, &moduleRoot{dir: dir, path: mainModulePath})
+}
+
+// functionPackage returns the package path of a function
+// (eg: "github.com/acme/api/users.(*Repository).Get" -> "github.com/acme/api/users").
+// Dots of the last element of the path are escaped by the linker (eg: "yaml%2ev3").
+func functionPackage(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	if i := strings.Index(function[lastSlash+1:], "."); i >= 0 {
+		function = function[:lastSlash+1+i]
+	}
+
+	return strings.ReplaceAll(function, "%2e", ".")
+}
@@ -0,0 +1,58 @@
+package oops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionPackage(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("main", functionPackage("main.main"))
+	is.Equal("github.com/acme/api/users", functionPackage("github.com/acme/api/users.(*Repository).Get"))
+	is.Equal("github.com/acme/api/users", functionPackage("github.com/acme/api/users.Get.func1"))
+	is.Equal("gopkg.in/yaml.v3", functionPackage("gopkg.in/yaml%2ev3.Unmarshal"))
+}
+
+func TestCleanPath(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal("github.com/samber/lo@v1.47.0/errors.go", cleanPath("/home/john/go/pkg/mod/github.com/samber/lo@v1.47.0/errors.go", "github.com/samber/lo.Must"))
+	is.Equal("/app/main.go", cleanPath("/app/main.go", "main.main"))
+}
+
+func TestStacktraceModulePath(t *testing.T) {
+	is := assert.New(t)
+
+	frames := new().Errorf("permission denied").(OopsError).StackFrames()
+	is.NotEmpty(frames)
+	is.Equal("github.com/samber/oops/stacktrace_path_test.go", frames[0].File)
+	is.Equal("TestStacktraceModulePath", frames[0].Function)
+
+	// source fragments are read from the file on disk
+	source := frames[0].Source()
+	is.NotEmpty(source)
+	is.True(strings.Contains(lineOf(source), "permission denied"))
+
+	StackTracePathTrimmer = func(path string) string {
+		return "custom/" + path[strings.LastIndex(path, "/")+1:]
+	}
+	defer func() { StackTracePathTrimmer = nil }()
+
+	frames = new().Errorf("permission denied").(OopsError).StackFrames()
+	is.Equal("custom/stacktrace_path_test.go", frames[0].File)
+	is.Equal("TestStacktraceModulePath", frames[0].Function)
+	is.NotEmpty(frames[0].Source())
+}
+
+func lineOf(source []SourceLine) string {
+	for _, line := range source {
+		if line.Current {
+			return line.Code
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,38 @@
+package oops
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// StackTraceSampling captures a stacktrace for 1 error out of N created at the
+// same call site. Other errors get an empty stacktrace. It reduces the cost of
+// stack walking in hot error paths. Values lower than 2 disable sampling.
+var StackTraceSampling int = 1
+
+// number of program counters identifying a call site
+const samplingCallSiteDepth = 8
+
+type samplingCallSite [samplingCallSiteDepth]uintptr
+
+var samplingCounters sync.Map // samplingCallSite -> *atomic.Uint64
+
+// shouldSampleStacktrace reports whether the stacktrace of the current error
+// must be captured. The first error of a call site is always captured.
+func shouldSampleStacktrace() bool {
+	rate := StackTraceSampling
+	if rate < 2 {
+		return true
+	}
+
+	var site samplingCallSite
+	runtime.Callers(3, site[:])
+
+	counter, ok := samplingCounters.Load(site)
+	if !ok {
+		counter, _ = samplingCounters.LoadOrStore(site, &atomic.Uint64{})
+	}
+
+	return (counter.(*atomic.Uint64).Add(1)-1)%uint64(rate) == 0
+}
@@ -0,0 +1,41 @@
+package oops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackTraceSampling(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { StackTraceSampling = 1 }()
+	StackTraceSampling = 3
+
+	captured := 0
+	fingerprints := map[string]struct{}{}
+	for i := 0; i < 9; i++ {
+		err := new().Errorf("sampled").(OopsError)
+		is.NotNil(err.stacktrace)
+		fingerprints[err.Fingerprint()] = struct{}{}
+
+		if len(err.stacktrace.frames) > 0 {
+			captured++
+			is.NotEmpty(err.Stacktrace())
+		} else {
+			is.Empty(err.Stacktrace())
+		}
+	}
+	is.Equal(3, captured)
+	is.Len(fingerprints, 1)
+
+	// another call site is sampled independently
+	err := new().Errorf("other call site").(OopsError)
+	is.NotEmpty(err.stacktrace.frames)
+
+	StackTraceSampling = 1
+	for i := 0; i < 3; i++ {
+		err := new().Errorf("not sampled").(OopsError)
+		is.NotEmpty(err.stacktrace.frames)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +62,136 @@ func TestStacktrace(t *testing.T) {
 		}
 	}
 }
+
+func TestStacktraceBuilderOptions(t *testing.T) {
+	is := assert.New(t)
+
+	err := new().WithoutStacktrace().Errorf("permission denied")
+	is.Nil(err.(OopsError).stacktrace)
+	is.Empty(err.(OopsError).Stacktrace())
+
+	err = new().WithStackDepth(1).Errorf("permission denied")
+	is.NotNil(err.(OopsError).stacktrace)
+	is.Len(err.(OopsError).stacktrace.frames, 1)
+
+	err = new().Errorf("permission denied")
+	is.NotNil(err.(OopsError).stacktrace)
+	is.NotEmpty(err.(OopsError).stacktrace.frames)
+
+	err = Caller().Errorf("permission denied")
+	is.Len(err.(OopsError).StackFrames(), 1)
+	is.Equal("TestStacktraceBuilderOptions", err.(OopsError).StackFrames()[0].Function)
+}
+
+func skipFramesHelper(n int) error {
+	return new().WithStackDepth(100).SkipFrames(n).Errorf("not found")
+}
+
+func stacktraceFunctions(err error) []string {
+	return lo.Map(err.(OopsError).stacktrace.frames, func(frame oopsStacktraceFrame, _ int) string {
+		return frame.function
+	})
+}
+
+func TestStacktraceSkipFrames(t *testing.T) {
+	is := assert.New(t)
+
+	functions := stacktraceFunctions(skipFramesHelper(0))
+	is.Contains(functions, "skipFramesHelper")
+	is.Equal(functions[1:], stacktraceFunctions(skipFramesHelper(1)))
+	is.Equal(functions[2:], stacktraceFunctions(skipFramesHelper(2)))
+
+	err := SkipFrames(1).SkipFrames(1).WithStackDepth(100).Errorf("not found")
+	is.Len(err.(OopsError).stacktrace.frames, max(len(functions)-3, 0))
+
+	err = SkipFrames(1000).Errorf("not found")
+	is.Empty(err.(OopsError).stacktrace.frames)
+}
+
+func lazyStacktraceHelper() error {
+	return new().WithStackDepth(100).Errorf("not found")
+}
+
+func TestStacktraceLazy(t *testing.T) {
+	is := assert.New(t)
+
+	testFrames := func(err error) []string {
+		frames := lo.Filter(err.(OopsError).stacktrace.getFrames(), func(frame oopsStacktraceFrame, _ int) bool {
+			return strings.HasSuffix(frame.file, "_test.go")
+		})
+
+		return lo.Map(frames, func(frame oopsStacktraceFrame, _ int) string {
+			return frame.function
+		})
+	}
+
+	eager := lazyStacktraceHelper()
+
+	StackTraceLazy = true
+	defer func() { StackTraceLazy = false }()
+
+	lazy := lazyStacktraceHelper()
+	st := lazy.(OopsError).stacktrace
+	is.NotEmpty(st.pcs)
+	is.Nil(st.frames)
+
+	is.Equal(testFrames(eager), testFrames(lazy))
+	is.Equal([]string{"lazyStacktraceHelper", "TestStacktraceLazy"}, testFrames(lazy)[:2])
+	is.Nil(st.pcs)
+	is.NotEmpty(st.frames)
+	is.Contains(lazy.(OopsError).Stacktrace(), "lazyStacktraceHelper()")
+
+	err := WithStackDepth(1).Errorf("not found")
+	is.Len(err.(OopsError).StackFrames(), 1)
+}
+
+func linkStackHelper() OopsErrorBuilder {
+	return new().In("mailer").LinkStack()
+}
+
+func TestStacktraceLinkStack(t *testing.T) {
+	is := assert.New(t)
+
+	builder := linkStackHelper()
+
+	done := make(chan error)
+	go func() {
+		done <- builder.Errorf("could not send email")
+	}()
+	err := (<-done).(OopsError)
+
+	linked := err.LinkedStackFrames()
+	is.NotEmpty(linked)
+	is.Equal("linkStackHelper", linked[0].Function)
+	is.Contains(lo.Map(linked, func(frame StackFrame, _ int) string { return frame.Function }), "TestStacktraceLinkStack")
+	is.NotContains(lo.Map(err.StackFrames(), func(frame StackFrame, _ int) string { return frame.Function }), "linkStackHelper")
+
+	stacktrace := err.Stacktrace()
+	is.Contains(stacktrace, "  --- [linked] at ")
+	is.Contains(stacktrace, "linkStackHelper()")
+
+	// the linked stack is printed once per chain
+	wrapped := builder.Wrapf(err, "job failed").(OopsError)
+	is.Equal(len(linked), strings.Count(wrapped.Stacktrace(), "[linked]"))
+
+	is.Empty(new().Errorf("could not send email").(OopsError).LinkedStackFrames())
+}
+
+func TestStacktraceStackHash(t *testing.T) {
+	is := assert.New(t)
+
+	errs := []error{}
+	for i := 0; i < 2; i++ {
+		errs = append(errs, new().Errorf("user %d not found", i))
+	}
+	other := new().Errorf("user %d not found", 0)
+
+	hash := errs[0].(OopsError).StackHash()
+	is.NotZero(hash)
+	is.Equal(hash, errs[1].(OopsError).StackHash())
+	is.NotEqual(hash, new().Wrap(errs[0]).(OopsError).StackHash())
+	is.Equal(hash, other.(OopsError).StackHash())
+	is.NotEqual(hash, func() error { return new().Errorf("user not found") }().(OopsError).StackHash())
+
+	is.Zero(new().WithoutStacktrace().Errorf("user not found").(OopsError).StackHash())
+}
@@ -0,0 +1,116 @@
+package oopstemporal
+
+import (
+	"errors"
+
+	"github.com/samber/oops"
+	"go.temporal.io/sdk/temporal"
+)
+
+// DefaultErrorType is the type of application errors built from oops errors without code.
+const DefaultErrorType = "OopsError"
+
+// Details are the attributes of an oops error, sent as the details of an
+// `ApplicationError`.
+type Details struct {
+	Code       string         `json:"code,omitempty"`
+	Domain     string         `json:"domain,omitempty"`
+	Tags       []string       `json:"tags,omitempty"`
+	Trace      string         `json:"trace,omitempty"`
+	Hint       string         `json:"hint,omitempty"`
+	Public     string         `json:"public,omitempty"`
+	Owner      string         `json:"owner,omitempty"`
+	Severity   string         `json:"severity,omitempty"`
+	HTTPStatus int            `json:"http_status,omitempty"`
+	Context    map[string]any `json:"context,omitempty"`
+}
+
+// ToApplicationError converts an oops error into a Temporal `ApplicationError`.
+// The code is the error type (default: "OopsError"), errors marked with
+// `oops.Retryable(false)` are non-retryable, and attributes travel as details.
+// Other errors are returned as is.
+//
+//	func ChargeActivity(ctx context.Context, req ChargeRequest) error {
+//		err := charge(ctx, req)
+//		return oopstemporal.ToApplicationError(err)
+//	}
+func ToApplicationError(err error) error {
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return err
+	}
+
+	errorType := oopsError.Code()
+	if errorType == "" {
+		errorType = DefaultErrorType
+	}
+
+	details := Details{
+		Code:       oopsError.Code(),
+		Domain:     oopsError.Domain(),
+		Tags:       oopsError.Tags(),
+		Trace:      oopsError.Trace(),
+		Hint:       oopsError.Hint(),
+		Public:     oopsError.Public(),
+		Owner:      oopsError.Owner(),
+		Severity:   string(oopsError.Severity()),
+		HTTPStatus: oopsError.HTTPStatus(),
+	}
+
+	// redacted values are not exported
+	if context, ok := oopsError.ToMap()["context"].(map[string]any); ok {
+		details.Context = context
+	}
+
+	return temporal.NewApplicationErrorWithOptions(
+		oopsError.Error(),
+		errorType,
+		temporal.ApplicationErrorOptions{
+			NonRetryable: !oopsError.Retryable(),
+			Details:      []any{details},
+		},
+	)
+}
+
+// FromApplicationError rehydrates an oops error from a Temporal `ApplicationError`
+// found in the chain of err (eg: the cause of an `ActivityError` in a workflow).
+// Other errors are wrapped as is.
+//
+//	err := workflow.ExecuteActivity(ctx, ChargeActivity, req).Get(ctx, nil)
+//	if err != nil {
+//		return oopstemporal.FromApplicationError(err)
+//	}
+func FromApplicationError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) {
+		return oops.Wrap(err)
+	}
+
+	builder := oops.
+		Retryable(!appErr.NonRetryable()).
+		With("temporal_error_type", appErr.Type())
+
+	var details Details
+	if appErr.HasDetails() && appErr.Details(&details) == nil {
+		builder = builder.
+			Code(details.Code).
+			In(details.Domain).
+			Tags(details.Tags...).
+			Trace(details.Trace).
+			Hint(details.Hint).
+			Public(details.Public).
+			Owner(details.Owner).
+			Severity(oops.SeverityLevel(details.Severity)).
+			HTTPStatus(details.HTTPStatus)
+
+		for k, v := range details.Context {
+			builder = builder.With(k, v)
+		}
+	}
+
+	return builder.Errorf("%s", appErr.Message())
+}
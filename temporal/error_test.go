@@ -0,0 +1,56 @@
+package oopstemporal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestRoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("card_declined").
+		In("payment").
+		Trace("1234").
+		Public("Your card was declined.").
+		Retryable(false).
+		With("amount", 42).
+		Errorf("stripe: card declined")
+
+	converted := ToApplicationError(err)
+
+	var appErr *temporal.ApplicationError
+	is.True(errors.As(converted, &appErr))
+	is.Equal("card_declined", appErr.Type())
+	is.Equal("stripe: card declined", appErr.Message())
+	is.True(appErr.NonRetryable())
+	is.True(appErr.HasDetails())
+
+	rehydrated, ok := oops.AsOops(FromApplicationError(converted))
+	is.True(ok)
+	is.Equal("stripe: card declined", rehydrated.Error())
+	is.Equal("card_declined", rehydrated.Code())
+	is.Equal("payment", rehydrated.Domain())
+	is.Equal("1234", rehydrated.Trace())
+	is.Equal("Your card was declined.", rehydrated.Public())
+	is.False(rehydrated.Retryable())
+	is.Equal(42, rehydrated.Context()["amount"])
+	is.Equal("card_declined", rehydrated.Context()["temporal_error_type"])
+}
+
+func TestToApplicationError(t *testing.T) {
+	is := assert.New(t)
+
+	var appErr *temporal.ApplicationError
+	is.True(errors.As(ToApplicationError(oops.Errorf("timeout")), &appErr))
+	is.Equal(DefaultErrorType, appErr.Type())
+	is.False(appErr.NonRetryable())
+
+	original := errors.New("boom")
+	is.Equal(original, ToApplicationError(original))
+	is.Nil(ToApplicationError(nil))
+}
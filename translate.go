@@ -0,0 +1,47 @@
+package oops
+
+// TranslationRule rewrites the attributes of an error crossing a module
+// boundary (eg: storage codes -> API codes).
+type TranslationRule struct {
+	// Code and Domain select the errors to translate. Empty values match any error.
+	Code   string
+	Domain string
+
+	// ToCode, ToDomain and ToPublic are the translated attributes. Empty values keep the original attribute.
+	ToCode   string
+	ToDomain string
+	ToPublic string
+}
+
+func (r TranslationRule) match(code string, domain string) bool {
+	return (r.Code == "" || r.Code == code) && (r.Domain == "" || r.Domain == domain)
+}
+
+// Translate wraps an error into an `oops.OopsError` object, with code, domain and
+// public message rewritten by the first matching rule. Translated attributes take
+// precedence over the wrapped errors, while the original error is kept in the chain.
+// If no rule matches, the error is returned as is.
+func Translate(err error, rules []TranslationRule) error {
+	if err == nil {
+		return nil
+	}
+
+	code, domain := GetCode(err), GetDomain(err)
+
+	for _, rule := range rules {
+		if !rule.match(code, domain) {
+			continue
+		}
+
+		builder := new().
+			Code(rule.ToCode).
+			In(rule.ToDomain).
+			Public(rule.ToPublic)
+		// set before building, so that hooks see the translated attributes
+		builder.translated = true
+
+		return builder.Wrap(err)
+	}
+
+	return err
+}
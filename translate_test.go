@@ -0,0 +1,78 @@
+package oops
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslate(t *testing.T) {
+	is := assert.New(t)
+
+	rules := []TranslationRule{
+		{Code: "storage.row_not_found", ToCode: "api.not_found", ToDomain: "api", ToPublic: "Resource not found."},
+		{Domain: "storage", ToCode: "api.internal", ToPublic: "Internal error."},
+	}
+
+	original := new().
+		Code("storage.row_not_found").
+		In("storage").
+		Hint("check deleted_at column").
+		Trace("1234").
+		Owner("storage-team@acme.org").
+		Time(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)).
+		With("table", "users").
+		Wrap(assert.AnError)
+
+	err := Translate(original, rules)
+	is.Error(err)
+	is.Equal("api.not_found", err.(OopsError).Code())
+	is.Equal("api", err.(OopsError).Domain())
+	is.Equal("Resource not found.", err.(OopsError).Public())
+	is.Equal("check deleted_at column", err.(OopsError).Hint())
+	is.Equal(map[string]any{"table": "users"}, err.(OopsError).Context())
+
+	// other attributes are resolved from the wrapped errors
+	is.Equal("1234", err.(OopsError).Trace())
+	is.Equal("storage-team@acme.org", err.(OopsError).Owner())
+	is.Equal(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), err.(OopsError).Time())
+	is.True(errors.Is(err, assert.AnError))
+	is.Equal("storage.row_not_found", errors.Unwrap(err).(OopsError).Code())
+
+	// translated attributes are not overridden when wrapped again
+	err = Wrapf(err, "could not fetch user")
+	is.Equal("api.not_found", err.(OopsError).Code())
+
+	// second rule, original domain is kept
+	original = new().Code("storage.timeout").In("storage").Wrap(assert.AnError)
+	err = Translate(original, rules)
+	is.Equal("api.internal", err.(OopsError).Code())
+	is.Equal("storage", err.(OopsError).Domain())
+	is.Equal("Internal error.", err.(OopsError).Public())
+
+	// no matching rule
+	original = new().Code("billing.invoice_not_found").In("billing").Wrap(assert.AnError)
+	is.Equal(original, Translate(original, rules))
+	is.Nil(Translate(nil, rules))
+}
+
+func TestTranslateHooks(t *testing.T) {
+	is := assert.New(t)
+
+	defer func() { hooks = []func(OopsError) OopsError{} }()
+
+	codes := []string{}
+	OnError(func(err OopsError) OopsError {
+		codes = append(codes, err.Code())
+		return err
+	})
+
+	original := new().Code("storage.row_not_found").In("storage").Wrap(assert.AnError)
+	err := Translate(original, []TranslationRule{{Code: "storage.row_not_found", ToCode: "api.not_found", ToDomain: "api"}})
+
+	is.Equal([]string{"storage.row_not_found", "api.not_found"}, codes)
+	is.Equal("api.not_found", err.(OopsError).Code())
+	is.Equal("api", err.(OopsError).Domain())
+}
@@ -0,0 +1,206 @@
+package oopstwirp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/samber/oops"
+	"github.com/twitchtv/twirp"
+)
+
+// Meta keys of the oops attributes. Allowed context values are sent with their
+// own key (see ErrorOptions).
+const (
+	MetaCode   = "oops_code"
+	MetaDomain = "oops_domain"
+	MetaPublic = "oops_public"
+	MetaTrace  = "oops_trace"
+)
+
+// ErrorOptions configures the conversion of errors into Twirp errors.
+type ErrorOptions struct {
+	// ContextKeys are the context attributes sent as meta. The context is not
+	// sent by default, since it may hold internal data.
+	ContextKeys []string
+}
+
+// ToTwirpError converts an error into a Twirp error. The Twirp code is the code
+// of the error when it is a valid Twirp code (eg: "not_found"), or is derived
+// from the http status of the error. The message is the public message of the
+// error (default: the text of the http status). Code, domain, public message
+// and trace are sent as meta. Non-oops errors are reported as `twirp.Internal`.
+//
+// The context of the error is not sent. Use ToTwirpErrorWith to send some
+// attributes as meta.
+//
+//	func (s *server) GetInvoice(ctx context.Context, req *pb.GetInvoiceRequest) (*pb.Invoice, error) {
+//		invoice, err := s.repo.Get(ctx, req.Id)
+//		if err != nil {
+//			return nil, oopstwirp.ToTwirpError(err)
+//		}
+//		return invoice, nil
+//	}
+func ToTwirpError(err error) twirp.Error {
+	return ToTwirpErrorWith(err, ErrorOptions{})
+}
+
+// ToTwirpErrorWith converts an error into a Twirp error, with custom options.
+//
+//	twerr := oopstwirp.ToTwirpErrorWith(err, oopstwirp.ErrorOptions{
+//		ContextKeys: []string{"invoice_id"},
+//	})
+func ToTwirpErrorWith(err error, opts ErrorOptions) twirp.Error {
+	if err == nil {
+		return nil
+	}
+
+	if twerr, ok := err.(twirp.Error); ok {
+		return twerr
+	}
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		return twirp.WrapError(twirp.NewError(twirp.Internal, http.StatusText(http.StatusInternalServerError)), err)
+	}
+
+	code := twirp.ErrorCode(oopsError.Code())
+	if code == twirp.NoError || !twirp.IsValidErrorCode(code) {
+		code = httpStatusToTwirpCode(oopsError.HTTPStatus())
+	}
+
+	msg := oops.GetPublic(oopsError, http.StatusText(twirp.ServerHTTPStatusFromErrorCode(code)))
+	twerr := twirp.WrapError(twirp.NewError(code, msg), oopsError)
+
+	if context, ok := oopsError.ToMap()["context"].(map[string]any); ok {
+		for _, k := range opts.ContextKeys {
+			if v, ok := context[k]; ok {
+				twerr = twerr.WithMeta(k, fmt.Sprint(v))
+			}
+		}
+	}
+
+	for k, v := range map[string]string{
+		MetaCode:   oopsError.Code(),
+		MetaDomain: oopsError.Domain(),
+		MetaPublic: oopsError.Public(),
+		MetaTrace:  oopsError.Trace(),
+	} {
+		if v != "" {
+			twerr = twerr.WithMeta(k, v)
+		}
+	}
+
+	return twerr
+}
+
+// FromTwirpError rehydrates an `oops.OopsError` from an error returned by a
+// Twirp client. The message of the error is the message of the Twirp error.
+// Errors that are not Twirp errors are wrapped as is.
+//
+//	invoice, err := client.GetInvoice(ctx, &pb.GetInvoiceRequest{Id: id})
+//	if err != nil {
+//		return oopstwirp.FromTwirpError(err)
+//	}
+func FromTwirpError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	twerr, ok := err.(twirp.Error)
+	if !ok {
+		return oops.Wrap(err)
+	}
+
+	builder := oops.
+		HTTPStatus(twirp.ServerHTTPStatusFromErrorCode(twerr.Code())).
+		With("twirp_code", string(twerr.Code()))
+
+	for k, v := range twerr.MetaMap() {
+		switch k {
+		case MetaCode:
+			builder = builder.Code(v)
+		case MetaDomain:
+			builder = builder.In(v)
+		case MetaPublic:
+			builder = builder.Public(v)
+		case MetaTrace:
+			builder = builder.Trace(v)
+		default:
+			builder = builder.With(k, v)
+		}
+	}
+
+	return builder.Errorf("%s", twerr.Msg())
+}
+
+// ServerInterceptor converts the oops errors returned by handlers into Twirp errors.
+//
+//	handler := pb.NewBillingServer(server, twirp.WithServerInterceptors(oopstwirp.ServerInterceptor()))
+func ServerInterceptor() twirp.Interceptor {
+	return ServerInterceptorWith(ErrorOptions{})
+}
+
+// ServerInterceptorWith is ServerInterceptor, with custom options for the
+// conversion of errors into Twirp errors (see ToTwirpErrorWith).
+func ServerInterceptorWith(opts ErrorOptions) twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			if _, ok := oops.AsOops(err); ok {
+				return resp, ToTwirpErrorWith(err, opts)
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// ClientInterceptor rehydrates the Twirp errors received by a client into oops errors.
+//
+//	client := pb.NewBillingProtobufClient(url, http.DefaultClient, twirp.WithClientInterceptors(oopstwirp.ClientInterceptor()))
+func ClientInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			resp, err := next(ctx, req)
+			if _, ok := err.(twirp.Error); ok {
+				return resp, FromTwirpError(err)
+			}
+
+			return resp, err
+		}
+	}
+}
+
+func httpStatusToTwirpCode(httpStatus int) twirp.ErrorCode {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return twirp.InvalidArgument
+	case http.StatusUnauthorized:
+		return twirp.Unauthenticated
+	case http.StatusForbidden:
+		return twirp.PermissionDenied
+	case http.StatusNotFound:
+		return twirp.NotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return twirp.DeadlineExceeded
+	case http.StatusConflict:
+		return twirp.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return twirp.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return twirp.ResourceExhausted
+	case 499: // client closed request
+		return twirp.Canceled
+	case http.StatusNotImplemented:
+		return twirp.Unimplemented
+	case http.StatusServiceUnavailable:
+		return twirp.Unavailable
+	}
+
+	if httpStatus >= 400 && httpStatus < 500 {
+		return twirp.InvalidArgument
+	}
+
+	return twirp.Internal
+}
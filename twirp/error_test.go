@@ -0,0 +1,89 @@
+package oopstwirp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+	"github.com/twitchtv/twirp"
+)
+
+func TestRoundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	err := oops.
+		Code("billing.invoice_not_found").
+		In("billing").
+		Trace("1234").
+		HTTPStatus(404).
+		Public("Invoice not found.").
+		With("invoice_id", 42).
+		Errorf("invoice 42 not found")
+
+	twerr := ToTwirpErrorWith(err, ErrorOptions{ContextKeys: []string{"invoice_id", "unknown"}})
+	is.Equal(twirp.NotFound, twerr.Code())
+	is.Equal("Invoice not found.", twerr.Msg())
+	is.Equal(map[string]string{
+		"invoice_id": "42",
+		MetaCode:     "billing.invoice_not_found",
+		MetaDomain:   "billing",
+		MetaPublic:   "Invoice not found.",
+		MetaTrace:    "1234",
+	}, twerr.MetaMap())
+
+	rehydrated, ok := oops.AsOops(FromTwirpError(twerr))
+	is.True(ok)
+	is.Equal("Invoice not found.", rehydrated.Error())
+	is.Equal("billing.invoice_not_found", rehydrated.Code())
+	is.Equal("billing", rehydrated.Domain())
+	is.Equal("1234", rehydrated.Trace())
+	is.Equal("Invoice not found.", rehydrated.Public())
+	is.Equal(404, rehydrated.HTTPStatus())
+	is.Equal(map[string]any{"invoice_id": "42", "twirp_code": "not_found"}, rehydrated.Context())
+}
+
+func TestToTwirpError(t *testing.T) {
+	is := assert.New(t)
+
+	is.Nil(ToTwirpError(nil))
+
+	// twirp codes are used as is
+	is.Equal(twirp.Unavailable, ToTwirpError(oops.Code("unavailable").Errorf("down")).Code())
+	is.Equal(twirp.Internal, ToTwirpError(oops.Errorf("boom")).Code())
+	is.Equal(twirp.InvalidArgument, ToTwirpError(oops.HTTPStatus(422).Errorf("invalid")).Code())
+
+	twerr := ToTwirpError(errors.New("boom"))
+	is.Equal(twirp.Internal, twerr.Code())
+	is.Equal("Internal Server Error", twerr.Msg())
+
+	// internal message and context are not sent by default
+	twerr = ToTwirpError(oops.HTTPStatus(404).With("sql", "SELECT * FROM invoices").Errorf("invoice 42 not found"))
+	is.Equal("Not Found", twerr.Msg())
+	is.Empty(twerr.Meta("sql"))
+
+	original := twirp.NotFoundError("missing")
+	is.Equal(original, ToTwirpError(original))
+}
+
+func TestInterceptors(t *testing.T) {
+	is := assert.New(t)
+
+	method := func(ctx context.Context, req any) (any, error) {
+		return nil, oops.HTTPStatus(404).Errorf("invoice not found")
+	}
+
+	_, err := ServerInterceptor()(method)(context.Background(), nil)
+	twerr, ok := err.(twirp.Error)
+	is.True(ok)
+	is.Equal(twirp.NotFound, twerr.Code())
+
+	_, err = ClientInterceptor()(func(ctx context.Context, req any) (any, error) {
+		return nil, twerr
+	})(context.Background(), nil)
+	is.EqualError(err, "Not Found")
+	oopsError, ok := oops.AsOops(err)
+	is.True(ok)
+	is.Equal(404, oopsError.HTTPStatus())
+}
@@ -2,6 +2,7 @@ package oops
 
 import (
 	"context"
+	"unicode/utf8"
 
 	"github.com/samber/lo"
 )
@@ -20,3 +21,17 @@ func contextValueOrNil(ctx context.Context, k any) any {
 
 	return v
 }
+
+// truncateMessage cuts s to MaxMessageLength bytes, on a rune boundary.
+func truncateMessage(s string) string {
+	if MaxMessageLength <= 0 || len(s) <= MaxMessageLength {
+		return s
+	}
+
+	n := MaxMessageLength
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n] + "…"
+}
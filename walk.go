@@ -0,0 +1,58 @@
+package oops
+
+// Walk calls fn for each `oops.OopsError` of the error tree, the outermost first,
+// until fn returns false. Both `Unwrap() error` and `Unwrap() []error` (eg: the
+// result of errors.Join) are traversed, depth first. Other errors are traversed
+// but not passed to fn.
+//
+//	oops.Walk(err, func(layer oops.OopsError) bool {
+//		fmt.Println(layer.Code(), layer.Context())
+//		return true
+//	})
+func Walk(err error, fn func(layer OopsError) bool) {
+	walk(err, fn)
+}
+
+// Chain returns the `oops.OopsError` layers of the error tree, in the order of Walk.
+func Chain(err error) []OopsError {
+	layers := []OopsError{}
+
+	Walk(err, func(layer OopsError) bool {
+		layers = append(layers, layer)
+		return true
+	})
+
+	return layers
+}
+
+// walk returns false when fn stopped the traversal.
+func walk(err error, fn func(layer OopsError) bool) bool {
+	switch e := err.(type) {
+	case nil:
+		return true
+	case OopsError:
+		if !fn(e) {
+			return false
+		}
+	case *OopsError:
+		if e == nil {
+			return true
+		}
+		if !fn(*e) {
+			return false
+		}
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, child := range e.Unwrap() {
+			if !walk(child, fn) {
+				return false
+			}
+		}
+	case interface{ Unwrap() error }:
+		return walk(e.Unwrap(), fn)
+	}
+
+	return true
+}
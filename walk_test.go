@@ -0,0 +1,47 @@
+package oops
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	is := assert.New(t)
+
+	invoice42 := new().Code("invoice_42").Errorf("invoice 42 failed")
+	invoice43 := new().Code("invoice_43").Wrapf(errors.New("timeout"), "invoice 43 failed")
+	joined := new().Code("batch_failed").Join(invoice42, fmt.Errorf("retrying: %w", invoice43), errors.New("invoice 44 failed"))
+	err := new().Code("job_failed").Wrapf(joined, "billing job failed")
+
+	codes := func(layers []OopsError) []string {
+		output := []string{}
+		for _, layer := range layers {
+			output = append(output, layer.code)
+		}
+		return output
+	}
+
+	is.Equal([]string{"job_failed", "batch_failed", "invoice_42", "invoice_43"}, codes(Chain(err)))
+
+	// stop
+	visited := []OopsError{}
+	Walk(err, func(layer OopsError) bool {
+		visited = append(visited, layer)
+		return layer.code != "invoice_42"
+	})
+	is.Equal([]string{"job_failed", "batch_failed", "invoice_42"}, codes(visited))
+
+	// non-oops errors
+	is.Equal([]string{"invoice_42"}, codes(Chain(fmt.Errorf("wrapped: %w", invoice42))))
+	is.Equal([]string{"invoice_42", "invoice_43"}, codes(Chain(errors.Join(invoice42, invoice43))))
+	is.Empty(Chain(errors.New("timeout")))
+	is.Empty(Chain(nil))
+
+	// pointers
+	layer := invoice42.(OopsError)
+	is.Equal([]string{"invoice_42"}, codes(Chain(&layer)))
+	is.Empty(Chain((*OopsError)(nil)))
+}
@@ -0,0 +1,131 @@
+package oopsxray
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-xray-sdk-go/strategy/exception"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/samber/oops"
+)
+
+// Namespace of the segment metadata.
+const Namespace = "oops"
+
+// Record adds the error to the current segment (or subsegment) of the context.
+// See RecordSegment. It is a noop when the context has no segment.
+func Record(ctx context.Context, err error) {
+	if seg := xray.GetSegment(ctx); seg != nil {
+		RecordSegment(seg, err)
+	}
+}
+
+// RecordSegment adds the error to a segment:
+//   - annotations (indexed): `code`, `domain` and `user_id`
+//   - metadata: attributes of the error, in the "oops" namespace
+//   - cause: an exception with the message and the stack frames of the deepest error
+//
+// The segment is flagged as error for 4xx http statuses, throttle for 429,
+// and fault otherwise. Non-oops errors are recorded with `seg.AddError`.
+func RecordSegment(seg *xray.Segment, err error) {
+	if xray.SdkDisabled() {
+		return
+	}
+
+	oopsError, ok := oops.AsOops(err)
+	if !ok {
+		_ = seg.AddError(err)
+		return
+	}
+
+	if code := oopsError.Code(); code != "" {
+		_ = seg.AddAnnotation("code", code)
+	}
+
+	if domain := oopsError.Domain(); domain != "" {
+		_ = seg.AddAnnotation("domain", domain)
+	}
+
+	if userID, _ := oopsError.User(); userID != "" {
+		_ = seg.AddAnnotation("user_id", userID)
+	}
+
+	payload := oopsError.ToMapWith(oops.ToMapOptions{Exclude: []string{"stacktrace", "sources", "request", "response"}})
+	for k, v := range payload {
+		_ = seg.AddMetadataToNamespace(Namespace, k, v)
+	}
+
+	cause := toException(oopsError)
+	wd, _ := os.Getwd()
+
+	seg.Lock()
+	defer seg.Unlock()
+
+	// not sampled
+	if seg.Dummy {
+		return
+	}
+
+	switch status := oopsError.HTTPStatus(); {
+	case status == http.StatusTooManyRequests:
+		seg.Error = true
+		seg.Throttle = true
+	case status >= 400 && status < 500:
+		seg.Error = true
+	default:
+		seg.Fault = true
+	}
+
+	seg.GetCause().WorkingDirectory = wd
+	seg.GetCause().Exceptions = append(seg.GetCause().Exceptions, cause)
+}
+
+func toException(err oops.OopsError) exception.Exception {
+	errorType := err.Code()
+	if errorType == "" {
+		errorType = "oops.OopsError"
+	}
+
+	return exception.Exception{
+		ID:      newExceptionID(),
+		Type:    errorType,
+		Message: err.Error(),
+		Stack:   stack(err),
+	}
+}
+
+// stack returns the frames of the deepest error, most recent call first.
+func stack(err oops.OopsError) []exception.Stack {
+	output := []exception.Stack{}
+
+	for current := error(err); current != nil; {
+		e, ok := current.(oops.OopsError)
+		if !ok {
+			break
+		}
+
+		if frames := e.StackFrames(); len(frames) > 0 {
+			output = make([]exception.Stack, 0, len(frames))
+			for _, frame := range frames {
+				output = append(output, exception.Stack{
+					Path:  frame.File,
+					Line:  frame.Line,
+					Label: frame.Function,
+				})
+			}
+		}
+
+		current = e.Unwrap()
+	}
+
+	return output
+}
+
+func newExceptionID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
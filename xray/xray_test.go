@@ -0,0 +1,79 @@
+package oopsxray
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-xray-sdk-go/strategy/sampling"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/samber/oops"
+	"github.com/stretchr/testify/assert"
+)
+
+type alwaysSample struct{}
+
+func (alwaysSample) ShouldTrace(*sampling.Request) *sampling.Decision {
+	return &sampling.Decision{Sample: true}
+}
+
+func beginSegment(t *testing.T) (context.Context, *xray.Segment) {
+	ctx, err := xray.ContextWithConfig(context.Background(), xray.Config{SamplingStrategy: alwaysSample{}})
+	assert.NoError(t, err)
+
+	return xray.BeginSegment(ctx, "test")
+}
+
+func TestRecord(t *testing.T) {
+	is := assert.New(t)
+
+	ctx, seg := beginSegment(t)
+
+	err := oops.
+		Code("iam_missing_permission").
+		In("authz").
+		User("user-123").
+		With("permission", "post.create").
+		Errorf("permission denied")
+
+	Record(ctx, err)
+
+	is.Equal("iam_missing_permission", seg.Annotations["code"])
+	is.Equal("authz", seg.Annotations["domain"])
+	is.Equal("user-123", seg.Annotations["user_id"])
+	is.Equal(map[string]any{"permission": "post.create"}, seg.Metadata[Namespace]["context"])
+	is.NotContains(seg.Metadata[Namespace], "stacktrace")
+	is.True(seg.Fault)
+	is.False(seg.Error)
+
+	is.Len(seg.Cause.Exceptions, 1)
+	is.Equal("iam_missing_permission", seg.Cause.Exceptions[0].Type)
+	is.Equal("permission denied", seg.Cause.Exceptions[0].Message)
+	is.NotEmpty(seg.Cause.Exceptions[0].Stack)
+	is.Len(seg.Cause.Exceptions[0].ID, 16)
+
+	// no segment
+	Record(context.Background(), err)
+}
+
+func TestRecordSegmentStatus(t *testing.T) {
+	is := assert.New(t)
+
+	_, seg := beginSegment(t)
+	RecordSegment(seg, oops.HTTPStatus(http.StatusNotFound).Errorf("not found"))
+	is.True(seg.Error)
+	is.False(seg.Fault)
+	is.False(seg.Throttle)
+
+	_, seg = beginSegment(t)
+	RecordSegment(seg, oops.HTTPStatus(http.StatusTooManyRequests).Errorf("slow down"))
+	is.True(seg.Error)
+	is.True(seg.Throttle)
+
+	_, seg = beginSegment(t)
+	RecordSegment(seg, errors.New("secret"))
+	is.True(seg.Fault)
+	is.Len(seg.Cause.Exceptions, 1)
+	is.Empty(seg.Annotations)
+}